@@ -2,10 +2,12 @@ package account
 
 import (
 	"context"
+	"fmt"
 	"go-microservice/account/pb"
+	"go-microservice/platform"
+	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type Client struct {
@@ -13,8 +15,9 @@ type Client struct {
 	client pb.AccountServiceClient
 }
 
-func NewClient(url string) (*Client, error) {
-	conn, err := grpc.NewClient(url, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewClient подключается к сервису по url; без сертификатов в tlsConfig соединение не шифруется
+func NewClient(url string, tlsConfig platform.TLSConfig) (*Client, error) {
+	conn, err := platform.Dial(url, tlsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -26,12 +29,17 @@ func (c *Client) Close() {
 	c.conn.Close()
 }
 
+// Health проверяет сервис и его зависимости по grpc.health.v1
+func (c *Client) Health(ctx context.Context) error {
+	return platform.CheckConn(ctx, c.conn)
+}
+
 func (c *Client) PostAccount(ctx context.Context, name string) (*Account, error) {
 	res, err := c.client.PostAccount(ctx, &pb.PostAccountRequest{Name: name})
 	if err != nil {
 		return nil, err
 	}
-	return &Account{ID: res.Account.Id, Name: res.Account.Name}, nil
+	return fromProtoAccount(res.Account)
 }
 
 func (c *Client) GetAccount(ctx context.Context, id string) (*Account, error) {
@@ -39,7 +47,7 @@ func (c *Client) GetAccount(ctx context.Context, id string) (*Account, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Account{ID: res.Account.Id, Name: res.Account.Name}, nil
+	return fromProtoAccount(res.Account)
 }
 
 func (c *Client) GetAccounts(ctx context.Context, skip, take uint64) ([]Account, error) {
@@ -49,7 +57,114 @@ func (c *Client) GetAccounts(ctx context.Context, skip, take uint64) ([]Account,
 	}
 	accounts := make([]Account, len(res.Accounts))
 	for i, a := range res.Accounts {
-		accounts[i] = Account{ID: a.Id, Name: a.Name}
+		account, err := fromProtoAccount(a)
+		if err != nil {
+			return nil, err
+		}
+		accounts[i] = *account
 	}
 	return accounts, nil
 }
+
+// GetAccountsPage возвращает страницу аккаунтов после курсора after
+func (c *Client) GetAccountsPage(ctx context.Context, after string, first uint64) (*AccountPage, error) {
+	res, err := c.client.GetAccountsPage(ctx, &pb.GetAccountsPageRequest{After: after, First: first})
+	if err != nil {
+		return nil, err
+	}
+	page := &AccountPage{
+		Edges:       make([]AccountEdge, len(res.Edges)),
+		HasNextPage: res.HasNextPage,
+	}
+	for i, e := range res.Edges {
+		account, err := fromProtoAccount(e.Account)
+		if err != nil {
+			return nil, err
+		}
+		page.Edges[i] = AccountEdge{Account: *account, Cursor: e.Cursor}
+	}
+	return page, nil
+}
+
+func (c *Client) UpdateAccount(ctx context.Context, id, name string) (*Account, error) {
+	res, err := c.client.UpdateAccount(ctx, &pb.UpdateAccountRequest{Id: id, Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoAccount(res.Account)
+}
+
+func (c *Client) DeactivateAccount(ctx context.Context, id string) (*Account, error) {
+	res, err := c.client.DeactivateAccount(ctx, &pb.DeactivateAccountRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoAccount(res.Account)
+}
+
+func (c *Client) ReactivateAccount(ctx context.Context, id string) (*Account, error) {
+	res, err := c.client.ReactivateAccount(ctx, &pb.ReactivateAccountRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoAccount(res.Account)
+}
+
+func (c *Client) SetAccountRole(ctx context.Context, id string, role Role) (*Account, error) {
+	res, err := c.client.SetAccountRole(ctx, &pb.SetAccountRoleRequest{Id: id, Role: protoRoles[role]})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoAccount(res.Account)
+}
+
+func (c *Client) Register(ctx context.Context, name, email, password string) (*Account, error) {
+	res, err := c.client.Register(ctx, &pb.RegisterRequest{Name: name, Email: email, Password: password})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoAccount(res.Account)
+}
+
+func (c *Client) Login(ctx context.Context, email, password string) (*Session, error) {
+	res, err := c.client.Login(ctx, &pb.LoginRequest{Email: email, Password: password})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoSession(res.Session)
+}
+
+// RefreshToken обменивает refresh токен на новую сессию
+func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*Session, error) {
+	res, err := c.client.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: refreshToken})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoSession(res.Session)
+}
+
+func fromProtoSession(s *pb.Session) (*Session, error) {
+	account, err := fromProtoAccount(s.Account)
+	if err != nil {
+		return nil, err
+	}
+	var expiresAt time.Time
+	if err := expiresAt.UnmarshalBinary(s.RefreshExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal refreshExpiresAt: %w", err)
+	}
+	return &Session{Account: *account, RefreshToken: s.RefreshToken, RefreshExpiresAt: expiresAt}, nil
+}
+
+func fromProtoAccount(a *pb.Account) (*Account, error) {
+	var updatedAt time.Time
+	if err := updatedAt.UnmarshalBinary(a.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal updatedAt: %w", err)
+	}
+	return &Account{
+		ID:        a.Id,
+		Name:      a.Name,
+		Status:    fromProtoStatus(a.Status),
+		Role:      fromProtoRole(a.Role),
+		UpdatedAt: updatedAt,
+	}, nil
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"go-microservice/account"
+	"go-microservice/platform"
 	"log"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -10,6 +14,11 @@ import (
 
 type Config struct {
 	DatabaseURL string `envconfig:"DATABASE_URL"`
+	MetricsAddr string `envconfig:"METRICS_ADDR" default:":9090"`
+	platform.TLSConfig
+	platform.TracingConfig
+	platform.LogConfig
+	platform.EventsConfig
 }
 
 func main() {
@@ -19,19 +28,59 @@ func main() {
 		log.Fatal(err)
 	}
 
-	time.Sleep(10 * time.Second)
-	
-	r, err := account.NewPostgresReposytory(cfg.DatabaseURL)
+	logger, err := platform.NewLogger("account", cfg.LogConfig)
 	if err != nil {
-		log.Println(err)
-		return
+		log.Fatal(err)
 	}
-	defer r.Close()
+	slog.SetDefault(logger)
 
-	s := account.NewService(r)
+	ctx, stop := platform.SignalContext()
+	defer stop()
 
-	if err = account.ListenGRPC(s, 50051); err != nil {
-		log.Fatal(err)
+	if err := run(ctx, cfg, logger); err != nil {
+		logger.Error("Service failed", "error", err)
+		os.Exit(1)
 	}
-	log.Println("Listening on port 50051")
+	logger.Info("Stopped")
+}
+
+// run работает до отмены ctx. После остановки gRPC сервера останавливается relay событий,
+// затем закрывается репозиторий.
+func run(ctx context.Context, cfg Config, logger *slog.Logger) error {
+	shutdownTracing, err := platform.SetupTracing(ctx, "account", cfg.TracingConfig)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+
+	// Ждём, пока поднимется Postgres
+	startCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	var r account.Repository
+	err = platform.Retry(startCtx, "connect to postgres", func() (err error) {
+		r, err = account.NewPostgresReposytory(cfg.DatabaseURL)
+		return err
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	r = account.NewInstrumentedRepository(r, "postgres", logger)
+
+	// Relay останавливается раньше, чем закрывается репозиторий
+	stopRelay, err := platform.StartEventRelay(ctx, r, cfg.EventsConfig)
+	if err != nil {
+		return err
+	}
+	defer stopRelay()
+
+	go func() {
+		if err := platform.ServeMetrics(ctx, cfg.MetricsAddr); err != nil {
+			logger.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	s := account.NewService(r, logger)
+
+	return account.ListenGRPC(ctx, s, 50051, cfg.TLSConfig, logger, platform.HealthCheck{Name: "postgres", Check: r.Ping})
 }
@@ -0,0 +1,98 @@
+package account
+
+import (
+	"context"
+	"go-microservice/platform"
+	"log/slog"
+	"time"
+)
+
+// instrumentedRepository замеряет каждый запрос к хранилищу через platform.QueryObserver.
+// Close и Ping передаются как есть.
+type instrumentedRepository struct {
+	Repository
+	queries platform.QueryObserver
+}
+
+// NewInstrumentedRepository оборачивает r, store - имя хранилища в метриках и span (postgres, elasticsearch)
+func NewInstrumentedRepository(r Repository, store string, logger *slog.Logger) Repository {
+	return &instrumentedRepository{Repository: r, queries: platform.QueryObserver{Store: store, Logger: logger}}
+}
+
+func (r *instrumentedRepository) PutAccount(ctx context.Context, a Account, events ...platform.Event) error {
+	return platform.ObserveExec(ctx, r.queries, "PutAccount", func(ctx context.Context) error {
+		return r.Repository.PutAccount(ctx, a, events...)
+	})
+}
+
+func (r *instrumentedRepository) GetAccountById(ctx context.Context, id string) (*Account, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetAccountById", func(ctx context.Context) (*Account, error) {
+		return r.Repository.GetAccountById(ctx, id)
+	})
+}
+
+func (r *instrumentedRepository) ListAccounts(ctx context.Context, skip uint64, take uint64) ([]Account, error) {
+	return platform.ObserveQuery(ctx, r.queries, "ListAccounts", func(ctx context.Context) ([]Account, error) {
+		return r.Repository.ListAccounts(ctx, skip, take)
+	})
+}
+
+func (r *instrumentedRepository) ListAccountsAfter(ctx context.Context, after string, limit uint64) ([]Account, error) {
+	return platform.ObserveQuery(ctx, r.queries, "ListAccountsAfter", func(ctx context.Context) ([]Account, error) {
+		return r.Repository.ListAccountsAfter(ctx, after, limit)
+	})
+}
+
+func (r *instrumentedRepository) UpdateAccountName(ctx context.Context, id, name string, updatedAt time.Time) (*Account, error) {
+	return platform.ObserveQuery(ctx, r.queries, "UpdateAccountName", func(ctx context.Context) (*Account, error) {
+		return r.Repository.UpdateAccountName(ctx, id, name, updatedAt)
+	})
+}
+
+func (r *instrumentedRepository) UpdateAccountStatus(ctx context.Context, id string, status AccountStatus, updatedAt time.Time) (*Account, error) {
+	return platform.ObserveQuery(ctx, r.queries, "UpdateAccountStatus", func(ctx context.Context) (*Account, error) {
+		return r.Repository.UpdateAccountStatus(ctx, id, status, updatedAt)
+	})
+}
+
+func (r *instrumentedRepository) UpdateAccountRole(ctx context.Context, id string, role Role, updatedAt time.Time) (*Account, error) {
+	return platform.ObserveQuery(ctx, r.queries, "UpdateAccountRole", func(ctx context.Context) (*Account, error) {
+		return r.Repository.UpdateAccountRole(ctx, id, role, updatedAt)
+	})
+}
+
+func (r *instrumentedRepository) PutAccountWithCredentials(ctx context.Context, a Account, c Credentials, events ...platform.Event) error {
+	return platform.ObserveExec(ctx, r.queries, "PutAccountWithCredentials", func(ctx context.Context) error {
+		return r.Repository.PutAccountWithCredentials(ctx, a, c, events...)
+	})
+}
+
+func (r *instrumentedRepository) GetCredentialsByEmail(ctx context.Context, email string) (*Credentials, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetCredentialsByEmail", func(ctx context.Context) (*Credentials, error) {
+		return r.Repository.GetCredentialsByEmail(ctx, email)
+	})
+}
+
+func (r *instrumentedRepository) PutRefreshToken(ctx context.Context, t RefreshToken) error {
+	return platform.ObserveExec(ctx, r.queries, "PutRefreshToken", func(ctx context.Context) error {
+		return r.Repository.PutRefreshToken(ctx, t)
+	})
+}
+
+func (r *instrumentedRepository) TakeRefreshToken(ctx context.Context, hash string) (*RefreshToken, error) {
+	return platform.ObserveQuery(ctx, r.queries, "TakeRefreshToken", func(ctx context.Context) (*RefreshToken, error) {
+		return r.Repository.TakeRefreshToken(ctx, hash)
+	})
+}
+
+func (r *instrumentedRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	return platform.ObserveQuery(ctx, r.queries, "PendingEvents", func(ctx context.Context) ([]platform.Event, error) {
+		return r.Repository.PendingEvents(ctx, limit)
+	})
+}
+
+func (r *instrumentedRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	return platform.ObserveExec(ctx, r.queries, "DeleteEvents", func(ctx context.Context) error {
+		return r.Repository.DeleteEvents(ctx, ids)
+	})
+}
@@ -0,0 +1,209 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"go-microservice/platform"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// inMemoryRepository хранит аккаунты в памяти процесса.
+// Используется в тестах и для локального запуска без Postgres.
+type inMemoryRepository struct {
+	mu            sync.RWMutex
+	accounts      map[string]Account
+	credentials   map[string]Credentials // по email
+	refreshTokens map[string]RefreshToken
+	// outbox - неопубликованные события в порядке записи
+	outbox []platform.Event
+}
+
+func NewInMemoryRepository() Repository {
+	return &inMemoryRepository{
+		accounts:      make(map[string]Account),
+		credentials:   make(map[string]Credentials),
+		refreshTokens: make(map[string]RefreshToken),
+	}
+}
+
+// Close implements Repository.
+func (r *inMemoryRepository) Close() {}
+
+// Ping implements Repository.
+func (r *inMemoryRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+// PutAccount implements Repository.
+func (r *inMemoryRepository) PutAccount(ctx context.Context, a Account, events ...platform.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.accounts[a.ID]; exists {
+		return fmt.Errorf("account %s already exists", a.ID)
+	}
+	r.accounts[a.ID] = a
+	r.outbox = append(r.outbox, events...)
+	return nil
+}
+
+// GetAccountById implements Repository.
+func (r *inMemoryRepository) GetAccountById(ctx context.Context, id string) (*Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.accounts[id]
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+	return &a, nil
+}
+
+// UpdateAccountName implements Repository.
+func (r *inMemoryRepository) UpdateAccountName(ctx context.Context, id, name string, updatedAt time.Time) (*Account, error) {
+	return r.update(id, updatedAt, func(a *Account) { a.Name = name })
+}
+
+// UpdateAccountStatus implements Repository.
+func (r *inMemoryRepository) UpdateAccountStatus(ctx context.Context, id string, status AccountStatus, updatedAt time.Time) (*Account, error) {
+	return r.update(id, updatedAt, func(a *Account) { a.Status = status })
+}
+
+// UpdateAccountRole implements Repository.
+func (r *inMemoryRepository) UpdateAccountRole(ctx context.Context, id string, role Role, updatedAt time.Time) (*Account, error) {
+	return r.update(id, updatedAt, func(a *Account) { a.Role = role })
+}
+
+func (r *inMemoryRepository) update(id string, updatedAt time.Time, change func(a *Account)) (*Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, exists := r.accounts[id]
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+	change(&a)
+	a.UpdatedAt = updatedAt
+	r.accounts[id] = a
+	return &a, nil
+}
+
+// ListAccounts implements Repository.
+// Порядок совпадает с Postgres: по id в обратном порядке.
+func (r *inMemoryRepository) ListAccounts(ctx context.Context, skip uint64, take uint64) ([]Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	accounts := make([]Account, 0, len(r.accounts))
+	for _, a := range r.accounts {
+		accounts = append(accounts, a)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID > accounts[j].ID
+	})
+
+	if skip >= uint64(len(accounts)) {
+		return []Account{}, nil
+	}
+	accounts = accounts[skip:]
+	if take < uint64(len(accounts)) {
+		accounts = accounts[:take]
+	}
+	return accounts, nil
+}
+
+// ListAccountsAfter implements Repository.
+func (r *inMemoryRepository) ListAccountsAfter(ctx context.Context, after string, limit uint64) ([]Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	accounts := make([]Account, 0, len(r.accounts))
+	for _, a := range r.accounts {
+		if after == "" || a.ID < after {
+			accounts = append(accounts, a)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID > accounts[j].ID
+	})
+	if limit < uint64(len(accounts)) {
+		accounts = accounts[:limit]
+	}
+	return accounts, nil
+}
+
+// PutAccountWithCredentials implements Repository.
+func (r *inMemoryRepository) PutAccountWithCredentials(ctx context.Context, a Account, c Credentials, events ...platform.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.accounts[a.ID]; exists {
+		return fmt.Errorf("account %s already exists", a.ID)
+	}
+	if _, exists := r.credentials[c.Email]; exists {
+		return ErrEmailTaken
+	}
+	r.accounts[a.ID] = a
+	r.credentials[c.Email] = c
+	r.outbox = append(r.outbox, events...)
+	return nil
+}
+
+// GetCredentialsByEmail implements Repository.
+func (r *inMemoryRepository) GetCredentialsByEmail(ctx context.Context, email string) (*Credentials, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.credentials[email]
+	if !ok {
+		return nil, ErrCredentialsNotFound
+	}
+	return &c, nil
+}
+
+// PutRefreshToken implements Repository.
+func (r *inMemoryRepository) PutRefreshToken(ctx context.Context, t RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.refreshTokens[t.Hash]; exists {
+		return fmt.Errorf("refresh token already exists")
+	}
+	r.refreshTokens[t.Hash] = t
+	return nil
+}
+
+// TakeRefreshToken implements Repository.
+func (r *inMemoryRepository) TakeRefreshToken(ctx context.Context, hash string) (*RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.refreshTokens[hash]
+	if !ok {
+		return nil, ErrRefreshTokenNotFound
+	}
+	delete(r.refreshTokens, hash)
+	return &t, nil
+}
+
+// PendingEvents implements Repository.
+func (r *inMemoryRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]platform.Event{}, r.outbox[:min(limit, len(r.outbox))]...), nil
+}
+
+// DeleteEvents implements Repository.
+func (r *inMemoryRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.outbox = slices.DeleteFunc(r.outbox, func(e platform.Event) bool {
+		return slices.Contains(ids, e.ID)
+	})
+	return nil
+}
@@ -3,15 +3,64 @@ package account
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"go-microservice/platform"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+var (
+	ErrAccountNotFound      = platform.NewError(platform.NotFound, "ACCOUNT_NOT_FOUND", "account not found")
+	ErrEmailTaken           = platform.NewError(platform.AlreadyExists, "EMAIL_TAKEN", "email is already registered")
+	ErrCredentialsNotFound  = platform.NewError(platform.NotFound, "CREDENTIALS_NOT_FOUND", "credentials not found")
+	ErrRefreshTokenNotFound = platform.NewError(platform.NotFound, "REFRESH_TOKEN_NOT_FOUND", "refresh token not found")
+)
+
+// uniqueViolation - код ошибки Postgres при нарушении UNIQUE
+const uniqueViolation = "23505"
+
+// Credentials - данные для входа в аккаунт. Пароль хранится только в виде bcrypt хеша.
+type Credentials struct {
+	AccountID    string
+	Email        string
+	PasswordHash string
+}
+
+// RefreshToken - выданный refresh токен. Сам токен не хранится, только его SHA-256.
+type RefreshToken struct {
+	Hash      string
+	AccountID string
+	ExpiresAt time.Time
+}
+
 type Repository interface {
 	Close()
-	PutAccount(ctx context.Context, a Account) error
+	// Ping проверяет, что хранилище доступно
+	Ping(ctx context.Context) error
+	// PutAccount создаёт аккаунт и в той же транзакции записывает events в outbox
+	PutAccount(ctx context.Context, a Account, events ...platform.Event) error
 	GetAccountById(ctx context.Context, id string) (*Account, error)
 	ListAccounts(ctx context.Context, skip uint64, take uint64) ([]Account, error)
+	// ListAccountsAfter возвращает до limit аккаунтов с id меньше after в порядке убывания id.
+	// KSUID упорядочены по времени создания, поэтому это страница от новых к старым.
+	ListAccountsAfter(ctx context.Context, after string, limit uint64) ([]Account, error)
+	// UpdateAccountName, UpdateAccountStatus и UpdateAccountRole меняют одно поле и время изменения
+	// и возвращают аккаунт после изменения. Остальные поля не перезаписываются,
+	// поэтому параллельные изменения разных полей не теряются.
+	UpdateAccountName(ctx context.Context, id, name string, updatedAt time.Time) (*Account, error)
+	UpdateAccountStatus(ctx context.Context, id string, status AccountStatus, updatedAt time.Time) (*Account, error)
+	UpdateAccountRole(ctx context.Context, id string, role Role, updatedAt time.Time) (*Account, error)
+	// PutAccountWithCredentials создаёт аккаунт вместе с данными для входа.
+	// Если email уже занят, не создаётся ничего и возвращается ErrEmailTaken.
+	PutAccountWithCredentials(ctx context.Context, a Account, c Credentials, events ...platform.Event) error
+	GetCredentialsByEmail(ctx context.Context, email string) (*Credentials, error)
+	PutRefreshToken(ctx context.Context, t RefreshToken) error
+	// TakeRefreshToken удаляет токен и возвращает его, так что один токен можно использовать только раз.
+	// Срок действия не проверяется.
+	TakeRefreshToken(ctx context.Context, hash string) (*RefreshToken, error)
+	platform.OutboxStore
 }
 
 type postgresRepository struct {
@@ -26,6 +75,8 @@ func NewPostgresReposytory(url string) (*postgresRepository, error) {
 
 	err = db.Ping()
 	if err != nil {
+		// Конструктор повторяется при старте, пул незапустившейся попытки нужно закрыть
+		db.Close()
 		return nil, err
 	}
 
@@ -36,22 +87,68 @@ func (r *postgresRepository) Close() {
 	r.db.Close()
 }
 
-func (r *postgresRepository) Ping() error {
-	return r.db.Ping()
+// Ping implements Repository.
+func (r *postgresRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
 }
 
-func (r *postgresRepository) PutAccount(ctx context.Context, a Account) error {
-	_, err := r.db.ExecContext(ctx, "INSERT INTO accounts(id,name) VALUES($1,$2)", a.ID, a.Name)
+func (r *postgresRepository) PutAccount(ctx context.Context, a Account, events ...platform.Event) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	return nil
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO accounts(id,name,status,role,updated_at) VALUES($1,$2,$3,$4,$5)", a.ID, a.Name, a.Status, a.Role, a.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if err = platform.InsertEvents(ctx, tx, events); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateAccountName implements Repository.
+func (r *postgresRepository) UpdateAccountName(ctx context.Context, id, name string, updatedAt time.Time) (*Account, error) {
+	return r.updateColumn(ctx, id, "name", name, updatedAt)
+}
+
+// UpdateAccountStatus implements Repository.
+func (r *postgresRepository) UpdateAccountStatus(ctx context.Context, id string, status AccountStatus, updatedAt time.Time) (*Account, error) {
+	return r.updateColumn(ctx, id, "status", status, updatedAt)
+}
+
+// UpdateAccountRole implements Repository.
+func (r *postgresRepository) UpdateAccountRole(ctx context.Context, id string, role Role, updatedAt time.Time) (*Account, error) {
+	return r.updateColumn(ctx, id, "role", role, updatedAt)
+}
+
+// updateColumn записывает value в column и updated_at. column подставляется в запрос как есть,
+// поэтому передаётся только константой.
+func (r *postgresRepository) updateColumn(ctx context.Context, id, column string, value interface{}, updatedAt time.Time) (*Account, error) {
+	var a Account
+	result := r.db.QueryRowContext(ctx, "UPDATE accounts SET "+column+"=$2, updated_at=$3 WHERE id=$1 RETURNING id, name, status, role, updated_at", id, value, updatedAt)
+	if err := result.Scan(&a.ID, &a.Name, &a.Status, &a.Role, &a.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
 }
 
 func (r *postgresRepository) GetAccountById(ctx context.Context, id string) (*Account, error) {
 	var a Account
-	result := r.db.QueryRowContext(ctx, "SELECT id, name FROM accounts WHERE id = $1", id)
-	if err := result.Scan(&a.ID, &a.Name); err != nil {
+	result := r.db.QueryRowContext(ctx, "SELECT id, name, status, role, updated_at FROM accounts WHERE id = $1", id)
+	if err := result.Scan(&a.ID, &a.Name, &a.Status, &a.Role, &a.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
 		return nil, err
 	}
 	return &a, nil
@@ -60,7 +157,7 @@ func (r *postgresRepository) GetAccountById(ctx context.Context, id string) (*Ac
 func (r *postgresRepository) ListAccounts(ctx context.Context, skip, take uint64) ([]Account, error) {
 	rows, err := r.db.QueryContext(
 		ctx,
-		"SELECT id, name FROM accounts ORDER BY id DESC OFFSET $1 LIMIT $2",
+		"SELECT id, name, status, role, updated_at FROM accounts ORDER BY id DESC OFFSET $1 LIMIT $2",
 		skip,
 		take,
 	)
@@ -73,9 +170,10 @@ func (r *postgresRepository) ListAccounts(ctx context.Context, skip, take uint64
 	var accounts []Account
 	for rows.Next() {
 		var acc Account
-		if err := rows.Scan(&acc.ID, &acc.Name); err == nil {
-			accounts = append(accounts, acc)
+		if err := rows.Scan(&acc.ID, &acc.Name, &acc.Status, &acc.Role, &acc.UpdatedAt); err != nil {
+			return nil, err
 		}
+		accounts = append(accounts, acc)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -84,3 +182,103 @@ func (r *postgresRepository) ListAccounts(ctx context.Context, skip, take uint64
 
 	return accounts, nil
 }
+
+func (r *postgresRepository) ListAccountsAfter(ctx context.Context, after string, limit uint64) ([]Account, error) {
+	query := "SELECT id, name, status, role, updated_at FROM accounts"
+	args := []any{}
+	if after != "" {
+		query += " WHERE id < $1"
+		args = append(args, after)
+	}
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args)+1)
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []Account{}
+	for rows.Next() {
+		var a Account
+		if err := rows.Scan(&a.ID, &a.Name, &a.Status, &a.Role, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *postgresRepository) PutAccountWithCredentials(ctx context.Context, a Account, c Credentials, events ...platform.Event) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO accounts(id,name,status,role,updated_at) VALUES($1,$2,$3,$4,$5)", a.ID, a.Name, a.Status, a.Role, a.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO credentials(account_id,email,password_hash) VALUES($1,$2,$3)", c.AccountID, c.Email, c.PasswordHash)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return ErrEmailTaken
+	}
+	if err != nil {
+		return err
+	}
+	if err = platform.InsertEvents(ctx, tx, events); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PendingEvents implements Repository.
+func (r *postgresRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	return platform.PendingEvents(ctx, r.db, limit)
+}
+
+// DeleteEvents implements Repository.
+func (r *postgresRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	return platform.DeleteEvents(ctx, r.db, ids)
+}
+
+func (r *postgresRepository) GetCredentialsByEmail(ctx context.Context, email string) (*Credentials, error) {
+	var c Credentials
+	row := r.db.QueryRowContext(ctx, "SELECT account_id, email, password_hash FROM credentials WHERE email = $1", email)
+	if err := row.Scan(&c.AccountID, &c.Email, &c.PasswordHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCredentialsNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *postgresRepository) PutRefreshToken(ctx context.Context, t RefreshToken) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO refresh_tokens(token_hash,account_id,expires_at) VALUES($1,$2,$3)", t.Hash, t.AccountID, t.ExpiresAt)
+	return err
+}
+
+// TakeRefreshToken implements Repository.
+// DELETE ... RETURNING атомарен, поэтому два параллельных обмена одного токена не пройдут оба.
+func (r *postgresRepository) TakeRefreshToken(ctx context.Context, hash string) (*RefreshToken, error) {
+	var t RefreshToken
+	row := r.db.QueryRowContext(ctx, "DELETE FROM refresh_tokens WHERE token_hash = $1 RETURNING token_hash, account_id, expires_at", hash)
+	if err := row.Scan(&t.Hash, &t.AccountID, &t.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
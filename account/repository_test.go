@@ -0,0 +1,292 @@
+package account
+
+import (
+	"context"
+	"errors"
+	eventspb "go-microservice/events/pb"
+	"go-microservice/platform"
+	"go-microservice/platform/storetest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// TestRepositoryContract гоняет контрактные тесты на всех реализациях Repository.
+// Postgres подключается только если задан TEST_ACCOUNT_DATABASE_URL (схема из up.sql должна быть применена).
+func TestRepositoryContract(t *testing.T) {
+	tests := []storetest.Test[Repository]{
+		{Name: "put and get", Run: testPutAndGetAccount},
+		{Name: "get missing", Run: testGetMissingAccount},
+		{Name: "put duplicate", Run: testPutDuplicateAccount},
+		{Name: "list pagination", Run: testListAccounts},
+		{Name: "list after", Run: testListAccountsAfter},
+		{Name: "update", Run: testUpdateAccount},
+		{Name: "credentials", Run: testCredentials},
+		{Name: "refresh tokens", Run: testRefreshTokens},
+		{Name: "outbox", Run: testOutbox},
+	}
+	storetest.RunContract(t, tests, func() Repository { return NewInMemoryRepository() }, storetest.Store[Repository]{
+		Name: "postgres",
+		Env:  "TEST_ACCOUNT_DATABASE_URL",
+		Open: func(t *testing.T, url string) Repository {
+			r, err := NewPostgresReposytory(url)
+			if err != nil {
+				t.Fatalf("connect to postgres: %v", err)
+			}
+			if _, err := r.db.Exec("TRUNCATE accounts, credentials, refresh_tokens, outbox"); err != nil {
+				t.Fatalf("truncate accounts: %v", err)
+			}
+			t.Cleanup(r.Close)
+			return r
+		},
+	})
+}
+
+func newAccount(name string) Account {
+	// Postgres хранит время с точностью до микросекунд
+	return Account{
+		ID:        ksuid.New().String(),
+		Name:      name,
+		Status:    StatusActive,
+		Role:      RoleCustomer,
+		UpdatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+func assertAccount(t *testing.T, got, want Account) {
+	t.Helper()
+	if got.ID != want.ID || got.Name != want.Name || got.Status != want.Status || got.Role != want.Role || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("account = %+v, want %+v", got, want)
+	}
+}
+
+func testPutAndGetAccount(t *testing.T, r Repository) {
+	ctx := context.Background()
+	want := newAccount("alice")
+
+	if err := r.PutAccount(ctx, want); err != nil {
+		t.Fatalf("PutAccount: %v", err)
+	}
+
+	got, err := r.GetAccountById(ctx, want.ID)
+	if err != nil {
+		t.Fatalf("GetAccountById: %v", err)
+	}
+	assertAccount(t, *got, want)
+}
+
+func testGetMissingAccount(t *testing.T, r Repository) {
+	_, err := r.GetAccountById(context.Background(), ksuid.New().String())
+	if !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("GetAccountById error = %v, want %v", err, ErrAccountNotFound)
+	}
+}
+
+func testPutDuplicateAccount(t *testing.T, r Repository) {
+	ctx := context.Background()
+	a := newAccount("bob")
+
+	if err := r.PutAccount(ctx, a); err != nil {
+		t.Fatalf("PutAccount: %v", err)
+	}
+	if err := r.PutAccount(ctx, a); err == nil {
+		t.Error("PutAccount with duplicate id succeeded, want error")
+	}
+}
+
+func testListAccounts(t *testing.T, r Repository) {
+	ctx := context.Background()
+
+	ids := make([]string, 5)
+	for i := range ids {
+		a := newAccount("user")
+		ids[i] = a.ID
+		if err := r.PutAccount(ctx, a); err != nil {
+			t.Fatalf("PutAccount: %v", err)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	tests := []struct {
+		skip, take uint64
+		want       []string
+	}{
+		{skip: 0, take: 2, want: ids[0:2]},
+		{skip: 2, take: 2, want: ids[2:4]},
+		{skip: 4, take: 2, want: ids[4:5]},
+		{skip: 5, take: 2, want: nil},
+		{skip: 0, take: 100, want: ids},
+	}
+	for _, tt := range tests {
+		got, err := r.ListAccounts(ctx, tt.skip, tt.take)
+		if err != nil {
+			t.Fatalf("ListAccounts(%d, %d): %v", tt.skip, tt.take, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("ListAccounts(%d, %d) returned %d accounts, want %d", tt.skip, tt.take, len(got), len(tt.want))
+		}
+		for i := range got {
+			if got[i].ID != tt.want[i] {
+				t.Errorf("ListAccounts(%d, %d)[%d] = %s, want %s", tt.skip, tt.take, i, got[i].ID, tt.want[i])
+			}
+		}
+	}
+}
+
+func testListAccountsAfter(t *testing.T, r Repository) {
+	ctx := context.Background()
+
+	ids := make([]string, 5)
+	for i := range ids {
+		a := newAccount("user")
+		ids[i] = a.ID
+		if err := r.PutAccount(ctx, a); err != nil {
+			t.Fatalf("PutAccount: %v", err)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	tests := []struct {
+		after string
+		limit uint64
+		want  []string
+	}{
+		{after: "", limit: 2, want: ids[0:2]},
+		{after: ids[1], limit: 2, want: ids[2:4]},
+		{after: ids[3], limit: 2, want: ids[4:5]},
+		{after: ids[4], limit: 2, want: nil},
+	}
+	for _, tt := range tests {
+		got, err := r.ListAccountsAfter(ctx, tt.after, tt.limit)
+		if err != nil {
+			t.Fatalf("ListAccountsAfter(%q, %d): %v", tt.after, tt.limit, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("ListAccountsAfter(%q, %d) returned %d accounts, want %d", tt.after, tt.limit, len(got), len(tt.want))
+		}
+		for i := range got {
+			if got[i].ID != tt.want[i] {
+				t.Errorf("ListAccountsAfter(%q, %d)[%d] = %s, want %s", tt.after, tt.limit, i, got[i].ID, tt.want[i])
+			}
+		}
+	}
+}
+
+func testUpdateAccount(t *testing.T, r Repository) {
+	ctx := context.Background()
+	want := newAccount("carol")
+	if err := r.PutAccount(ctx, want); err != nil {
+		t.Fatalf("PutAccount: %v", err)
+	}
+
+	// Каждое изменение трогает только своё поле, так что предыдущие сохраняются
+	updates := []struct {
+		name   string
+		change func(a *Account)
+		update func(updatedAt time.Time) (*Account, error)
+	}{
+		{"name", func(a *Account) { a.Name = "caroline" }, func(at time.Time) (*Account, error) {
+			return r.UpdateAccountName(ctx, want.ID, "caroline", at)
+		}},
+		{"status", func(a *Account) { a.Status = StatusDeactivated }, func(at time.Time) (*Account, error) {
+			return r.UpdateAccountStatus(ctx, want.ID, StatusDeactivated, at)
+		}},
+		{"role", func(a *Account) { a.Role = RoleMerchandiser }, func(at time.Time) (*Account, error) {
+			return r.UpdateAccountRole(ctx, want.ID, RoleMerchandiser, at)
+		}},
+	}
+	for _, u := range updates {
+		u.change(&want)
+		want.UpdatedAt = want.UpdatedAt.Add(time.Minute)
+		updated, err := u.update(want.UpdatedAt)
+		if err != nil {
+			t.Fatalf("update %s: %v", u.name, err)
+		}
+		assertAccount(t, *updated, want)
+	}
+
+	got, err := r.GetAccountById(ctx, want.ID)
+	if err != nil {
+		t.Fatalf("GetAccountById: %v", err)
+	}
+	assertAccount(t, *got, want)
+
+	missing := ksuid.New().String()
+	if _, err := r.UpdateAccountName(ctx, missing, "nobody", time.Now()); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("UpdateAccountName missing error = %v, want %v", err, ErrAccountNotFound)
+	}
+	if _, err := r.UpdateAccountStatus(ctx, missing, StatusActive, time.Now()); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("UpdateAccountStatus missing error = %v, want %v", err, ErrAccountNotFound)
+	}
+	if _, err := r.UpdateAccountRole(ctx, missing, RoleAdmin, time.Now()); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("UpdateAccountRole missing error = %v, want %v", err, ErrAccountNotFound)
+	}
+}
+
+func testCredentials(t *testing.T, r Repository) {
+	ctx := context.Background()
+	a := newAccount("dave")
+	c := Credentials{AccountID: a.ID, Email: "dave@example.com", PasswordHash: "hash"}
+	if err := r.PutAccountWithCredentials(ctx, a, c); err != nil {
+		t.Fatalf("PutAccountWithCredentials: %v", err)
+	}
+
+	got, err := r.GetCredentialsByEmail(ctx, c.Email)
+	if err != nil {
+		t.Fatalf("GetCredentialsByEmail: %v", err)
+	}
+	if *got != c {
+		t.Errorf("credentials = %+v, want %+v", *got, c)
+	}
+	if _, err := r.GetCredentialsByEmail(ctx, "nobody@example.com"); !errors.Is(err, ErrCredentialsNotFound) {
+		t.Errorf("GetCredentialsByEmail missing error = %v, want %v", err, ErrCredentialsNotFound)
+	}
+
+	// Занятый email не создаёт и аккаунт
+	other := newAccount("dave2")
+	err = r.PutAccountWithCredentials(ctx, other, Credentials{AccountID: other.ID, Email: c.Email, PasswordHash: "hash"})
+	if !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("PutAccountWithCredentials with taken email error = %v, want %v", err, ErrEmailTaken)
+	}
+	if _, err := r.GetAccountById(ctx, other.ID); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("GetAccountById after failed registration error = %v, want %v", err, ErrAccountNotFound)
+	}
+}
+
+func testRefreshTokens(t *testing.T, r Repository) {
+	ctx := context.Background()
+	a := newAccount("erin")
+	if err := r.PutAccount(ctx, a); err != nil {
+		t.Fatalf("PutAccount: %v", err)
+	}
+
+	want := RefreshToken{
+		Hash:      strings.Repeat("a", 64),
+		AccountID: a.ID,
+		ExpiresAt: time.Now().UTC().Truncate(time.Microsecond).Add(time.Hour),
+	}
+	if err := r.PutRefreshToken(ctx, want); err != nil {
+		t.Fatalf("PutRefreshToken: %v", err)
+	}
+
+	got, err := r.TakeRefreshToken(ctx, want.Hash)
+	if err != nil {
+		t.Fatalf("TakeRefreshToken: %v", err)
+	}
+	if got.Hash != want.Hash || got.AccountID != want.AccountID || !got.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("refresh token = %+v, want %+v", *got, want)
+	}
+	if _, err := r.TakeRefreshToken(ctx, want.Hash); !errors.Is(err, ErrRefreshTokenNotFound) {
+		t.Errorf("second TakeRefreshToken error = %v, want %v", err, ErrRefreshTokenNotFound)
+	}
+}
+
+func testOutbox(t *testing.T, r Repository) {
+	a := newAccount("alice")
+	storetest.CheckOutbox(t, r, a.ID, &eventspb.AccountCreated{AccountId: a.ID}, func(events ...platform.Event) error {
+		return r.PutAccount(context.Background(), a, events...)
+	})
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"go-microservice/account/pb"
+	"go-microservice/platform"
+	"log/slog"
 	"net"
 
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 type grpcServer struct {
@@ -15,15 +18,22 @@ type grpcServer struct {
 	pb.UnimplementedAccountServiceServer
 }
 
-func ListenGRPC(s Service, port int) error {
+// ListenGRPC обслуживает s на port, пока не отменён ctx, и дожидается текущих вызовов.
+// С сертификатами в tlsConfig сервер принимает только TLS (mTLS, если задан CA),
+// без них - plaintext. checks отдаются по grpc.health.v1, вызовы пишутся в logger.
+func ListenGRPC(ctx context.Context, s Service, port int, tlsConfig platform.TLSConfig, logger *slog.Logger, checks ...platform.HealthCheck) error {
+	serv, err := platform.NewServer(tlsConfig, logger)
+	if err != nil {
+		return err
+	}
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return err
 	}
-	serv := grpc.NewServer()
 	pb.RegisterAccountServiceServer(serv, &grpcServer{service: s})
+	platform.RegisterHealth(serv, pb.AccountService_ServiceDesc.ServiceName, checks...)
 	reflection.Register(serv)
-	return serv.Serve(lis)
+	return platform.Serve(ctx, serv, lis)
 }
 
 func (s *grpcServer) PostAccount(ctx context.Context, r *pb.PostAccountRequest) (*pb.PostAccountResponse, error) {
@@ -31,11 +41,11 @@ func (s *grpcServer) PostAccount(ctx context.Context, r *pb.PostAccountRequest)
 	if err != nil {
 		return nil, err
 	}
-	return &pb.PostAccountResponse{
-		Account: &pb.Account{
-			Id:   a.ID,
-			Name: a.Name,
-		}}, nil
+	account, err := toProtoAccount(a)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PostAccountResponse{Account: account}, nil
 }
 
 func (s *grpcServer) GetAccount(ctx context.Context, r *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
@@ -43,7 +53,11 @@ func (s *grpcServer) GetAccount(ctx context.Context, r *pb.GetAccountRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	return &pb.GetAccountResponse{Account: &pb.Account{Id: a.ID, Name: a.Name}}, nil
+	account, err := toProtoAccount(a)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetAccountResponse{Account: account}, nil
 }
 
 func (s *grpcServer) GetAccounts(ctx context.Context, r *pb.GetAccountsRequest) (*pb.GetAccountsResponse, error) {
@@ -54,7 +68,181 @@ func (s *grpcServer) GetAccounts(ctx context.Context, r *pb.GetAccountsRequest)
 	//accounts:= []*pb.Account{}
 	accounts := make([]*pb.Account, len(accs))
 	for i, a := range accs {
-		accounts[i] = &pb.Account{Id: a.ID, Name: a.Name}
+		if accounts[i], err = toProtoAccount(&a); err != nil {
+			return nil, err
+		}
 	}
 	return &pb.GetAccountsResponse{Accounts: accounts}, nil
 }
+
+func (s *grpcServer) GetAccountsPage(ctx context.Context, r *pb.GetAccountsPageRequest) (*pb.GetAccountsPageResponse, error) {
+	page, err := s.service.GetAccountsPage(ctx, r.After, r.First)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*pb.AccountEdge, len(page.Edges))
+	for i, e := range page.Edges {
+		account, err := toProtoAccount(&e.Account)
+		if err != nil {
+			return nil, err
+		}
+		edges[i] = &pb.AccountEdge{Account: account, Cursor: e.Cursor}
+	}
+	return &pb.GetAccountsPageResponse{Edges: edges, HasNextPage: page.HasNextPage}, nil
+}
+
+func (s *grpcServer) UpdateAccount(ctx context.Context, r *pb.UpdateAccountRequest) (*pb.UpdateAccountResponse, error) {
+	if r.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	a, err := s.service.UpdateAccount(ctx, r.Id, r.Name)
+	if err != nil {
+		return nil, err
+	}
+	account, err := toProtoAccount(a)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UpdateAccountResponse{Account: account}, nil
+}
+
+func (s *grpcServer) DeactivateAccount(ctx context.Context, r *pb.DeactivateAccountRequest) (*pb.DeactivateAccountResponse, error) {
+	if r.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	a, err := s.service.DeactivateAccount(ctx, r.Id)
+	if err != nil {
+		return nil, err
+	}
+	account, err := toProtoAccount(a)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DeactivateAccountResponse{Account: account}, nil
+}
+
+func (s *grpcServer) ReactivateAccount(ctx context.Context, r *pb.ReactivateAccountRequest) (*pb.ReactivateAccountResponse, error) {
+	if r.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	a, err := s.service.ReactivateAccount(ctx, r.Id)
+	if err != nil {
+		return nil, err
+	}
+	account, err := toProtoAccount(a)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReactivateAccountResponse{Account: account}, nil
+}
+
+func (s *grpcServer) SetAccountRole(ctx context.Context, r *pb.SetAccountRoleRequest) (*pb.SetAccountRoleResponse, error) {
+	if r.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	a, err := s.service.SetRole(ctx, r.Id, fromProtoRole(r.Role))
+	if err != nil {
+		return nil, err
+	}
+	account, err := toProtoAccount(a)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetAccountRoleResponse{Account: account}, nil
+}
+
+func (s *grpcServer) Register(ctx context.Context, r *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	a, err := s.service.Register(ctx, r.Name, r.Email, r.Password)
+	if err != nil {
+		return nil, err
+	}
+	account, err := toProtoAccount(a)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RegisterResponse{Account: account}, nil
+}
+
+func (s *grpcServer) Login(ctx context.Context, r *pb.LoginRequest) (*pb.LoginResponse, error) {
+	session, err := s.service.Login(ctx, r.Email, r.Password)
+	if err != nil {
+		return nil, err
+	}
+	pbSession, err := toProtoSession(session)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.LoginResponse{Session: pbSession}, nil
+}
+
+func (s *grpcServer) RefreshToken(ctx context.Context, r *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	session, err := s.service.RefreshToken(ctx, r.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	pbSession, err := toProtoSession(session)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RefreshTokenResponse{Session: pbSession}, nil
+}
+
+var protoStatuses = map[AccountStatus]pb.AccountStatus{
+	StatusActive:      pb.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+	StatusDeactivated: pb.AccountStatus_ACCOUNT_STATUS_DEACTIVATED,
+}
+
+var protoRoles = map[Role]pb.AccountRole{
+	RoleCustomer:     pb.AccountRole_ACCOUNT_ROLE_CUSTOMER,
+	RoleMerchandiser: pb.AccountRole_ACCOUNT_ROLE_MERCHANDISER,
+	RoleAdmin:        pb.AccountRole_ACCOUNT_ROLE_ADMIN,
+}
+
+func toProtoAccount(a *Account) (*pb.Account, error) {
+	updatedAt, err := a.UpdatedAt.MarshalBinary()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal updatedAt: %v", err)
+	}
+	return &pb.Account{
+		Id:        a.ID,
+		Name:      a.Name,
+		Status:    protoStatuses[a.Status],
+		Role:      protoRoles[a.Role],
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+func toProtoSession(s *Session) (*pb.Session, error) {
+	account, err := toProtoAccount(&s.Account)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := s.RefreshExpiresAt.MarshalBinary()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal refreshExpiresAt: %v", err)
+	}
+	return &pb.Session{Account: account, RefreshToken: s.RefreshToken, RefreshExpiresAt: expiresAt}, nil
+}
+
+func fromProtoStatus(s pb.AccountStatus) AccountStatus {
+	for status, v := range protoStatuses {
+		if v == s {
+			return status
+		}
+	}
+	return ""
+}
+
+func fromProtoRole(r pb.AccountRole) Role {
+	for role, v := range protoRoles {
+		if v == r {
+			return role
+		}
+	}
+	return ""
+}
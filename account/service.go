@@ -2,42 +2,174 @@ package account
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	eventspb "go-microservice/events/pb"
+	"go-microservice/platform"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/segmentio/ksuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidName        = platform.NewError(platform.InvalidArgument, "INVALID_ACCOUNT_NAME", "invalid account name")
+	ErrAccountDeactivated = platform.NewError(platform.FailedPrecondition, "ACCOUNT_DEACTIVATED", "account is deactivated")
+	ErrInvalidCursor      = platform.NewError(platform.InvalidArgument, "INVALID_CURSOR", "invalid cursor")
+	ErrInvalidEmail       = platform.NewError(platform.InvalidArgument, "INVALID_EMAIL", "invalid email")
+	ErrInvalidPassword    = platform.NewError(platform.InvalidArgument, "INVALID_PASSWORD", "invalid password")
+	// ErrInvalidCredentials не различает неизвестный email и неверный пароль,
+	// чтобы по ответу Login нельзя было проверить, зарегистрирован ли адрес
+	ErrInvalidCredentials  = platform.NewError(platform.Unauthenticated, "INVALID_CREDENTIALS", "invalid email or password")
+	ErrInvalidRefreshToken = platform.NewError(platform.Unauthenticated, "INVALID_REFRESH_TOKEN", "invalid or expired refresh token")
+	ErrInvalidRole         = platform.NewError(platform.InvalidArgument, "INVALID_ROLE", "invalid account role")
+)
+
+// maxNameLength совпадает с размером колонки accounts.name
+const maxNameLength = 24
+
+const (
+	minPasswordLength = 8
+	// bcrypt учитывает только первые 72 байта пароля
+	maxPasswordBytes = 72
+	refreshTokenTTL  = 30 * 24 * time.Hour
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
 )
 
 type Service interface {
 	PostAccount(ctx context.Context, name string) (*Account, error)
 	GetAccount(ctx context.Context, id string) (*Account, error)
 	GetAccounts(ctx context.Context, skip uint64, take uint64) ([]Account, error)
+	GetAccountsPage(ctx context.Context, after string, first uint64) (*AccountPage, error)
+	UpdateAccount(ctx context.Context, id string, name string) (*Account, error)
+	DeactivateAccount(ctx context.Context, id string) (*Account, error)
+	ReactivateAccount(ctx context.Context, id string) (*Account, error)
+	Register(ctx context.Context, name, email, password string) (*Account, error)
+	Login(ctx context.Context, email, password string) (*Session, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*Session, error)
+	SetRole(ctx context.Context, id string, role Role) (*Account, error)
+}
+
+type AccountStatus string
+
+const (
+	StatusActive      AccountStatus = "active"
+	StatusDeactivated AccountStatus = "deactivated"
+)
+
+// Role определяет, что аккаунту разрешено делать через шлюз.
+// Роли упорядочены: admin может всё, что может merchandiser, а тот - всё, что может customer.
+type Role string
+
+const (
+	RoleCustomer     Role = "customer"
+	RoleMerchandiser Role = "merchandiser"
+	RoleAdmin        Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleCustomer:     1,
+	RoleMerchandiser: 2,
+	RoleAdmin:        3,
+}
+
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Includes сообщает, даёт ли роль r права роли other
+func (r Role) Includes(other Role) bool {
+	return r.Valid() && roleRanks[r] >= roleRanks[other]
 }
 
 type Account struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Status    AccountStatus `json:"status"`
+	Role      Role          `json:"role"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type AccountEdge struct {
+	Account Account
+	// Cursor - непрозрачная позиция аккаунта в выдаче, передаётся в after для следующей страницы
+	Cursor string
+}
+
+// AccountPage - страница аккаунтов, от новых к старым
+type AccountPage struct {
+	Edges       []AccountEdge
+	HasNextPage bool
+}
+
+// Session - результат входа. Access токен выпускает шлюз, сервис аккаунтов отвечает
+// за проверку пароля и refresh токены.
+type Session struct {
+	Account Account
+	// RefreshToken одноразовый: RefreshToken обменивает его на новый
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// Active сообщает, может ли аккаунт оформлять заказы
+func (a Account) Active() bool {
+	return a.Status == StatusActive
 }
 
 type accountService struct {
 	repository Repository
+	logger     *slog.Logger
 }
 
-func NewService(r Repository) Service {
+func NewService(r Repository, logger *slog.Logger) Service {
 	return &accountService{
 		repository: r,
+		logger:     logger,
 	}
 }
 
 func (s *accountService) PostAccount(ctx context.Context, name string) (*Account, error) {
+	name, err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
 	a := Account{
-		Name: name,
-		ID:   ksuid.New().String(),
+		Name:      name,
+		ID:        ksuid.New().String(),
+		Status:    StatusActive,
+		Role:      RoleCustomer,
+		UpdatedAt: time.Now(),
 	}
-	if err := s.repository.PutAccount(ctx, a); err != nil {
+	event, err := platform.NewEvent(a.ID, accountCreatedEvent(a))
+	if err != nil {
 		return nil, err
 	}
+	if err := s.repository.PutAccount(ctx, a, event); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Account created", "account_id", a.ID)
 	return &a, nil
 }
 
+func accountCreatedEvent(a Account) *eventspb.AccountCreated {
+	return &eventspb.AccountCreated{AccountId: a.ID, Name: a.Name, Role: string(a.Role)}
+}
+
 func (s *accountService) GetAccount(ctx context.Context, id string) (*Account, error) {
 	return s.repository.GetAccountById(ctx, id)
 }
@@ -48,3 +180,271 @@ func (s *accountService) GetAccounts(ctx context.Context, skip uint64, take uint
 	}
 	return s.repository.ListAccounts(ctx, skip, take)
 }
+
+// GetAccountsPage возвращает first аккаунтов после курсора after (пустой after - с начала).
+// В отличие от GetAccounts страница не сдвигается при добавлении новых аккаунтов.
+func (s *accountService) GetAccountsPage(ctx context.Context, after string, first uint64) (*AccountPage, error) {
+	afterID, err := decodeCursor(after)
+	if err != nil {
+		return nil, err
+	}
+	if first == 0 {
+		first = defaultPageSize
+	}
+	first = min(first, maxPageSize)
+
+	// Лишний аккаунт показывает, есть ли следующая страница
+	accounts, err := s.repository.ListAccountsAfter(ctx, afterID, first+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &AccountPage{HasNextPage: uint64(len(accounts)) > first}
+	if page.HasNextPage {
+		accounts = accounts[:first]
+	}
+	page.Edges = make([]AccountEdge, len(accounts))
+	for i, a := range accounts {
+		page.Edges[i] = AccountEdge{Account: a, Cursor: encodeCursor(a.ID)}
+	}
+	return page, nil
+}
+
+// UpdateAccount меняет имя аккаунта. Деактивированный аккаунт изменить нельзя.
+func (s *accountService) UpdateAccount(ctx context.Context, id string, name string) (*Account, error) {
+	name, err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.repository.GetAccountById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !a.Active() {
+		return nil, ErrAccountDeactivated
+	}
+
+	return s.repository.UpdateAccountName(ctx, id, name, time.Now())
+}
+
+// DeactivateAccount запрещает аккаунту оформлять заказы. Повторная деактивация ничего не меняет.
+func (s *accountService) DeactivateAccount(ctx context.Context, id string) (*Account, error) {
+	return s.setStatus(ctx, id, StatusDeactivated)
+}
+
+// ReactivateAccount снимает деактивацию. Для активного аккаунта ничего не меняет.
+func (s *accountService) ReactivateAccount(ctx context.Context, id string) (*Account, error) {
+	return s.setStatus(ctx, id, StatusActive)
+}
+
+func (s *accountService) setStatus(ctx context.Context, id string, status AccountStatus) (*Account, error) {
+	a, err := s.repository.GetAccountById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == status {
+		return a, nil
+	}
+
+	a, err = s.repository.UpdateAccountStatus(ctx, id, status, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Account status changed", "account_id", a.ID, "status", a.Status)
+	return a, nil
+}
+
+// Register создаёт аккаунт, в который можно войти по email и паролю
+func (s *accountService) Register(ctx context.Context, name, email, password string) (*Account, error) {
+	name, err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+	email, err = normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePassword(password); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	a := Account{
+		Name:      name,
+		ID:        ksuid.New().String(),
+		Status:    StatusActive,
+		Role:      RoleCustomer,
+		UpdatedAt: time.Now(),
+	}
+	c := Credentials{AccountID: a.ID, Email: email, PasswordHash: string(hash)}
+	event, err := platform.NewEvent(a.ID, accountCreatedEvent(a))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.PutAccountWithCredentials(ctx, a, c, event); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Account registered", "account_id", a.ID)
+	return &a, nil
+}
+
+// Login проверяет пароль и открывает сессию. Деактивированный аккаунт войти не может.
+func (s *accountService) Login(ctx context.Context, email, password string) (*Session, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	c, err := s.repository.GetCredentialsByEmail(ctx, email)
+	if errors.Is(err, ErrCredentialsNotFound) {
+		// Сравниваем с фиктивным хешем, чтобы время ответа не выдавало незарегистрированный email
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(c.PasswordHash), []byte(password)); err != nil {
+		// Email в лог не пишем, аккаунта достаточно, чтобы заметить подбор пароля
+		s.logger.WarnContext(ctx, "Login with wrong password", "account_id", c.AccountID)
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.openSession(ctx, c.AccountID)
+}
+
+// RefreshToken обменивает refresh токен на новую сессию. Старый токен после этого недействителен.
+func (s *accountService) RefreshToken(ctx context.Context, refreshToken string) (*Session, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	t, err := s.repository.TakeRefreshToken(ctx, hashRefreshToken(refreshToken))
+	if errors.Is(err, ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(t.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.openSession(ctx, t.AccountID)
+}
+
+func (s *accountService) openSession(ctx context.Context, accountID string) (*Session, error) {
+	a, err := s.repository.GetAccountById(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.Active() {
+		return nil, ErrAccountDeactivated
+	}
+
+	token, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	t := RefreshToken{
+		Hash:      hashRefreshToken(token),
+		AccountID: a.ID,
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if err := s.repository.PutRefreshToken(ctx, t); err != nil {
+		return nil, err
+	}
+	return &Session{Account: *a, RefreshToken: token, RefreshExpiresAt: t.ExpiresAt}, nil
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken - в базе хранится только хеш: утечка таблицы не даёт войти в чужой аккаунт
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	// ParseAddress принимает и "Name <a@b>", нам нужен только сам адрес
+	if err != nil || addr.Address != email {
+		return "", fmt.Errorf("%w: %q", ErrInvalidEmail, email)
+	}
+	return email, nil
+}
+
+func validatePassword(password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return fmt.Errorf("%w: password is shorter than %d characters", ErrInvalidPassword, minPasswordLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("%w: password is longer than %d bytes", ErrInvalidPassword, maxPasswordBytes)
+	}
+	return nil
+}
+
+// SetRole назначает аккаунту роль. Новая роль попадает в access токен при следующем входе или обновлении токена.
+func (s *accountService) SetRole(ctx context.Context, id string, role Role) (*Account, error) {
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRole, role)
+	}
+
+	a, err := s.repository.GetAccountById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Role == role {
+		return a, nil
+	}
+
+	a, err = s.repository.UpdateAccountRole(ctx, id, role, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Account role changed", "account_id", a.ID, "role", a.Role)
+	return a, nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidName)
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return "", fmt.Errorf("%w: name is longer than %d characters", ErrInvalidName, maxNameLength)
+	}
+	return name, nil
+}
+
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(id) == 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	return string(id), nil
+}
@@ -0,0 +1,211 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"go-microservice/platform"
+	"strings"
+	"testing"
+)
+
+func TestAccountDeactivation(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(NewInMemoryRepository(), platform.DiscardLogger())
+
+	a, err := s.PostAccount(ctx, "alice")
+	if err != nil {
+		t.Fatalf("PostAccount: %v", err)
+	}
+	if !a.Active() {
+		t.Fatalf("new account status = %s, want %s", a.Status, StatusActive)
+	}
+
+	deactivated, err := s.DeactivateAccount(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("DeactivateAccount: %v", err)
+	}
+	if deactivated.Status != StatusDeactivated || deactivated.UpdatedAt.Before(a.UpdatedAt) {
+		t.Errorf("DeactivateAccount = %+v, want deactivated with newer updatedAt", deactivated)
+	}
+	if again, err := s.DeactivateAccount(ctx, a.ID); err != nil || !again.UpdatedAt.Equal(deactivated.UpdatedAt) {
+		t.Errorf("repeated DeactivateAccount = %+v, %v, want unchanged account", again, err)
+	}
+	if _, err := s.UpdateAccount(ctx, a.ID, "bob"); !errors.Is(err, ErrAccountDeactivated) {
+		t.Errorf("UpdateAccount of deactivated account error = %v, want %v", err, ErrAccountDeactivated)
+	}
+
+	if _, err := s.ReactivateAccount(ctx, a.ID); err != nil {
+		t.Fatalf("ReactivateAccount: %v", err)
+	}
+	updated, err := s.UpdateAccount(ctx, a.ID, "  bob ")
+	if err != nil {
+		t.Fatalf("UpdateAccount: %v", err)
+	}
+	if updated.Name != "bob" || !updated.Active() {
+		t.Errorf("UpdateAccount = %+v, want active account named bob", updated)
+	}
+
+	if _, err := s.DeactivateAccount(ctx, "missing"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("DeactivateAccount of missing account error = %v, want %v", err, ErrAccountNotFound)
+	}
+}
+
+func TestAccountNameValidation(t *testing.T) {
+	s := NewService(NewInMemoryRepository(), platform.DiscardLogger())
+	for _, name := range []string{"", "   ", strings.Repeat("я", maxNameLength+1)} {
+		if _, err := s.PostAccount(context.Background(), name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("PostAccount(%q) error = %v, want %v", name, err, ErrInvalidName)
+		}
+	}
+	if _, err := s.PostAccount(context.Background(), strings.Repeat("я", maxNameLength)); err != nil {
+		t.Errorf("PostAccount with %d characters: %v", maxNameLength, err)
+	}
+}
+
+func TestGetAccountsPage(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(NewInMemoryRepository(), platform.DiscardLogger())
+
+	for range 3 {
+		if _, err := s.PostAccount(ctx, "user"); err != nil {
+			t.Fatalf("PostAccount: %v", err)
+		}
+	}
+
+	first, err := s.GetAccountsPage(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("GetAccountsPage: %v", err)
+	}
+	if len(first.Edges) != 2 || !first.HasNextPage {
+		t.Fatalf("first page = %d accounts, hasNextPage %v, want 2, true", len(first.Edges), first.HasNextPage)
+	}
+
+	second, err := s.GetAccountsPage(ctx, first.Edges[1].Cursor, 2)
+	if err != nil {
+		t.Fatalf("GetAccountsPage: %v", err)
+	}
+	if len(second.Edges) != 1 || second.HasNextPage {
+		t.Fatalf("second page = %d accounts, hasNextPage %v, want 1, false", len(second.Edges), second.HasNextPage)
+	}
+	if second.Edges[0].Account.ID >= first.Edges[1].Account.ID {
+		t.Errorf("second page starts with %s, want account after %s", second.Edges[0].Account.ID, first.Edges[1].Account.ID)
+	}
+
+	if _, err := s.GetAccountsPage(ctx, "not a cursor!", 2); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("GetAccountsPage with malformed cursor error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestLoginAndRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(NewInMemoryRepository(), platform.DiscardLogger())
+
+	a, err := s.Register(ctx, "alice", " Alice@Example.com ", "correct horse")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := s.Register(ctx, "alice2", "alice@example.com", "another password"); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("Register with taken email error = %v, want %v", err, ErrEmailTaken)
+	}
+
+	for _, tt := range []struct{ email, password string }{
+		{"alice@example.com", "wrong password"},
+		{"bob@example.com", "correct horse"},
+		{"not an email", "correct horse"},
+	} {
+		if _, err := s.Login(ctx, tt.email, tt.password); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Login(%q, %q) error = %v, want %v", tt.email, tt.password, err, ErrInvalidCredentials)
+		}
+	}
+
+	session, err := s.Login(ctx, "ALICE@example.com", "correct horse")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if session.Account.ID != a.ID || session.RefreshToken == "" {
+		t.Fatalf("Login session = %+v, want session of %s with refresh token", session, a.ID)
+	}
+
+	refreshed, err := s.RefreshToken(ctx, session.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if refreshed.Account.ID != a.ID || refreshed.RefreshToken == session.RefreshToken {
+		t.Errorf("RefreshToken session = %+v, want new token for %s", refreshed, a.ID)
+	}
+	// Refresh токен одноразовый
+	if _, err := s.RefreshToken(ctx, session.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("reused RefreshToken error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+
+	if _, err := s.DeactivateAccount(ctx, a.ID); err != nil {
+		t.Fatalf("DeactivateAccount: %v", err)
+	}
+	if _, err := s.Login(ctx, "alice@example.com", "correct horse"); !errors.Is(err, ErrAccountDeactivated) {
+		t.Errorf("Login to deactivated account error = %v, want %v", err, ErrAccountDeactivated)
+	}
+	if _, err := s.RefreshToken(ctx, refreshed.RefreshToken); !errors.Is(err, ErrAccountDeactivated) {
+		t.Errorf("RefreshToken of deactivated account error = %v, want %v", err, ErrAccountDeactivated)
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	s := NewService(NewInMemoryRepository(), platform.DiscardLogger())
+	tests := []struct {
+		email, password string
+		want            error
+	}{
+		{"alice", "correct horse", ErrInvalidEmail},
+		{"Alice <alice@example.com>", "correct horse", ErrInvalidEmail},
+		{"alice@example.com", "short", ErrInvalidPassword},
+		{"alice@example.com", strings.Repeat("x", maxPasswordBytes+1), ErrInvalidPassword},
+	}
+	for _, tt := range tests {
+		if _, err := s.Register(context.Background(), "alice", tt.email, tt.password); !errors.Is(err, tt.want) {
+			t.Errorf("Register(%q, %q) error = %v, want %v", tt.email, tt.password, err, tt.want)
+		}
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(NewInMemoryRepository(), platform.DiscardLogger())
+
+	a, err := s.PostAccount(ctx, "alice")
+	if err != nil {
+		t.Fatalf("PostAccount: %v", err)
+	}
+	if a.Role != RoleCustomer {
+		t.Errorf("new account role = %s, want %s", a.Role, RoleCustomer)
+	}
+
+	updated, err := s.SetRole(ctx, a.ID, RoleMerchandiser)
+	if err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	if got, err := s.GetAccount(ctx, a.ID); err != nil || got.Role != RoleMerchandiser || !got.UpdatedAt.Equal(updated.UpdatedAt) {
+		t.Errorf("GetAccount after SetRole = %+v, %v, want merchandiser", got, err)
+	}
+	if _, err := s.SetRole(ctx, a.ID, "superuser"); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("SetRole with unknown role error = %v, want %v", err, ErrInvalidRole)
+	}
+}
+
+func TestRoleIncludes(t *testing.T) {
+	tests := []struct {
+		role, other Role
+		want        bool
+	}{
+		{RoleAdmin, RoleMerchandiser, true},
+		{RoleAdmin, RoleCustomer, true},
+		{RoleMerchandiser, RoleMerchandiser, true},
+		{RoleMerchandiser, RoleAdmin, false},
+		{RoleCustomer, RoleMerchandiser, false},
+		{"", RoleCustomer, false},
+	}
+	for _, tt := range tests {
+		if got := tt.role.Includes(tt.other); got != tt.want {
+			t.Errorf("%q.Includes(%q) = %v, want %v", tt.role, tt.other, got, tt.want)
+		}
+	}
+}
@@ -1,4 +1,33 @@
 CREATE TABLE IF NOT EXISTS accounts(
     id CHAR(27) PRIMARY KEY,
     name VARCHAR(24) NOT NULL
-);
\ No newline at end of file
+);
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'deactivated'));
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'customer'
+    CHECK (role IN ('customer', 'merchandiser', 'admin'));
+
+CREATE TABLE IF NOT EXISTS credentials(
+    account_id CHAR(27) PRIMARY KEY REFERENCES accounts(id),
+    email VARCHAR(254) NOT NULL UNIQUE,
+    password_hash VARCHAR(60) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+    token_hash CHAR(64) PRIMARY KEY,
+    account_id CHAR(27) NOT NULL REFERENCES accounts(id),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS refresh_tokens_account_id ON refresh_tokens(account_id);
+
+-- Outbox: события, записанные в одной транзакции с аккаунтом. Relay публикует их и удаляет.
+CREATE TABLE IF NOT EXISTS outbox(
+    id CHAR(27) PRIMARY KEY,
+    type VARCHAR(128) NOT NULL,
+    aggregate_id CHAR(27) NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    payload BYTEA NOT NULL
+);
+CREATE INDEX IF NOT EXISTS outbox_occurred_at_idx ON outbox (occurred_at, id);
@@ -3,9 +3,11 @@ package catalog
 import (
 	"context"
 	"go-microservice/catalog/pb"
+	"go-microservice/money"
+	"go-microservice/platform"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 type Client struct {
@@ -13,8 +15,9 @@ type Client struct {
 	client pb.CatalogServiceClient
 }
 
-func NewClient(url string) (*Client, error) {
-	conn, err := grpc.NewClient(url, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewClient подключается к сервису по url; без сертификатов в tlsConfig соединение не шифруется
+func NewClient(url string, tlsConfig platform.TLSConfig) (*Client, error) {
+	conn, err := platform.Dial(url, tlsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -26,55 +29,128 @@ func (c *Client) Close() {
 	c.conn.Close()
 }
 
-func (c *Client) GetProduct(ctx context.Context, id string) (*Product, error) {
-	p, err := c.client.GetProduct(ctx, &pb.GetProductRequest{Id: id})
+// Health проверяет сервис и его зависимости по grpc.health.v1
+func (c *Client) Health(ctx context.Context) error {
+	return platform.CheckConn(ctx, c.conn)
+}
+
+// GetProduct возвращает товар с ценой в currency; пустая строка - базовая валюта каталога
+func (c *Client) GetProduct(ctx context.Context, id, currency string) (*Product, error) {
+	p, err := c.client.GetProduct(ctx, &pb.GetProductRequest{Id: id, Currency: currency})
 	if err != nil {
 		return nil, err
 	}
-	return &Product{
-		ID:          p.Product.Id,
-		Name:        p.Product.Name,
-		Description: p.Product.Description,
-		Price:       p.Product.Price,
-	}, nil
+	return fromProtoProduct(p.Product), nil
 }
 
-func (c *Client) PostProduct(ctx context.Context, price float64, name, description string) (*Product, error) {
+func (c *Client) PostProduct(ctx context.Context, price money.Money, name, description string, stock uint32) (*Product, error) {
 	p, err := c.client.PostProduct(ctx, &pb.PostProductRequest{
 		Name:        name,
 		Description: description,
-		Price:       price,
+		Price:       money.ToProto(price),
+		Stock:       stock,
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &Product{
-		ID:          p.Product.Id,
-		Name:        p.Product.Name,
-		Description: p.Product.Description,
-		Price:       p.Product.Price,
-	}, nil
+	return fromProtoProduct(p.Product), nil
 }
-func (c *Client) GetProducts(ctx context.Context, ids []string, query string, skip, take uint64) ([]Product, error) {
+// GetProducts возвращает товары с ценами в currency; пустая строка - базовая валюта каталога
+func (c *Client) GetProducts(ctx context.Context, ids []string, query string, skip, take uint64, currency string) ([]Product, error) {
 	p, err := c.client.GetProducts(ctx, &pb.GetProductsRequest{
-		Ids:   ids,
-		Query: query,
-		Skip:  skip,
-		Take:  take,
+		Ids:      ids,
+		Query:    query,
+		Skip:     skip,
+		Take:     take,
+		Currency: currency,
 	})
 	if err != nil {
 		return nil, err
 	}
 	products := make([]Product, len(p.Products))
 	for i, v := range p.Products {
-		products[i] = Product{
-			ID:          v.Id,
-			Name:        v.Name,
-			Description: v.Description,
-			Price:       v.Price,
-		}
+		products[i] = *fromProtoProduct(v)
 	}
 	return products, nil
 }
+
+// GetProductsPage возвращает страницу товаров после курсора after; непустой query включает поиск
+func (c *Client) GetProductsPage(ctx context.Context, query, after string, first uint64, currency string) (*ProductPage, error) {
+	r, err := c.client.GetProductsPage(ctx, &pb.GetProductsPageRequest{
+		After:    after,
+		First:    first,
+		Query:    query,
+		Currency: currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	page := &ProductPage{
+		Edges:       make([]ProductEdge, len(r.Edges)),
+		HasNextPage: r.HasNextPage,
+	}
+	for i, e := range r.Edges {
+		page.Edges[i] = ProductEdge{Product: *fromProtoProduct(e.Product), Cursor: e.Cursor}
+	}
+	return page, nil
+}
+
+// UpdateProduct меняет у товара p.ID только поля из paths (FieldName, FieldDescription, FieldPrice, FieldStock)
+func (c *Client) UpdateProduct(ctx context.Context, p Product, paths []string) (*Product, error) {
+	r, err := c.client.UpdateProduct(ctx, &pb.UpdateProductRequest{
+		Product: &pb.Product{
+			Id:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       money.ToProto(p.Price),
+			Stock:       p.Stock,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoProduct(r.Product), nil
+}
+
+func (c *Client) DeleteProduct(ctx context.Context, id string) error {
+	_, err := c.client.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: id})
+	return err
+}
+
+func fromProtoProduct(p *pb.Product) *Product {
+	return &Product{
+		ID:          p.Id,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       money.FromProto(p.Price),
+		Deleted:     p.Deleted,
+		Stock:       p.Stock,
+	}
+}
+
+// ReserveStock резервирует товары и возвращает ID резерва.
+// При нехватке товара возвращает ErrOutOfStock, товар - в Metadata["productId"] ошибки *platform.Error.
+func (c *Client) ReserveStock(ctx context.Context, items []StockItem) (string, error) {
+	pbItems := make([]*pb.StockItem, len(items))
+	for i, item := range items {
+		pbItems[i] = &pb.StockItem{ProductId: item.ProductID, Quantity: item.Quantity}
+	}
+	r, err := c.client.ReserveStock(ctx, &pb.ReserveStockRequest{Items: pbItems})
+	if err != nil {
+		return "", err
+	}
+	return r.ReservationId, nil
+}
+
+func (c *Client) ReleaseStock(ctx context.Context, reservationID string) error {
+	_, err := c.client.ReleaseStock(ctx, &pb.ReleaseStockRequest{ReservationId: reservationID})
+	return err
+}
+
+func (c *Client) CommitStock(ctx context.Context, reservationID string) error {
+	_, err := c.client.CommitStock(ctx, &pb.CommitStockRequest{ReservationId: reservationID})
+	return err
+}
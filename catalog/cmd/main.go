@@ -1,15 +1,27 @@
 package main
 
 import (
+	"context"
 	"go-microservice/catalog"
+	"go-microservice/money"
+	"go-microservice/platform"
 	"log"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	DatabaseURL string `envconfig:"DATABASE_URL"`
+	DatabaseURL       string `envconfig:"DATABASE_URL"`
+	BaseCurrency      string `envconfig:"BASE_CURRENCY" default:"USD"`
+	ExchangeRatesFile string `envconfig:"EXCHANGE_RATES_FILE"`
+	MetricsAddr       string `envconfig:"METRICS_ADDR" default:":9090"`
+	platform.TLSConfig
+	platform.TracingConfig
+	platform.LogConfig
+	platform.EventsConfig
 }
 
 func main() {
@@ -19,19 +31,63 @@ func main() {
 		log.Fatal(err)
 	}
 
-	time.Sleep(20 * time.Second)
+	logger, err := platform.NewLogger("catalog", cfg.LogConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logger)
 
-	r, err := catalog.NewElasticReposytory(cfg.DatabaseURL)
+	ctx, stop := platform.SignalContext()
+	defer stop()
+
+	if err := run(ctx, cfg, logger); err != nil {
+		logger.Error("Service failed", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("Stopped")
+}
+
+// run работает до отмены ctx. После остановки gRPC сервера останавливается relay событий, затем закрывается репозиторий.
+func run(ctx context.Context, cfg Config, logger *slog.Logger) error {
+	shutdownTracing, err := platform.SetupTracing(ctx, "catalog", cfg.TracingConfig)
 	if err != nil {
-		log.Println(err)
-		return
+		return err
+	}
+	defer shutdownTracing()
+
+	// Elasticsearch стартует дольше Postgres
+	startCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	var r catalog.Repository
+	err = platform.Retry(startCtx, "connect to elasticsearch", func() (err error) {
+		r, err = catalog.NewElasticReposytory(cfg.DatabaseURL)
+		return err
+	})
+	cancel()
+	if err != nil {
+		return err
 	}
 	defer r.Close()
+	r = catalog.NewInstrumentedRepository(r, "elasticsearch", logger)
 
-	s := catalog.NewService(r)
+	// Relay останавливается раньше, чем закрывается репозиторий
+	stopRelay, err := platform.StartEventRelay(ctx, r, cfg.EventsConfig)
+	if err != nil {
+		return err
+	}
+	defer stopRelay()
 
-	if err = catalog.ListenGRPC(s, 50051); err != nil {
-		log.Fatal(err)
+	go func() {
+		if err := platform.ServeMetrics(ctx, cfg.MetricsAddr); err != nil {
+			logger.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	rates, err := money.LoadRates(cfg.ExchangeRatesFile, cfg.BaseCurrency)
+	if err != nil {
+		return err
 	}
-	log.Println("Listening on port 50051")
+
+	s := catalog.NewService(r, rates.Base(), rates, logger)
+
+	return catalog.ListenGRPC(ctx, s, 50051, cfg.TLSConfig, logger, platform.HealthCheck{Name: "elasticsearch", Check: r.Ping})
 }
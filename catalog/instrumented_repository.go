@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"context"
+	"go-microservice/platform"
+	"log/slog"
+)
+
+// instrumentedRepository замеряет каждый запрос к хранилищу через platform.QueryObserver.
+// Close и Ping передаются как есть.
+type instrumentedRepository struct {
+	Repository
+	queries platform.QueryObserver
+}
+
+// NewInstrumentedRepository оборачивает r, store - имя хранилища в метриках и span (postgres, elasticsearch)
+func NewInstrumentedRepository(r Repository, store string, logger *slog.Logger) Repository {
+	return &instrumentedRepository{Repository: r, queries: platform.QueryObserver{Store: store, Logger: logger}}
+}
+
+func (r *instrumentedRepository) PutProduct(ctx context.Context, p Product, events ...platform.Event) error {
+	return platform.ObserveExec(ctx, r.queries, "PutProduct", func(ctx context.Context) error {
+		return r.Repository.PutProduct(ctx, p, events...)
+	})
+}
+
+func (r *instrumentedRepository) GetProductByID(ctx context.Context, id string) (*Product, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetProductByID", func(ctx context.Context) (*Product, error) {
+		return r.Repository.GetProductByID(ctx, id)
+	})
+}
+
+func (r *instrumentedRepository) ListProducts(ctx context.Context, skip, take uint64) ([]Product, error) {
+	return platform.ObserveQuery(ctx, r.queries, "ListProducts", func(ctx context.Context) ([]Product, error) {
+		return r.Repository.ListProducts(ctx, skip, take)
+	})
+}
+
+func (r *instrumentedRepository) ListProductsWithIDs(ctx context.Context, ids []string) ([]Product, error) {
+	return platform.ObserveQuery(ctx, r.queries, "ListProductsWithIDs", func(ctx context.Context) ([]Product, error) {
+		return r.Repository.ListProductsWithIDs(ctx, ids)
+	})
+}
+
+func (r *instrumentedRepository) SearchProducts(ctx context.Context, query string, skip, take uint64) ([]Product, error) {
+	return platform.ObserveQuery(ctx, r.queries, "SearchProducts", func(ctx context.Context) ([]Product, error) {
+		return r.Repository.SearchProducts(ctx, query, skip, take)
+	})
+}
+
+func (r *instrumentedRepository) ListProductsAfter(ctx context.Context, after string, limit uint64) ([]Product, error) {
+	return platform.ObserveQuery(ctx, r.queries, "ListProductsAfter", func(ctx context.Context) ([]Product, error) {
+		return r.Repository.ListProductsAfter(ctx, after, limit)
+	})
+}
+
+func (r *instrumentedRepository) SearchProductsAfter(ctx context.Context, query string, after *SearchAfter, limit uint64) ([]ProductHit, error) {
+	return platform.ObserveQuery(ctx, r.queries, "SearchProductsAfter", func(ctx context.Context) ([]ProductHit, error) {
+		return r.Repository.SearchProductsAfter(ctx, query, after, limit)
+	})
+}
+
+func (r *instrumentedRepository) UpdateProduct(ctx context.Context, p Product, paths []string, events ...platform.Event) error {
+	return platform.ObserveExec(ctx, r.queries, "UpdateProduct", func(ctx context.Context) error {
+		return r.Repository.UpdateProduct(ctx, p, paths, events...)
+	})
+}
+
+func (r *instrumentedRepository) DeleteProduct(ctx context.Context, id string) error {
+	return platform.ObserveExec(ctx, r.queries, "DeleteProduct", func(ctx context.Context) error {
+		return r.Repository.DeleteProduct(ctx, id)
+	})
+}
+
+func (r *instrumentedRepository) ReserveStock(ctx context.Context, res Reservation) error {
+	return platform.ObserveExec(ctx, r.queries, "ReserveStock", func(ctx context.Context) error {
+		return r.Repository.ReserveStock(ctx, res)
+	})
+}
+
+func (r *instrumentedRepository) CloseReservation(ctx context.Context, id string, status ReservationStatus) error {
+	return platform.ObserveExec(ctx, r.queries, "CloseReservation", func(ctx context.Context) error {
+		return r.Repository.CloseReservation(ctx, id, status)
+	})
+}
+
+func (r *instrumentedRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	return platform.ObserveQuery(ctx, r.queries, "PendingEvents", func(ctx context.Context) ([]platform.Event, error) {
+		return r.Repository.PendingEvents(ctx, limit)
+	})
+}
+
+func (r *instrumentedRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	return platform.ObserveExec(ctx, r.queries, "DeleteEvents", func(ctx context.Context) error {
+		return r.Repository.DeleteEvents(ctx, ids)
+	})
+}
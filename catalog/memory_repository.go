@@ -0,0 +1,339 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"go-microservice/platform"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// InMemoryRepository хранит товары в памяти процесса.
+// Используется в тестах и для локального запуска без Elasticsearch.
+type InMemoryRepository struct {
+	mu           sync.RWMutex
+	products     map[string]Product
+	reservations map[string]Reservation
+	// outbox - неопубликованные события в порядке записи
+	outbox []platform.Event
+}
+
+func NewInMemoryRepository() Repository {
+	return &InMemoryRepository{
+		products:     make(map[string]Product),
+		reservations: make(map[string]Reservation),
+	}
+}
+
+func (r *InMemoryRepository) Close() error {
+	return nil
+}
+
+func (r *InMemoryRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (r *InMemoryRepository) PutProduct(ctx context.Context, p Product, events ...platform.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.products[p.ID] = p
+	r.outbox = append(r.outbox, events...)
+	return nil
+}
+
+func (r *InMemoryRepository) GetProductByID(ctx context.Context, id string) (*Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.products[id]
+	if !ok {
+		return nil, ErrProductNotFound
+	}
+	return &p, nil
+}
+
+func (r *InMemoryRepository) UpdateProduct(ctx context.Context, p Product, paths []string, events ...platform.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[p.ID]
+	if !ok {
+		return ErrProductNotFound
+	}
+	if err := applyUpdate(&stored, p, paths); err != nil {
+		return err
+	}
+	r.products[p.ID] = stored
+	r.outbox = append(r.outbox, events...)
+	return nil
+}
+
+// DeleteProduct помечает товар удалённым, как и ElasticRepository
+func (r *InMemoryRepository) DeleteProduct(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.products[id]
+	if !ok {
+		return ErrProductNotFound
+	}
+	p.Deleted = true
+	r.products[id] = p
+	return nil
+}
+
+func (r *InMemoryRepository) ReserveStock(ctx context.Context, res Reservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.reservations[res.ID]; exists {
+		return fmt.Errorf("reservation %s already exists", res.ID)
+	}
+	for _, item := range res.Items {
+		p, ok := r.products[item.ProductID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID)
+		}
+		if p.Stock < item.Quantity {
+			return &OutOfStockError{ProductID: item.ProductID}
+		}
+	}
+
+	for _, item := range res.Items {
+		p := r.products[item.ProductID]
+		p.Stock -= item.Quantity
+		r.products[item.ProductID] = p
+	}
+	res.Items = append([]StockItem(nil), res.Items...)
+	r.reservations[res.ID] = res
+	return nil
+}
+
+func (r *InMemoryRepository) CloseReservation(ctx context.Context, id string, status ReservationStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res, ok := r.reservations[id]
+	if !ok {
+		return ErrReservationNotFound
+	}
+	if res.Status == status {
+		return nil
+	}
+	if res.Status != ReservationReserved {
+		return fmt.Errorf("%w: %s", ErrReservationClosed, res.Status)
+	}
+
+	if status == ReservationReleased {
+		for _, item := range res.Items {
+			if p, ok := r.products[item.ProductID]; ok {
+				p.Stock += item.Quantity
+				r.products[item.ProductID] = p
+			}
+		}
+	}
+	res.Status = status
+	r.reservations[id] = res
+	return nil
+}
+
+// ListProducts возвращает неудалённые товары, упорядоченные по ID
+func (r *InMemoryRepository) ListProducts(ctx context.Context, skip, take uint64) ([]Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make([]Product, 0, len(r.products))
+	for _, p := range r.products {
+		if !p.Deleted {
+			products = append(products, p)
+		}
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].ID < products[j].ID
+	})
+	return paginate(products, skip, take), nil
+}
+
+// ListProductsWithIDs возвращает найденные товары в порядке переданных IDs, включая удалённые; отсутствующие пропускаются
+func (r *InMemoryRepository) ListProductsWithIDs(ctx context.Context, ids []string) ([]Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make([]Product, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if p, ok := r.products[id]; ok {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+// SearchProducts приближённо повторяет multi_match запрос ElasticRepository.SearchProducts:
+// best_fields по name и description с fuzziness AUTO и оператором OR.
+func (r *InMemoryRepository) SearchProducts(ctx context.Context, query string, skip, take uint64) ([]Product, error) {
+	hits := r.search(query)
+	products := make([]Product, len(hits))
+	for i, h := range hits {
+		products[i] = h.Product
+	}
+	return paginate(products, skip, take), nil
+}
+
+// ListProductsAfter implements Repository.
+func (r *InMemoryRepository) ListProductsAfter(ctx context.Context, after string, limit uint64) ([]Product, error) {
+	products, err := r.ListProducts(ctx, 0, math.MaxUint64)
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(products), func(i int) bool {
+		return products[i].ID > after
+	})
+	return paginate(products[i:], 0, limit), nil
+}
+
+// SearchProductsAfter implements Repository.
+func (r *InMemoryRepository) SearchProductsAfter(ctx context.Context, query string, after *SearchAfter, limit uint64) ([]ProductHit, error) {
+	hits := r.search(query)
+	if after != nil {
+		i := sort.Search(len(hits), func(i int) bool {
+			h := hits[i]
+			return h.Score < after.Score || (h.Score == after.Score && h.Product.ID > after.ID)
+		})
+		hits = hits[i:]
+	}
+	if limit < uint64(len(hits)) {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// search возвращает неудалённые товары, подходящие под запрос, по убыванию релевантности
+func (r *InMemoryRepository) search(query string) []ProductHit {
+	terms := analyze(query)
+	if len(terms) == 0 {
+		return []ProductHit{}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hits := make([]ProductHit, 0)
+	for _, p := range r.products {
+		if p.Deleted {
+			continue
+		}
+		// best_fields: оценка документа равна оценке лучшего поля
+		score := max(fieldScore(terms, analyze(p.Name)), fieldScore(terms, analyze(p.Description)))
+		if score > 0 {
+			hits = append(hits, ProductHit{Product: p, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Product.ID < hits[j].Product.ID
+	})
+	return hits
+}
+
+func paginate(products []Product, skip, take uint64) []Product {
+	if skip >= uint64(len(products)) {
+		return []Product{}
+	}
+	products = products[skip:]
+	if take < uint64(len(products)) {
+		products = products[:take]
+	}
+	return products
+}
+
+// analyze разбивает текст на токены в нижнем регистре, как standard analyzer
+func analyze(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// fieldScore суммирует совпадения терминов запроса с токенами поля.
+// Точное совпадение весит 1, нечёткое - меньше в зависимости от расстояния.
+func fieldScore(terms, tokens []string) float64 {
+	score := 0.0
+	for _, term := range terms {
+		best := 0.0
+		allowed := fuzziness(term)
+		for _, token := range tokens {
+			d := levenshtein(term, token)
+			if d > allowed {
+				continue
+			}
+			if s := 1 / float64(d+1); s > best {
+				best = s
+			}
+		}
+		score += best
+	}
+	return score
+}
+
+// fuzziness соответствует режиму AUTO в Elasticsearch:
+// 0 правок для терминов из 1-2 символов, 1 для 3-5, 2 для более длинных
+func fuzziness(term string) int {
+	switch n := len([]rune(term)); {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func (r *InMemoryRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]platform.Event{}, r.outbox[:min(limit, len(r.outbox))]...), nil
+}
+
+func (r *InMemoryRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.outbox = slices.DeleteFunc(r.outbox, func(e platform.Event) bool {
+		return slices.Contains(ids, e.ID)
+	})
+	return nil
+}
@@ -9,8 +9,10 @@
 package pb
 
 import (
+	pb "go-microservice/money/pb"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -24,11 +26,14 @@ const (
 )
 
 type Product struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price       *pb.Money              `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	// Удалённые товары не попадают в выдачу, но остаются доступны по ID для старых заказов
+	Deleted       bool   `protobuf:"varint,6,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Stock         uint32 `protobuf:"varint,7,opt,name=stock,proto3" json:"stock,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -84,10 +89,24 @@ func (x *Product) GetDescription() string {
 	return ""
 }
 
-func (x *Product) GetPrice() float64 {
+func (x *Product) GetPrice() *pb.Money {
 	if x != nil {
 		return x.Price
 	}
+	return nil
+}
+
+func (x *Product) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *Product) GetStock() uint32 {
+	if x != nil {
+		return x.Stock
+	}
 	return 0
 }
 
@@ -95,7 +114,8 @@ type PostProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Price         *pb.Money              `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         uint32                 `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -144,16 +164,25 @@ func (x *PostProductRequest) GetDescription() string {
 	return ""
 }
 
-func (x *PostProductRequest) GetPrice() float64 {
+func (x *PostProductRequest) GetPrice() *pb.Money {
 	if x != nil {
 		return x.Price
 	}
+	return nil
+}
+
+func (x *PostProductRequest) GetStock() uint32 {
+	if x != nil {
+		return x.Stock
+	}
 	return 0
 }
 
 type GetProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Валюта, в которой вернуть цену. Пусто - базовая валюта каталога.
+	Currency      string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -195,12 +224,21 @@ func (x *GetProductRequest) GetId() string {
 	return ""
 }
 
+func (x *GetProductRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type GetProductsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Skip          uint64                 `protobuf:"varint,1,opt,name=skip,proto3" json:"skip,omitempty"`
-	Take          uint64                 `protobuf:"varint,2,opt,name=take,proto3" json:"take,omitempty"`
-	Ids           []string               `protobuf:"bytes,3,rep,name=ids,proto3" json:"ids,omitempty"`
-	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Skip  uint64                 `protobuf:"varint,1,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take  uint64                 `protobuf:"varint,2,opt,name=take,proto3" json:"take,omitempty"`
+	Ids   []string               `protobuf:"bytes,3,rep,name=ids,proto3" json:"ids,omitempty"`
+	Query string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	// Валюта, в которой вернуть цены. Пусто - базовая валюта каталога.
+	Currency      string `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -263,6 +301,621 @@ func (x *GetProductsRequest) GetQuery() string {
 	return ""
 }
 
+func (x *GetProductsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type GetProductsPageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Курсор последнего товара предыдущей страницы, пусто - первая страница
+	After string `protobuf:"bytes,1,opt,name=after,proto3" json:"after,omitempty"`
+	First uint64 `protobuf:"varint,2,opt,name=first,proto3" json:"first,omitempty"`
+	// Пусто - все товары по ID, иначе поиск по релевантности
+	Query         string `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsPageRequest) Reset() {
+	*x = GetProductsPageRequest{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsPageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsPageRequest) ProtoMessage() {}
+
+func (x *GetProductsPageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsPageRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsPageRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetProductsPageRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *GetProductsPageRequest) GetFirst() uint64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *GetProductsPageRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *GetProductsPageRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ProductEdge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductEdge) Reset() {
+	*x = ProductEdge{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductEdge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductEdge) ProtoMessage() {}
+
+func (x *ProductEdge) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductEdge.ProtoReflect.Descriptor instead.
+func (*ProductEdge) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProductEdge) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductEdge) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetProductsPageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Edges         []*ProductEdge         `protobuf:"bytes,1,rep,name=edges,proto3" json:"edges,omitempty"`
+	HasNextPage   bool                   `protobuf:"varint,2,opt,name=hasNextPage,proto3" json:"hasNextPage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsPageResponse) Reset() {
+	*x = GetProductsPageResponse{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsPageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsPageResponse) ProtoMessage() {}
+
+func (x *GetProductsPageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsPageResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsPageResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetProductsPageResponse) GetEdges() []*ProductEdge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+func (x *GetProductsPageResponse) GetHasNextPage() bool {
+	if x != nil {
+		return x.HasNextPage
+	}
+	return false
+}
+
+type UpdateProductRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// product.id определяет изменяемый товар
+	Product *Product `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Изменяемые поля: name, description, price, stock. Пустая маска - все заполненные поля.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateProductRequest) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *UpdateProductRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DeleteProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProductRequest) Reset() {
+	*x = DeleteProductRequest{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductRequest) ProtoMessage() {}
+
+func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProductResponse) Reset() {
+	*x = DeleteProductResponse{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductResponse) ProtoMessage() {}
+
+func (x *DeleteProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductResponse.ProtoReflect.Descriptor instead.
+func (*DeleteProductResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+type StockItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockItem) Reset() {
+	*x = StockItem{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockItem) ProtoMessage() {}
+
+func (x *StockItem) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockItem.ProtoReflect.Descriptor instead.
+func (*StockItem) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StockItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockItem) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReserveStockRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ReserveStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservationId,proto3" json:"reservationId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReserveStockResponse) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+type ReleaseStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservationId,proto3" json:"reservationId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseStockRequest) Reset() {
+	*x = ReleaseStockRequest{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseStockRequest) ProtoMessage() {}
+
+func (x *ReleaseStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseStockRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseStockRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReleaseStockRequest) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+type ReleaseStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseStockResponse) Reset() {
+	*x = ReleaseStockResponse{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseStockResponse) ProtoMessage() {}
+
+func (x *ReleaseStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseStockResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseStockResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+type CommitStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservationId,proto3" json:"reservationId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitStockRequest) Reset() {
+	*x = CommitStockRequest{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitStockRequest) ProtoMessage() {}
+
+func (x *CommitStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitStockRequest.ProtoReflect.Descriptor instead.
+func (*CommitStockRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CommitStockRequest) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+type CommitStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitStockResponse) Reset() {
+	*x = CommitStockResponse{}
+	mi := &file_catalog_pb_catalog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitStockResponse) ProtoMessage() {}
+
+func (x *CommitStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_pb_catalog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitStockResponse.ProtoReflect.Descriptor instead.
+func (*CommitStockResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{16}
+}
+
 type ProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -272,7 +925,7 @@ type ProductResponse struct {
 
 func (x *ProductResponse) Reset() {
 	*x = ProductResponse{}
-	mi := &file_catalog_pb_catalog_proto_msgTypes[4]
+	mi := &file_catalog_pb_catalog_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -284,7 +937,7 @@ func (x *ProductResponse) String() string {
 func (*ProductResponse) ProtoMessage() {}
 
 func (x *ProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_catalog_pb_catalog_proto_msgTypes[4]
+	mi := &file_catalog_pb_catalog_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -297,7 +950,7 @@ func (x *ProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductResponse.ProtoReflect.Descriptor instead.
 func (*ProductResponse) Descriptor() ([]byte, []int) {
-	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{4}
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ProductResponse) GetProduct() *Product {
@@ -316,7 +969,7 @@ type ProductsResponse struct {
 
 func (x *ProductsResponse) Reset() {
 	*x = ProductsResponse{}
-	mi := &file_catalog_pb_catalog_proto_msgTypes[5]
+	mi := &file_catalog_pb_catalog_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -328,7 +981,7 @@ func (x *ProductsResponse) String() string {
 func (*ProductsResponse) ProtoMessage() {}
 
 func (x *ProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_catalog_pb_catalog_proto_msgTypes[5]
+	mi := &file_catalog_pb_catalog_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -341,7 +994,7 @@ func (x *ProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductsResponse.ProtoReflect.Descriptor instead.
 func (*ProductsResponse) Descriptor() ([]byte, []int) {
-	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{5}
+	return file_catalog_pb_catalog_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ProductsResponse) GetProducts() []*Product {
@@ -355,32 +1008,75 @@ var File_catalog_pb_catalog_proto protoreflect.FileDescriptor
 
 const file_catalog_pb_catalog_proto_rawDesc = "" +
 	"\n" +
-	"\x18catalog/pb/catalog.proto\x12\x02pb\"e\n" +
+	"\x18catalog/pb/catalog.proto\x12\x02pb\x1a google/protobuf/field_mask.proto\x1a\x14money/pb/money.proto\"\xa9\x01\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\"`\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\"\n" +
+	"\x05price\x18\x05 \x01(\v2\f.money.MoneyR\x05price\x12\x18\n" +
+	"\adeleted\x18\x06 \x01(\bR\adeleted\x12\x14\n" +
+	"\x05stock\x18\a \x01(\rR\x05stockJ\x04\b\x04\x10\x05\"\x8a\x01\n" +
 	"\x12PostProductRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x01R\x05price\"#\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\"\n" +
+	"\x05price\x18\x04 \x01(\v2\f.money.MoneyR\x05price\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\rR\x05stockJ\x04\b\x03\x10\x04\"?\n" +
 	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"d\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\x80\x01\n" +
 	"\x12GetProductsRequest\x12\x12\n" +
 	"\x04skip\x18\x01 \x01(\x04R\x04skip\x12\x12\n" +
 	"\x04take\x18\x02 \x01(\x04R\x04take\x12\x10\n" +
 	"\x03ids\x18\x03 \x03(\tR\x03ids\x12\x14\n" +
-	"\x05query\x18\x04 \x01(\tR\x05query\"8\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"v\n" +
+	"\x16GetProductsPageRequest\x12\x14\n" +
+	"\x05after\x18\x01 \x01(\tR\x05after\x12\x14\n" +
+	"\x05first\x18\x02 \x01(\x04R\x05first\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"L\n" +
+	"\vProductEdge\x12%\n" +
+	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\"b\n" +
+	"\x17GetProductsPageResponse\x12%\n" +
+	"\x05edges\x18\x01 \x03(\v2\x0f.pb.ProductEdgeR\x05edges\x12 \n" +
+	"\vhasNextPage\x18\x02 \x01(\bR\vhasNextPage\"y\n" +
+	"\x14UpdateProductRequest\x12%\n" +
+	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\x12:\n" +
+	"\n" +
+	"updateMask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"&\n" +
+	"\x14DeleteProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x17\n" +
+	"\x15DeleteProductResponse\"E\n" +
+	"\tStockItem\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\rR\bquantity\":\n" +
+	"\x13ReserveStockRequest\x12#\n" +
+	"\x05items\x18\x01 \x03(\v2\r.pb.StockItemR\x05items\"<\n" +
+	"\x14ReserveStockResponse\x12$\n" +
+	"\rreservationId\x18\x01 \x01(\tR\rreservationId\";\n" +
+	"\x13ReleaseStockRequest\x12$\n" +
+	"\rreservationId\x18\x01 \x01(\tR\rreservationId\"\x16\n" +
+	"\x14ReleaseStockResponse\":\n" +
+	"\x12CommitStockRequest\x12$\n" +
+	"\rreservationId\x18\x01 \x01(\tR\rreservationId\"\x15\n" +
+	"\x13CommitStockResponse\"8\n" +
 	"\x0fProductResponse\x12%\n" +
 	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\";\n" +
 	"\x10ProductsResponse\x12'\n" +
-	"\bproducts\x18\x01 \x03(\v2\v.pb.ProductR\bproducts2\xc3\x01\n" +
+	"\bproducts\x18\x01 \x03(\v2\v.pb.ProductR\bproducts2\xdb\x04\n" +
 	"\x0eCatalogService\x12:\n" +
 	"\vPostProduct\x12\x16.pb.PostProductRequest\x1a\x13.pb.ProductResponse\x128\n" +
 	"\n" +
 	"GetProduct\x12\x15.pb.GetProductRequest\x1a\x13.pb.ProductResponse\x12;\n" +
-	"\vGetProducts\x12\x16.pb.GetProductsRequest\x1a\x14.pb.ProductsResponseB\x1cZ\x1ago-microservice/catalog/pbb\x06proto3"
+	"\vGetProducts\x12\x16.pb.GetProductsRequest\x1a\x14.pb.ProductsResponse\x12J\n" +
+	"\x0fGetProductsPage\x12\x1a.pb.GetProductsPageRequest\x1a\x1b.pb.GetProductsPageResponse\x12>\n" +
+	"\rUpdateProduct\x12\x18.pb.UpdateProductRequest\x1a\x13.pb.ProductResponse\x12D\n" +
+	"\rDeleteProduct\x12\x18.pb.DeleteProductRequest\x1a\x19.pb.DeleteProductResponse\x12A\n" +
+	"\fReserveStock\x12\x17.pb.ReserveStockRequest\x1a\x18.pb.ReserveStockResponse\x12A\n" +
+	"\fReleaseStock\x12\x17.pb.ReleaseStockRequest\x1a\x18.pb.ReleaseStockResponse\x12>\n" +
+	"\vCommitStock\x12\x16.pb.CommitStockRequest\x1a\x17.pb.CommitStockResponseB\x1cZ\x1ago-microservice/catalog/pbb\x06proto3"
 
 var (
 	file_catalog_pb_catalog_proto_rawDescOnce sync.Once
@@ -394,29 +1090,63 @@ func file_catalog_pb_catalog_proto_rawDescGZIP() []byte {
 	return file_catalog_pb_catalog_proto_rawDescData
 }
 
-var file_catalog_pb_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_catalog_pb_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_catalog_pb_catalog_proto_goTypes = []any{
-	(*Product)(nil),            // 0: pb.Product
-	(*PostProductRequest)(nil), // 1: pb.PostProductRequest
-	(*GetProductRequest)(nil),  // 2: pb.GetProductRequest
-	(*GetProductsRequest)(nil), // 3: pb.GetProductsRequest
-	(*ProductResponse)(nil),    // 4: pb.ProductResponse
-	(*ProductsResponse)(nil),   // 5: pb.ProductsResponse
+	(*Product)(nil),                 // 0: pb.Product
+	(*PostProductRequest)(nil),      // 1: pb.PostProductRequest
+	(*GetProductRequest)(nil),       // 2: pb.GetProductRequest
+	(*GetProductsRequest)(nil),      // 3: pb.GetProductsRequest
+	(*GetProductsPageRequest)(nil),  // 4: pb.GetProductsPageRequest
+	(*ProductEdge)(nil),             // 5: pb.ProductEdge
+	(*GetProductsPageResponse)(nil), // 6: pb.GetProductsPageResponse
+	(*UpdateProductRequest)(nil),    // 7: pb.UpdateProductRequest
+	(*DeleteProductRequest)(nil),    // 8: pb.DeleteProductRequest
+	(*DeleteProductResponse)(nil),   // 9: pb.DeleteProductResponse
+	(*StockItem)(nil),               // 10: pb.StockItem
+	(*ReserveStockRequest)(nil),     // 11: pb.ReserveStockRequest
+	(*ReserveStockResponse)(nil),    // 12: pb.ReserveStockResponse
+	(*ReleaseStockRequest)(nil),     // 13: pb.ReleaseStockRequest
+	(*ReleaseStockResponse)(nil),    // 14: pb.ReleaseStockResponse
+	(*CommitStockRequest)(nil),      // 15: pb.CommitStockRequest
+	(*CommitStockResponse)(nil),     // 16: pb.CommitStockResponse
+	(*ProductResponse)(nil),         // 17: pb.ProductResponse
+	(*ProductsResponse)(nil),        // 18: pb.ProductsResponse
+	(*pb.Money)(nil),                // 19: money.Money
+	(*fieldmaskpb.FieldMask)(nil),   // 20: google.protobuf.FieldMask
 }
 var file_catalog_pb_catalog_proto_depIdxs = []int32{
-	0, // 0: pb.ProductResponse.product:type_name -> pb.Product
-	0, // 1: pb.ProductsResponse.products:type_name -> pb.Product
-	1, // 2: pb.CatalogService.PostProduct:input_type -> pb.PostProductRequest
-	2, // 3: pb.CatalogService.GetProduct:input_type -> pb.GetProductRequest
-	3, // 4: pb.CatalogService.GetProducts:input_type -> pb.GetProductsRequest
-	4, // 5: pb.CatalogService.PostProduct:output_type -> pb.ProductResponse
-	4, // 6: pb.CatalogService.GetProduct:output_type -> pb.ProductResponse
-	5, // 7: pb.CatalogService.GetProducts:output_type -> pb.ProductsResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	19, // 0: pb.Product.price:type_name -> money.Money
+	19, // 1: pb.PostProductRequest.price:type_name -> money.Money
+	0,  // 2: pb.ProductEdge.product:type_name -> pb.Product
+	5,  // 3: pb.GetProductsPageResponse.edges:type_name -> pb.ProductEdge
+	0,  // 4: pb.UpdateProductRequest.product:type_name -> pb.Product
+	20, // 5: pb.UpdateProductRequest.updateMask:type_name -> google.protobuf.FieldMask
+	10, // 6: pb.ReserveStockRequest.items:type_name -> pb.StockItem
+	0,  // 7: pb.ProductResponse.product:type_name -> pb.Product
+	0,  // 8: pb.ProductsResponse.products:type_name -> pb.Product
+	1,  // 9: pb.CatalogService.PostProduct:input_type -> pb.PostProductRequest
+	2,  // 10: pb.CatalogService.GetProduct:input_type -> pb.GetProductRequest
+	3,  // 11: pb.CatalogService.GetProducts:input_type -> pb.GetProductsRequest
+	4,  // 12: pb.CatalogService.GetProductsPage:input_type -> pb.GetProductsPageRequest
+	7,  // 13: pb.CatalogService.UpdateProduct:input_type -> pb.UpdateProductRequest
+	8,  // 14: pb.CatalogService.DeleteProduct:input_type -> pb.DeleteProductRequest
+	11, // 15: pb.CatalogService.ReserveStock:input_type -> pb.ReserveStockRequest
+	13, // 16: pb.CatalogService.ReleaseStock:input_type -> pb.ReleaseStockRequest
+	15, // 17: pb.CatalogService.CommitStock:input_type -> pb.CommitStockRequest
+	17, // 18: pb.CatalogService.PostProduct:output_type -> pb.ProductResponse
+	17, // 19: pb.CatalogService.GetProduct:output_type -> pb.ProductResponse
+	18, // 20: pb.CatalogService.GetProducts:output_type -> pb.ProductsResponse
+	6,  // 21: pb.CatalogService.GetProductsPage:output_type -> pb.GetProductsPageResponse
+	17, // 22: pb.CatalogService.UpdateProduct:output_type -> pb.ProductResponse
+	9,  // 23: pb.CatalogService.DeleteProduct:output_type -> pb.DeleteProductResponse
+	12, // 24: pb.CatalogService.ReserveStock:output_type -> pb.ReserveStockResponse
+	14, // 25: pb.CatalogService.ReleaseStock:output_type -> pb.ReleaseStockResponse
+	16, // 26: pb.CatalogService.CommitStock:output_type -> pb.CommitStockResponse
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_catalog_pb_catalog_proto_init() }
@@ -430,7 +1160,7 @@ func file_catalog_pb_catalog_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_catalog_pb_catalog_proto_rawDesc), len(file_catalog_pb_catalog_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -6,19 +6,55 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"go-microservice/money"
+	"go-microservice/platform"
 	"io"
+	"strconv"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
 )
 
+var ErrProductNotFound = platform.NewError(platform.NotFound, "PRODUCT_NOT_FOUND", "product not found")
+
 type Repository interface {
 	Close() error
-	PutProduct(ctx context.Context, p Product) error
+	// Ping проверяет, что хранилище доступно
+	Ping(ctx context.Context) error
+	// PutProduct сохраняет новый товар вместе с событиями о нём
+	PutProduct(ctx context.Context, p Product, events ...platform.Event) error
 	GetProductByID(ctx context.Context, id string) (*Product, error)
 	ListProducts(ctx context.Context, skip, take uint64) ([]Product, error)
 	ListProductsWithIDs(ctx context.Context, ids []string) ([]Product, error)
 	SearchProducts(ctx context.Context, query string, skip, take uint64) ([]Product, error)
+	// ListProductsAfter возвращает до limit неудалённых товаров с ID больше after в порядке возрастания ID
+	ListProductsAfter(ctx context.Context, after string, limit uint64) ([]Product, error)
+	// SearchProductsAfter продолжает поиск после товара after (nil - с начала).
+	// Выдача упорядочена по убыванию релевантности, при равной релевантности - по ID.
+	SearchProductsAfter(ctx context.Context, query string, after *SearchAfter, limit uint64) ([]ProductHit, error)
+	// UpdateProduct меняет у товара p.ID только поля paths (FieldName, FieldDescription, FieldPrice, FieldStock)
+	// и сохраняет события об этом
+	UpdateProduct(ctx context.Context, p Product, paths []string, events ...platform.Event) error
+	DeleteProduct(ctx context.Context, id string) error
+	// ReserveStock уменьшает остатки по всем позициям r и сохраняет резерв.
+	// Если хотя бы одной позиции не хватает, остатки не меняются и возвращается *OutOfStockError.
+	ReserveStock(ctx context.Context, r Reservation) error
+	// CloseReservation переводит резерв в status (committed или released).
+	// Повторный перевод в тот же статус ничего не делает, в другой - ErrReservationClosed.
+	CloseReservation(ctx context.Context, id string, status ReservationStatus) error
+	platform.OutboxStore
+}
+
+// ProductHit - найденный товар и его релевантность
+type ProductHit struct {
+	Product Product
+	Score   float64
+}
+
+// SearchAfter - последний товар предыдущей страницы поиска
+type SearchAfter struct {
+	Score float64
+	ID    string
 }
 
 type ElasticRepository struct {
@@ -29,15 +65,49 @@ type searchResponse struct {
 	Hits struct {
 		Hits []struct {
 			Source Product `json:"_source"`
+			// Score пуст, если выдача отсортирована не по релевантности
+			Score *float64 `json:"_score"`
 		} `json:"hits"`
 	} `json:"hits"`
 }
 
-// type ProductDocument struct {
-// 	Name        string  `json:"name"`
-// 	Description string  `json:"description"`
-// 	Price       float64 `json:"price"`
-// }
+// idSortField - поле для сортировки по ID. Индекс создаётся динамическим маппингом,
+// который делает id текстом с подполем keyword.
+const idSortField = "id.keyword"
+
+// legacyPriceCurrency - валюта документов, сохранённых до перехода на money.Money,
+// когда price хранился в индексе числом без валюты.
+const legacyPriceCurrency = "USD"
+
+// UnmarshalJSON читает документы каталога в обоих форматах цены:
+// {"amount": 1999, "currency": "USD"} и устаревшее число 19.99.
+// Устаревшие документы переписываются в новом формате при следующем PutProduct.
+func (p *Product) UnmarshalJSON(data []byte) error {
+	type product Product
+	var doc struct {
+		product
+		Price json.RawMessage `json:"price"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	*p = Product(doc.product)
+
+	price := bytes.TrimSpace(doc.Price)
+	if len(price) == 0 || bytes.Equal(price, []byte("null")) {
+		return nil
+	}
+	if price[0] == '{' {
+		return json.Unmarshal(price, &p.Price)
+	}
+
+	amount, err := strconv.ParseFloat(string(price), 64)
+	if err != nil {
+		return fmt.Errorf("invalid legacy price %s: %w", price, err)
+	}
+	p.Price = money.FromFloat(amount, legacyPriceCurrency)
+	return nil
+}
 
 func NewElasticReposytory(url string) (Repository, error) {
 	c, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{url}})
@@ -45,15 +115,25 @@ func NewElasticReposytory(url string) (Repository, error) {
 		return nil, err
 	}
 
-	res, err := c.Ping()
-	if err != nil {
+	r := &ElasticRepository{
+		client: c,
+	}
+	if err := r.Ping(context.Background()); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	return r, nil
+}
 
-	return &ElasticRepository{
-		client: c,
-	}, nil
+func (r *ElasticRepository) Ping(ctx context.Context) error {
+	res, err := r.client.Ping(r.client.Ping.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch ping: %s", res.Status())
+	}
+	return nil
 }
 
 func (r *ElasticRepository) Close() error {
@@ -63,15 +143,30 @@ func (r *ElasticRepository) Close() error {
 	return nil
 }
 
-func (r *ElasticRepository) PutProduct(ctx context.Context, p Product) error {
-	data, err := json.Marshal(p)
+// PutProduct сохраняет товар, затем события в индекс outbox. Транзакций в Elasticsearch нет,
+// поэтому если события сохранить не удалось, товар удаляется.
+func (r *ElasticRepository) PutProduct(ctx context.Context, p Product, events ...platform.Event) error {
+	if err := r.indexDocument(ctx, "catalog", p.ID, p); err != nil {
+		return err
+	}
+	for _, e := range events {
+		if err := r.indexDocument(ctx, outboxIndex, e.ID, e); err != nil {
+			return errors.Join(err, r.deleteDocument(context.WithoutCancel(ctx), "catalog", p.ID))
+		}
+	}
+	return nil
+}
+
+// indexDocument создаёт или перезаписывает документ id в index
+func (r *ElasticRepository) indexDocument(ctx context.Context, index, id string, doc interface{}) error {
+	data, err := json.Marshal(doc)
 	if err != nil {
 		return err
 	}
 
 	req := esapi.IndexRequest{
-		Index:      "catalog",
-		DocumentID: p.ID,
+		Index:      index,
+		DocumentID: id,
 		Body:       bytes.NewReader(data),
 		Refresh:    "true",
 	}
@@ -83,40 +178,301 @@ func (r *ElasticRepository) PutProduct(ctx context.Context, p Product) error {
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return errors.New("elasticsearch error")
+		return fmt.Errorf("error indexing document in %s: %s", index, res.String())
+	}
+	return nil
+}
+
+// deleteDocument удаляет документ id из index; отсутствующий документ не ошибка
+func (r *ElasticRepository) deleteDocument(ctx context.Context, index, id string) error {
+	req := esapi.DeleteRequest{
+		Index:      index,
+		DocumentID: id,
+		Refresh:    "true",
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != 404 {
+		return fmt.Errorf("error deleting document from %s: %s", index, res.String())
 	}
 	return nil
 }
 
 func (r *ElasticRepository) GetProductByID(ctx context.Context, id string) (*Product, error) {
+	var p Product
+	if err := r.getDocument(ctx, "catalog", id, &p, ErrProductNotFound); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// getDocument читает _source документа в v, для отсутствующего документа возвращает notFound
+func (r *ElasticRepository) getDocument(ctx context.Context, index, id string, v interface{}, notFound error) error {
 	req := esapi.GetRequest{
-		Index:      "catalog",
+		Index:      index,
 		DocumentID: id,
 	}
 
 	res, err := req.Do(ctx, r.client)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer res.Body.Close()
+
 	if res.StatusCode == 404 {
-		return nil, fmt.Errorf("product not found")
+		return notFound
 	}
 	if res.IsError() {
-		return nil, fmt.Errorf("error getting product: %s", res.String())
+		return fmt.Errorf("error getting document from %s: %s", index, res.String())
 	}
 
 	var doc struct {
-		Source Product `json:"_source"`
+		Source json.RawMessage `json:"_source"`
 	}
-
 	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
-		return nil, err
+		return err
+	}
+	return json.Unmarshal(doc.Source, v)
+}
+
+// UpdateProduct отправляет частичный документ только с полями paths, затем, как PutProduct,
+// сохраняет события. Для отсутствующего товара возвращает ErrProductNotFound.
+// Вернуть прежние поля без риска затереть параллельное изменение нельзя, поэтому события
+// пишутся и после отмены ctx; если запись всё же не удалась, изменение остаётся без события.
+func (r *ElasticRepository) UpdateProduct(ctx context.Context, p Product, paths []string, events ...platform.Event) error {
+	doc := make(map[string]interface{}, len(paths))
+	for _, path := range paths {
+		switch path {
+		case FieldName:
+			doc[path] = p.Name
+		case FieldDescription:
+			doc[path] = p.Description
+		case FieldPrice:
+			doc[path] = p.Price
+		case FieldStock:
+			doc[path] = p.Stock
+		default:
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidUpdateMask, path)
+		}
+	}
+
+	if err := r.updateDocument(ctx, p.ID, doc); err != nil {
+		return err
+	}
+	ctx = context.WithoutCancel(ctx)
+	for _, e := range events {
+		if err := r.indexDocument(ctx, outboxIndex, e.ID, e); err != nil {
+			return fmt.Errorf("product %s updated, but event %s is not saved: %w", p.ID, e.ID, err)
+		}
+	}
+	return nil
+}
+
+// DeleteProduct помечает товар удалённым, сам документ остаётся в индексе
+func (r *ElasticRepository) DeleteProduct(ctx context.Context, id string) error {
+	return r.updateDocument(ctx, id, map[string]interface{}{"deleted": true})
+}
+
+// updateDocument выполняет частичное обновление документа (_update с doc).
+// При конфликте с параллельным изменением, например списанием остатка, запрос повторяется.
+func (r *ElasticRepository) updateDocument(ctx context.Context, id string, doc interface{}) error {
+	data, err := json.Marshal(map[string]interface{}{"doc": doc})
+	if err != nil {
+		return err
+	}
+
+	req := esapi.UpdateRequest{
+		Index:           "catalog",
+		DocumentID:      id,
+		Body:            bytes.NewReader(data),
+		Refresh:         "true",
+		RetryOnConflict: esapi.IntPtr(3),
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return ErrProductNotFound
+	}
+	if res.IsError() {
+		return fmt.Errorf("error updating product: %s", res.String())
+	}
+	return nil
+}
+
+const reservationsIndex = "reservations"
+
+// stockScript меняет остаток на params.delta. Если остатка не хватает, документ не меняется (noop).
+// Документы, сохранённые до учёта остатков, не содержат stock и считаются пустыми.
+const stockScript = `long stock = ctx._source.stock == null ? 0 : ctx._source.stock;
+if (stock + params.delta < 0) { ctx.op = 'noop' } else { ctx._source.stock = stock + params.delta }`
+
+// reservationScript переводит резерв из params.from в params.to, в любом другом статусе - noop
+const reservationScript = `if (ctx._source.status == params.from) { ctx._source.status = params.to } else { ctx.op = 'noop' }`
+
+// ReserveStock списывает остатки по одной позиции. Elasticsearch не умеет транзакции между документами,
+// поэтому при нехватке уже списанные позиции возвращаются на склад.
+func (r *ElasticRepository) ReserveStock(ctx context.Context, res Reservation) error {
+	reserved := make([]StockItem, 0, len(res.Items))
+	for _, item := range res.Items {
+		result, err := r.runScript(ctx, "catalog", item.ProductID, stockScript,
+			map[string]interface{}{"delta": -int64(item.Quantity)}, fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID))
+		if err == nil && result == "noop" {
+			err = &OutOfStockError{ProductID: item.ProductID}
+		}
+		if err != nil {
+			return errors.Join(err, r.restock(ctx, reserved))
+		}
+		reserved = append(reserved, item)
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return errors.Join(err, r.restock(ctx, reserved))
+	}
+
+	req := esapi.CreateRequest{
+		Index:      reservationsIndex,
+		DocumentID: res.ID,
+		Body:       bytes.NewReader(data),
+		Refresh:    "true",
+	}
+	resp, err := req.Do(ctx, r.client)
+	if err != nil {
+		return errors.Join(err, r.restock(ctx, reserved))
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return errors.Join(fmt.Errorf("error saving reservation: %s", resp.String()), r.restock(ctx, reserved))
+	}
+	return nil
+}
+
+// CloseReservation фиксирует или отменяет резерв
+func (r *ElasticRepository) CloseReservation(ctx context.Context, id string, status ReservationStatus) error {
+	var res Reservation
+	if err := r.getDocument(ctx, reservationsIndex, id, &res, ErrReservationNotFound); err != nil {
+		return err
+	}
+
+	result, err := r.runScript(ctx, reservationsIndex, id, reservationScript,
+		map[string]interface{}{"from": ReservationReserved, "to": status}, ErrReservationNotFound)
+	if err != nil {
+		return err
+	}
+	if result == "noop" {
+		// Резерв уже закрыт: перечитываем, чтобы отличить повторный запрос от конфликта
+		if err := r.getDocument(ctx, reservationsIndex, id, &res, ErrReservationNotFound); err != nil {
+			return err
+		}
+		if res.Status == status {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrReservationClosed, res.Status)
+	}
+
+	if status == ReservationReleased {
+		return r.restock(ctx, res.Items)
+	}
+	return nil
+}
+
+// restock возвращает позиции на склад. Выполняется и после отмены ctx, чтобы не потерять остатки.
+func (r *ElasticRepository) restock(ctx context.Context, items []StockItem) error {
+	ctx = context.WithoutCancel(ctx)
+	var errs []error
+	for _, item := range items {
+		_, err := r.runScript(ctx, "catalog", item.ProductID, stockScript,
+			map[string]interface{}{"delta": int64(item.Quantity)}, fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restock %s: %w", item.ProductID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runScript выполняет _update со скриптом и возвращает result из ответа (updated или noop).
+// Для отсутствующего документа возвращает notFound.
+func (r *ElasticRepository) runScript(ctx context.Context, index, id, source string, params map[string]interface{}, notFound error) (string, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"script": map[string]interface{}{
+			"source": source,
+			"lang":   "painless",
+			"params": params,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req := esapi.UpdateRequest{
+		Index:           index,
+		DocumentID:      id,
+		Body:            bytes.NewReader(data),
+		Refresh:         "true",
+		RetryOnConflict: esapi.IntPtr(3),
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return "", notFound
+	}
+	if res.IsError() {
+		return "", fmt.Errorf("error updating %s in %s: %s", id, index, res.String())
+	}
+
+	var body struct {
+		Result string `json:"result"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	return body.Result, nil
+}
+
+// notDeleted оборачивает запрос так, чтобы удалённые товары не попадали в выдачу
+func notDeleted(query map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": query,
+			"must_not": map[string]interface{}{
+				"term": map[string]interface{}{"deleted": true},
+			},
+		},
 	}
-	return &doc.Source, nil
 }
 
 // executeSearch выполняет поисковый запрос и парсит ответ
 func (r *ElasticRepository) executeSearch(ctx context.Context, index string, body io.Reader) ([]Product, error) {
+	hits, err := r.executeSearchHits(ctx, index, body)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]Product, len(hits))
+	for i, hit := range hits {
+		products[i] = hit.Product
+	}
+	return products, nil
+}
+
+// executeSearchHits выполняет поисковый запрос и возвращает товары вместе с релевантностью
+func (r *ElasticRepository) executeSearchHits(ctx context.Context, index string, body io.Reader) ([]ProductHit, error) {
 	res, err := r.client.Search(
 		r.client.Search.WithContext(ctx),
 		r.client.Search.WithIndex(index),
@@ -144,12 +500,16 @@ func (r *ElasticRepository) executeSearch(ctx context.Context, index string, bod
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	products := make([]Product, 0, len(result.Hits.Hits))
+	hits := make([]ProductHit, 0, len(result.Hits.Hits))
 	for _, hit := range result.Hits.Hits {
-		products = append(products, hit.Source)
+		h := ProductHit{Product: hit.Source}
+		if hit.Score != nil {
+			h.Score = *hit.Score
+		}
+		hits = append(hits, h)
 	}
 
-	return products, nil
+	return hits, nil
 }
 
 // ListProducts возвращает список товаров с пагинацией
@@ -157,9 +517,9 @@ func (r *ElasticRepository) ListProducts(ctx context.Context, skip, take uint64)
 	query := map[string]interface{}{
 		"from": skip,
 		"size": take,
-		"query": map[string]interface{}{
+		"query": notDeleted(map[string]interface{}{
 			"match_all": struct{}{},
-		},
+		}),
 	}
 
 	var buf bytes.Buffer
@@ -170,7 +530,7 @@ func (r *ElasticRepository) ListProducts(ctx context.Context, skip, take uint64)
 	return r.executeSearch(ctx, "catalog", &buf)
 }
 
-// ListProductsWithIDs возвращает товары по их IDs
+// ListProductsWithIDs возвращает товары по их IDs, включая удалённые
 func (r *ElasticRepository) ListProductsWithIDs(ctx context.Context, ids []string) ([]Product, error) {
 	query := map[string]interface{}{
 		"size": len(ids),
@@ -189,19 +549,74 @@ func (r *ElasticRepository) ListProductsWithIDs(ctx context.Context, ids []strin
 	return r.executeSearch(ctx, "catalog", &buf)
 }
 
+// ListProductsAfter возвращает страницу товаров через search_after.
+// В отличие от from, позиция не сдвигается, когда перед ней добавляются товары.
+func (r *ElasticRepository) ListProductsAfter(ctx context.Context, after string, limit uint64) ([]Product, error) {
+	query := map[string]interface{}{
+		"size": limit,
+		"query": notDeleted(map[string]interface{}{
+			"match_all": struct{}{},
+		}),
+		"sort": []interface{}{
+			map[string]interface{}{idSortField: "asc"},
+		},
+	}
+	if after != "" {
+		query["search_after"] = []interface{}{after}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	return r.executeSearch(ctx, "catalog", &buf)
+}
+
+// SearchProductsAfter выполняет тот же запрос, что SearchProducts, но страницы берёт через search_after.
+// ID в сортировке нужен, чтобы порядок товаров с равной релевантностью был однозначным.
+func (r *ElasticRepository) SearchProductsAfter(ctx context.Context, query string, after *SearchAfter, limit uint64) ([]ProductHit, error) {
+	searchQuery := map[string]interface{}{
+		"size": limit,
+		"query": notDeleted(map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":     query,
+				"fields":    []string{"name", "description"},
+				"type":      "best_fields",
+				"fuzziness": "AUTO",
+			},
+		}),
+		"sort": []interface{}{
+			map[string]interface{}{"_score": "desc"},
+			map[string]interface{}{idSortField: "asc"},
+		},
+		"track_scores": true,
+	}
+	if after != nil {
+		searchQuery["search_after"] = []interface{}{after.Score, after.ID}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	return r.executeSearchHits(ctx, "catalog", &buf)
+}
+
 func (r *ElasticRepository) SearchProducts(ctx context.Context, query string, skip, take uint64) ([]Product, error) {
 	// 1. Формируем multi-match запрос
 	searchQuery := map[string]interface{}{
 		"from": skip,
 		"size": take,
-		"query": map[string]interface{}{
+		"query": notDeleted(map[string]interface{}{
 			"multi_match": map[string]interface{}{
 				"query":     query,
 				"fields":    []string{"name", "description"},
 				"type":      "best_fields", // Аналогично NewMultiMatchQuery в olivere
 				"fuzziness": "AUTO",        // Опционально: нечёткий поиск
 			},
-		},
+		}),
 	}
 
 	// 2. Сериализуем запрос
@@ -212,3 +627,62 @@ func (r *ElasticRepository) SearchProducts(ctx context.Context, query string, sk
 
 	return r.executeSearch(ctx, "catalog", &buf)
 }
+
+const outboxIndex = "outbox"
+
+// PendingEvents возвращает события из индекса outbox. Пока событий не было, индекса нет.
+func (r *ElasticRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	query := map[string]interface{}{
+		"size": limit,
+		"sort": []interface{}{
+			map[string]interface{}{"occurred_at": "asc"},
+			map[string]interface{}{idSortField: "asc"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, err
+	}
+
+	res, err := r.client.Search(
+		r.client.Search.WithContext(ctx),
+		r.client.Search.WithIndex(outboxIndex),
+		r.client.Search.WithBody(&buf),
+		r.client.Search.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("error searching %s: %s", outboxIndex, res.String())
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source platform.Event `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	events := make([]platform.Event, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		events[i] = hit.Source
+	}
+	return events, nil
+}
+
+// DeleteEvents удаляет опубликованные события из индекса outbox
+func (r *ElasticRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		if err := r.deleteDocument(ctx, outboxIndex, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,490 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	eventspb "go-microservice/events/pb"
+	"go-microservice/money"
+	"go-microservice/platform"
+	"go-microservice/platform/storetest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/segmentio/ksuid"
+)
+
+// TestRepositoryContract гоняет контрактные тесты на всех реализациях Repository.
+// Elasticsearch подключается только если задан TEST_CATALOG_ELASTIC_URL; индексы catalog, reservations и outbox при этом пересоздаются.
+func TestRepositoryContract(t *testing.T) {
+	tests := []storetest.Test[Repository]{
+		{Name: "put and get", Run: testPutAndGetProduct},
+		{Name: "get missing", Run: testGetMissingProduct},
+		{Name: "put overwrites", Run: testPutOverwritesProduct},
+		{Name: "list pagination", Run: testListProducts},
+		{Name: "list with ids", Run: testListProductsWithIDs},
+		{Name: "search", Run: testSearchProducts},
+		{Name: "list after", Run: testListProductsAfter},
+		{Name: "search after", Run: testSearchProductsAfter},
+		{Name: "update", Run: testUpdateProduct},
+		{Name: "delete", Run: testDeleteProduct},
+		{Name: "reserve stock", Run: testReserveStock},
+		{Name: "close reservation", Run: testCloseReservation},
+		{Name: "outbox", Run: testOutbox},
+	}
+	storetest.RunContract(t, tests, func() Repository { return NewInMemoryRepository() }, storetest.Store[Repository]{
+		Name: "elastic",
+		Env:  "TEST_CATALOG_ELASTIC_URL",
+		Open: func(t *testing.T, url string) Repository {
+			r, err := NewElasticReposytory(url)
+			if err != nil {
+				t.Fatalf("connect to elasticsearch: %v", err)
+			}
+			res, err := esapi.IndicesDeleteRequest{Index: []string{"catalog", reservationsIndex, outboxIndex}, IgnoreUnavailable: esapi.BoolPtr(true)}.Do(context.Background(), r.(*ElasticRepository).client)
+			if err != nil {
+				t.Fatalf("delete catalog indices: %v", err)
+			}
+			res.Body.Close()
+			t.Cleanup(func() { r.Close() })
+			return r
+		},
+	})
+}
+
+func putProducts(t *testing.T, r Repository, products ...Product) {
+	t.Helper()
+	for _, p := range products {
+		if err := r.PutProduct(context.Background(), p); err != nil {
+			t.Fatalf("PutProduct(%s): %v", p.ID, err)
+		}
+	}
+}
+
+func newProduct(name, description string, cents int64) Product {
+	return Product{
+		ID:          ksuid.New().String(),
+		Name:        name,
+		Description: description,
+		Price:       money.New(cents, "USD"),
+	}
+}
+
+func productIDs(products []Product) []string {
+	ids := make([]string, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testPutAndGetProduct(t *testing.T, r Repository) {
+	want := newProduct("Keyboard", "Mechanical keyboard", 4999)
+	putProducts(t, r, want)
+
+	got, err := r.GetProductByID(context.Background(), want.ID)
+	if err != nil {
+		t.Fatalf("GetProductByID: %v", err)
+	}
+	if *got != want {
+		t.Errorf("GetProductByID = %+v, want %+v", *got, want)
+	}
+}
+
+func testGetMissingProduct(t *testing.T, r Repository) {
+	_, err := r.GetProductByID(context.Background(), ksuid.New().String())
+	if !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("GetProductByID error = %v, want %v", err, ErrProductNotFound)
+	}
+}
+
+func testPutOverwritesProduct(t *testing.T, r Repository) {
+	p := newProduct("Mouse", "Wireless mouse", 1999)
+	putProducts(t, r, p)
+
+	p.Name = "Gaming mouse"
+	putProducts(t, r, p)
+
+	got, err := r.GetProductByID(context.Background(), p.ID)
+	if err != nil {
+		t.Fatalf("GetProductByID: %v", err)
+	}
+	if got.Name != p.Name {
+		t.Errorf("Name = %q, want %q", got.Name, p.Name)
+	}
+}
+
+func testListProducts(t *testing.T, r Repository) {
+	ctx := context.Background()
+	products := []Product{
+		newProduct("A", "first", 100),
+		newProduct("B", "second", 200),
+		newProduct("C", "third", 300),
+	}
+	putProducts(t, r, products...)
+
+	page1, err := r.ListProducts(ctx, 0, 2)
+	if err != nil {
+		t.Fatalf("ListProducts(0, 2): %v", err)
+	}
+	page2, err := r.ListProducts(ctx, 2, 2)
+	if err != nil {
+		t.Fatalf("ListProducts(2, 2): %v", err)
+	}
+	if len(page1) != 2 || len(page2) != 1 {
+		t.Fatalf("page sizes = %d, %d, want 2, 1", len(page1), len(page2))
+	}
+
+	got := productIDs(append(page1, page2...))
+	if want := productIDs(products); !equalIDs(got, want) {
+		t.Errorf("listed ids = %v, want %v", got, want)
+	}
+}
+
+func testListProductsWithIDs(t *testing.T, r Repository) {
+	a := newProduct("A", "first", 100)
+	b := newProduct("B", "second", 200)
+	c := newProduct("C", "third", 300)
+	putProducts(t, r, a, b, c)
+
+	got, err := r.ListProductsWithIDs(context.Background(), []string{a.ID, c.ID, ksuid.New().String()})
+	if err != nil {
+		t.Fatalf("ListProductsWithIDs: %v", err)
+	}
+	if ids, want := productIDs(got), productIDs([]Product{a, c}); !equalIDs(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+}
+
+func testSearchProducts(t *testing.T, r Repository) {
+	keyboard := newProduct("Mechanical keyboard", "Clicky switches", 4999)
+	mouse := newProduct("Wireless mouse", "Ergonomic design", 1999)
+	monitor := newProduct("Monitor", "27 inch display with thin bezels", 19999)
+	putProducts(t, r, keyboard, mouse, monitor)
+
+	tests := []struct {
+		query string
+		want  []Product
+	}{
+		{query: "keyboard", want: []Product{keyboard}},
+		{query: "KEYBOARD", want: []Product{keyboard}},
+		{query: "keybaord", want: []Product{keyboard}},
+		{query: "ergonomic", want: []Product{mouse}},
+		{query: "wireless display", want: []Product{mouse, monitor}},
+		{query: "toaster", want: nil},
+	}
+	for _, tt := range tests {
+		got, err := r.SearchProducts(context.Background(), tt.query, 0, 10)
+		if err != nil {
+			t.Fatalf("SearchProducts(%q): %v", tt.query, err)
+		}
+		if ids, want := productIDs(got), productIDs(tt.want); !equalIDs(ids, want) {
+			t.Errorf("SearchProducts(%q) ids = %v, want %v", tt.query, ids, want)
+		}
+	}
+}
+
+func testListProductsAfter(t *testing.T, r Repository) {
+	ctx := context.Background()
+	products := []Product{
+		newProduct("A", "first", 100),
+		newProduct("B", "second", 200),
+		newProduct("C", "third", 300),
+	}
+	putProducts(t, r, products...)
+	want := productIDs(products)
+
+	var got []string
+	after := ""
+	for range len(products) + 1 {
+		page, err := r.ListProductsAfter(ctx, after, 2)
+		if err != nil {
+			t.Fatalf("ListProductsAfter(%q, 2): %v", after, err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, p := range page {
+			got = append(got, p.ID)
+		}
+		after = page[len(page)-1].ID
+	}
+	// Страницы идут по возрастанию ID без пропусков и повторов
+	if !equalIDs(got, want) {
+		t.Errorf("listed ids = %v, want %v", got, want)
+	}
+}
+
+func testSearchProductsAfter(t *testing.T, r Repository) {
+	ctx := context.Background()
+	exact := newProduct("Laptop stand", "Aluminium", 2999)
+	other := newProduct("Laptop sleeve", "Neoprene", 1999)
+	partial := newProduct("Desk lamp", "Fits next to a laptop", 999)
+	putProducts(t, r, exact, other, partial)
+
+	all, err := r.SearchProductsAfter(ctx, "laptop stand", nil, 10)
+	if err != nil {
+		t.Fatalf("SearchProductsAfter: %v", err)
+	}
+	if len(all) != 3 || all[0].Product.ID != exact.ID {
+		t.Fatalf("SearchProductsAfter returned %d hits, first %+v, want 3 starting with %s", len(all), all, exact.ID)
+	}
+
+	var got []ProductHit
+	var after *SearchAfter
+	for range len(all) + 1 {
+		page, err := r.SearchProductsAfter(ctx, "laptop stand", after, 1)
+		if err != nil {
+			t.Fatalf("SearchProductsAfter: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		got = append(got, page...)
+		last := page[len(page)-1]
+		after = &SearchAfter{Score: last.Score, ID: last.Product.ID}
+	}
+	if len(got) != len(all) {
+		t.Fatalf("paged search returned %d hits, want %d", len(got), len(all))
+	}
+	for i := range all {
+		if got[i].Product.ID != all[i].Product.ID {
+			t.Errorf("hit %d = %s, want %s", i, got[i].Product.ID, all[i].Product.ID)
+		}
+	}
+}
+
+func testUpdateProduct(t *testing.T, r Repository) {
+	ctx := context.Background()
+	p := newProduct("Mouse", "Wireless mouse", 1999)
+	p.Stock = 5
+	putProducts(t, r, p)
+
+	// Остаток, списанный после чтения товара, не должен вернуться при правке других полей
+	if err := r.ReserveStock(ctx, newReservation(StockItem{ProductID: p.ID, Quantity: 1})); err != nil {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+	want := p
+	want.Name = "Gaming mouse"
+	want.Price = money.New(2499, "USD")
+	want.Stock--
+
+	update := Product{ID: p.ID, Name: want.Name, Description: "ignored", Price: want.Price, Stock: p.Stock}
+	if err := r.UpdateProduct(ctx, update, []string{FieldName, FieldPrice}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	got, err := r.GetProductByID(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("GetProductByID: %v", err)
+	}
+	if *got != want {
+		t.Errorf("GetProductByID = %+v, want %+v", *got, want)
+	}
+
+	if err := r.UpdateProduct(ctx, newProduct("Ghost", "", 100), []string{FieldName}); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("UpdateProduct missing error = %v, want %v", err, ErrProductNotFound)
+	}
+}
+
+func testDeleteProduct(t *testing.T, r Repository) {
+	ctx := context.Background()
+	kept := newProduct("Keyboard", "Mechanical keyboard", 4999)
+	deleted := newProduct("Keyboard cover", "Dust cover for keyboard", 999)
+	putProducts(t, r, kept, deleted)
+
+	if err := r.DeleteProduct(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+
+	// Удалённый товар остаётся доступен по ID, чтобы старые заказы могли его показать
+	got, err := r.GetProductByID(ctx, deleted.ID)
+	if err != nil {
+		t.Fatalf("GetProductByID: %v", err)
+	}
+	if !got.Deleted || got.Name != deleted.Name {
+		t.Errorf("GetProductByID = %+v, want deleted %s", *got, deleted.Name)
+	}
+	withIDs, err := r.ListProductsWithIDs(ctx, []string{kept.ID, deleted.ID})
+	if err != nil {
+		t.Fatalf("ListProductsWithIDs: %v", err)
+	}
+	if ids, want := productIDs(withIDs), productIDs([]Product{kept, deleted}); !equalIDs(ids, want) {
+		t.Errorf("ListProductsWithIDs ids = %v, want %v", ids, want)
+	}
+
+	listed, err := r.ListProducts(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	if ids, want := productIDs(listed), productIDs([]Product{kept}); !equalIDs(ids, want) {
+		t.Errorf("ListProducts ids = %v, want %v", ids, want)
+	}
+	found, err := r.SearchProducts(ctx, "keyboard", 0, 10)
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if ids, want := productIDs(found), productIDs([]Product{kept}); !equalIDs(ids, want) {
+		t.Errorf("SearchProducts ids = %v, want %v", ids, want)
+	}
+
+	if err := r.DeleteProduct(ctx, ksuid.New().String()); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("DeleteProduct missing error = %v, want %v", err, ErrProductNotFound)
+	}
+}
+
+func newReservation(items ...StockItem) Reservation {
+	return Reservation{
+		ID:        ksuid.New().String(),
+		Items:     items,
+		Status:    ReservationReserved,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func assertStock(t *testing.T, r Repository, id string, want uint32) {
+	t.Helper()
+	p, err := r.GetProductByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetProductByID: %v", err)
+	}
+	if p.Stock != want {
+		t.Errorf("stock of %s = %d, want %d", id, p.Stock, want)
+	}
+}
+
+func testReserveStock(t *testing.T, r Repository) {
+	ctx := context.Background()
+	a := newProduct("A", "first", 100)
+	a.Stock = 5
+	b := newProduct("B", "second", 200)
+	b.Stock = 1
+	putProducts(t, r, a, b)
+
+	if err := r.ReserveStock(ctx, newReservation(StockItem{a.ID, 2}, StockItem{b.ID, 1})); err != nil {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+	assertStock(t, r, a.ID, 3)
+	assertStock(t, r, b.ID, 0)
+
+	var outOfStock *OutOfStockError
+	err := r.ReserveStock(ctx, newReservation(StockItem{a.ID, 2}, StockItem{b.ID, 1}))
+	if !errors.As(err, &outOfStock) || outOfStock.ProductID != b.ID {
+		t.Fatalf("ReserveStock error = %v, want out of stock for %s", err, b.ID)
+	}
+	assertStock(t, r, a.ID, 3)
+
+	if err := r.ReserveStock(ctx, newReservation(StockItem{ksuid.New().String(), 1})); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("ReserveStock of missing product error = %v, want %v", err, ErrProductNotFound)
+	}
+}
+
+func testCloseReservation(t *testing.T, r Repository) {
+	ctx := context.Background()
+	p := newProduct("A", "first", 100)
+	p.Stock = 5
+	putProducts(t, r, p)
+
+	released := newReservation(StockItem{p.ID, 2})
+	committed := newReservation(StockItem{p.ID, 3})
+	for _, res := range []Reservation{released, committed} {
+		if err := r.ReserveStock(ctx, res); err != nil {
+			t.Fatalf("ReserveStock: %v", err)
+		}
+	}
+	assertStock(t, r, p.ID, 0)
+
+	if err := r.CloseReservation(ctx, released.ID, ReservationReleased); err != nil {
+		t.Fatalf("CloseReservation(released): %v", err)
+	}
+	if err := r.CloseReservation(ctx, committed.ID, ReservationCommitted); err != nil {
+		t.Fatalf("CloseReservation(committed): %v", err)
+	}
+	// Повторы не меняют остатки
+	if err := r.CloseReservation(ctx, released.ID, ReservationReleased); err != nil {
+		t.Errorf("repeated release: %v", err)
+	}
+	assertStock(t, r, p.ID, 2)
+
+	if err := r.CloseReservation(ctx, committed.ID, ReservationReleased); !errors.Is(err, ErrReservationClosed) {
+		t.Errorf("release of committed reservation error = %v, want %v", err, ErrReservationClosed)
+	}
+	if err := r.CloseReservation(ctx, ksuid.New().String(), ReservationCommitted); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("CloseReservation of missing reservation error = %v, want %v", err, ErrReservationNotFound)
+	}
+	assertStock(t, r, p.ID, 2)
+}
+
+func TestSearchRanksBestFieldFirst(t *testing.T) {
+	r := NewInMemoryRepository()
+	exact := newProduct("Laptop stand", "Aluminium", 2999)
+	fuzzy := newProduct("Lapdog bed", "Soft", 3999)
+	putProducts(t, r, fuzzy, exact)
+
+	got, err := r.SearchProducts(context.Background(), "laptop", 0, 10)
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != exact.ID {
+		t.Errorf("SearchProducts order = %v, want exact match %s first", productIDs(got), exact.ID)
+	}
+}
+
+func TestProductDecodesLegacyPrice(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want money.Money
+	}{
+		{doc: `{"id":"1","name":"A","price":19.99}`, want: money.New(1999, "USD")},
+		{doc: `{"id":"1","name":"A","price":{"amount":1999,"currency":"EUR"}}`, want: money.New(1999, "EUR")},
+		{doc: `{"id":"1","name":"A"}`, want: money.Money{}},
+	}
+	for _, tt := range tests {
+		var p Product
+		if err := json.Unmarshal([]byte(tt.doc), &p); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.doc, err)
+		}
+		if p.ID != "1" || p.Name != "A" || p.Price != tt.want {
+			t.Errorf("Unmarshal(%s) = %+v, want price %+v", tt.doc, p, tt.want)
+		}
+	}
+}
+
+func TestFuzziness(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		match bool
+	}{
+		{"tv", "tv", true},
+		{"tv", "tx", false},
+		{"desk", "disk", true},
+		{"desk", "dusky", false},
+		{"keyboard", "keybaord", true},
+		{"keyboard", "kezbaord", false},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b) <= fuzziness(tt.a); got != tt.match {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.match)
+		}
+	}
+}
+
+func testOutbox(t *testing.T, r Repository) {
+	p := newProduct("Keyboard", "Mechanical keyboard", 4999)
+	storetest.CheckOutbox(t, r, p.ID, &eventspb.ProductCreated{ProductId: p.ID}, func(events ...platform.Event) error {
+		return r.PutProduct(context.Background(), p, events...)
+	})
+}
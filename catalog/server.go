@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"go-microservice/catalog/pb"
+	"go-microservice/money"
+	"go-microservice/platform"
+	"log/slog"
 	"net"
+	"strings"
 
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 type grpcServer struct {
@@ -15,15 +20,22 @@ type grpcServer struct {
 	pb.UnimplementedCatalogServiceServer
 }
 
-func ListenGRPC(s Service, port int) error {
+// ListenGRPC обслуживает s на port, пока не отменён ctx, и дожидается текущих вызовов.
+// С сертификатами в tlsConfig сервер принимает только TLS (mTLS, если задан CA),
+// без них - plaintext. checks отдаются по grpc.health.v1, вызовы пишутся в logger.
+func ListenGRPC(ctx context.Context, s Service, port int, tlsConfig platform.TLSConfig, logger *slog.Logger, checks ...platform.HealthCheck) error {
+	serv, err := platform.NewServer(tlsConfig, logger)
+	if err != nil {
+		return err
+	}
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return err
 	}
-	serv := grpc.NewServer()
 	pb.RegisterCatalogServiceServer(serv, &grpcServer{service: s})
+	platform.RegisterHealth(serv, pb.CatalogService_ServiceDesc.ServiceName, checks...)
 	reflection.Register(serv)
-	return serv.Serve(lis)
+	return platform.Serve(ctx, serv, lis)
 }
 
 func toProtoProduct(p *Product) *pb.Product {
@@ -31,7 +43,9 @@ func toProtoProduct(p *Product) *pb.Product {
 		Id:          p.ID,
 		Name:        p.Name,
 		Description: p.Description,
-		Price:       p.Price,
+		Price:       money.ToProto(p.Price),
+		Deleted:     p.Deleted,
+		Stock:       p.Stock,
 	}
 }
 
@@ -48,7 +62,12 @@ func (s *grpcServer) GetProduct(ctx context.Context, r *pb.GetProductRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	return &pb.ProductResponse{Product: toProtoProduct(product)}, nil
+
+	products, err := s.convertPrices(ctx, []Product{*product}, r.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProductResponse{Product: toProtoProduct(&products[0])}, nil
 }
 
 func (s *grpcServer) GetProducts(ctx context.Context, r *pb.GetProductsRequest) (*pb.ProductsResponse, error) {
@@ -66,13 +85,128 @@ func (s *grpcServer) GetProducts(ctx context.Context, r *pb.GetProductsRequest)
 		return nil, err
 	}
 
+	products, err = s.convertPrices(ctx, products, r.Currency)
+	if err != nil {
+		return nil, err
+	}
+
 	return makeProductsResponse(products), nil
 }
 
+func (s *grpcServer) GetProductsPage(ctx context.Context, r *pb.GetProductsPageRequest) (*pb.GetProductsPageResponse, error) {
+	page, err := s.service.GetProductsPage(ctx, r.Query, r.After, r.First)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]Product, len(page.Edges))
+	for i, e := range page.Edges {
+		products[i] = e.Product
+	}
+	products, err = s.convertPrices(ctx, products, r.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*pb.ProductEdge, len(page.Edges))
+	for i, e := range page.Edges {
+		edges[i] = &pb.ProductEdge{Product: toProtoProduct(&products[i]), Cursor: e.Cursor}
+	}
+	return &pb.GetProductsPageResponse{Edges: edges, HasNextPage: page.HasNextPage}, nil
+}
+
 func (s *grpcServer) PostProduct(ctx context.Context, r *pb.PostProductRequest) (*pb.ProductResponse, error) {
-	product, err := s.service.PostProduct(ctx, r.Name, r.Description, r.Price)
+	product, err := s.service.PostProduct(ctx, r.Name, r.Description, money.FromProto(r.Price), r.Stock)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProductResponse{Product: toProtoProduct(product)}, nil
+}
+
+func (s *grpcServer) UpdateProduct(ctx context.Context, r *pb.UpdateProductRequest) (*pb.ProductResponse, error) {
+	if r.Product == nil || r.Product.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "product.id is required")
+	}
+
+	update := Product{
+		ID:          r.Product.Id,
+		Name:        r.Product.Name,
+		Description: r.Product.Description,
+		Price:       money.FromProto(r.Product.Price),
+		Stock:       r.Product.Stock,
+	}
+
+	// Без маски обновляются все заполненные поля; остаток можно обнулить только явной маской
+	paths := r.UpdateMask.GetPaths()
+	if len(paths) == 0 {
+		if r.Product.Name != "" {
+			paths = append(paths, FieldName)
+		}
+		if r.Product.Description != "" {
+			paths = append(paths, FieldDescription)
+		}
+		if r.Product.Price != nil {
+			paths = append(paths, FieldPrice)
+		}
+		if r.Product.Stock != 0 {
+			paths = append(paths, FieldStock)
+		}
+	}
+
+	product, err := s.service.UpdateProduct(ctx, update, paths)
 	if err != nil {
 		return nil, err
 	}
 	return &pb.ProductResponse{Product: toProtoProduct(product)}, nil
 }
+
+func (s *grpcServer) DeleteProduct(ctx context.Context, r *pb.DeleteProductRequest) (*pb.DeleteProductResponse, error) {
+	if r.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := s.service.DeleteProduct(ctx, r.Id); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteProductResponse{}, nil
+}
+
+func (s *grpcServer) ReserveStock(ctx context.Context, r *pb.ReserveStockRequest) (*pb.ReserveStockResponse, error) {
+	items := make([]StockItem, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = StockItem{ProductID: item.ProductId, Quantity: item.Quantity}
+	}
+
+	reservation, err := s.service.ReserveStock(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReserveStockResponse{ReservationId: reservation.ID}, nil
+}
+
+func (s *grpcServer) ReleaseStock(ctx context.Context, r *pb.ReleaseStockRequest) (*pb.ReleaseStockResponse, error) {
+	if err := s.service.ReleaseStock(ctx, r.ReservationId); err != nil {
+		return nil, err
+	}
+	return &pb.ReleaseStockResponse{}, nil
+}
+
+func (s *grpcServer) CommitStock(ctx context.Context, r *pb.CommitStockRequest) (*pb.CommitStockResponse, error) {
+	if err := s.service.CommitStock(ctx, r.ReservationId); err != nil {
+		return nil, err
+	}
+	return &pb.CommitStockResponse{}, nil
+}
+
+// convertPrices пересчитывает цены в запрошенную валюту, если она указана
+func (s *grpcServer) convertPrices(ctx context.Context, products []Product, currency string) ([]Product, error) {
+	if currency == "" {
+		return products, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if err := money.ValidateCurrency(currency); err != nil {
+		return nil, fmt.Errorf("%w: %q", err, currency)
+	}
+	return s.service.ConvertPrices(ctx, products, currency)
+}
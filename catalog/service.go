@@ -2,37 +2,154 @@ package catalog
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	eventspb "go-microservice/events/pb"
+	"go-microservice/money"
+	"go-microservice/platform"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
 
 	"github.com/segmentio/ksuid"
 )
 
+// ReasonOutOfStock - Reason ошибки ReserveStock при нехватке товара, в Metadata передаётся productId
+const ReasonOutOfStock = "OUT_OF_STOCK"
+
+var (
+	ErrInvalidPrice        = platform.NewError(platform.InvalidArgument, "INVALID_PRICE", "invalid product price")
+	ErrInvalidUpdateMask   = platform.NewError(platform.InvalidArgument, "INVALID_UPDATE_MASK", "invalid update mask")
+	ErrInvalidQuantity     = platform.NewError(platform.InvalidArgument, "INVALID_QUANTITY", "invalid quantity")
+	ErrOutOfStock          = platform.NewError(platform.FailedPrecondition, ReasonOutOfStock, "out of stock")
+	ErrReservationNotFound = platform.NewError(platform.NotFound, "RESERVATION_NOT_FOUND", "reservation not found")
+	ErrReservationClosed   = platform.NewError(platform.FailedPrecondition, "RESERVATION_CLOSED", "reservation is already closed")
+	ErrInvalidCursor       = platform.NewError(platform.InvalidArgument, "INVALID_CURSOR", "invalid cursor")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Поля товара, которые можно передать в маске UpdateProduct
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldPrice       = "price"
+	FieldStock       = "stock"
+)
+
 type Service interface {
-	PostProduct(ctx context.Context, name string, description string, price float64) (*Product, error)
+	PostProduct(ctx context.Context, name string, description string, price money.Money, stock uint32) (*Product, error)
 	GetProduct(ctx context.Context, id string) (*Product, error)
 	GetProducts(ctx context.Context, skip, take uint64) ([]Product, error)
 	GetProductsByIDs(ctx context.Context, ids []string) ([]Product, error)
 	SearchProducts(ctx context.Context, query string, skip, take uint64) ([]Product, error)
+	GetProductsPage(ctx context.Context, query, after string, first uint64) (*ProductPage, error)
+	ConvertPrices(ctx context.Context, products []Product, currency string) ([]Product, error)
+	UpdateProduct(ctx context.Context, update Product, paths []string) (*Product, error)
+	DeleteProduct(ctx context.Context, id string) error
+	ReserveStock(ctx context.Context, items []StockItem) (*Reservation, error)
+	ReleaseStock(ctx context.Context, reservationID string) error
+	CommitStock(ctx context.Context, reservationID string) error
 }
 
 type Product struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	// Deleted - товар снят с продажи. Документ не удаляется, чтобы старые заказы могли его показать.
+	Deleted bool `json:"deleted,omitempty"`
+	// Stock - количество, доступное для резервирования. Зарезервированные единицы в него не входят.
+	Stock uint32 `json:"stock"`
+}
+
+type ProductEdge struct {
+	Product Product
+	// Cursor - непрозрачная позиция товара в выдаче, передаётся в after для следующей страницы
+	Cursor string
+}
+
+// ProductPage - страница товаров: по ID для списка, по релевантности для поиска
+type ProductPage struct {
+	Edges       []ProductEdge
+	HasNextPage bool
+}
+
+// productCursor - содержимое курсора. Score есть только у курсоров поиска.
+type productCursor struct {
+	ID    string   `json:"id"`
+	Score *float64 `json:"score,omitempty"`
+}
+
+// OutOfStockError сообщает, какого товара не хватило для резерва
+type OutOfStockError struct {
+	ProductID string
+}
+
+func (e *OutOfStockError) Error() string {
+	return fmt.Sprintf("%v: product %s", ErrOutOfStock, e.ProductID)
+}
+
+// Unwrap добавляет к ErrOutOfStock товар, так что он уходит клиенту в ErrorInfo.Metadata
+func (e *OutOfStockError) Unwrap() error {
+	return ErrOutOfStock.WithMetadata("productId", e.ProductID)
+}
+
+type StockItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  uint32 `json:"quantity"`
+}
+
+type ReservationStatus string
+
+// Резерв создаётся в статусе reserved и закрывается один раз:
+// commit списывает товар окончательно, release возвращает его на склад.
+const (
+	ReservationReserved  ReservationStatus = "reserved"
+	ReservationCommitted ReservationStatus = "committed"
+	ReservationReleased  ReservationStatus = "released"
+)
+
+type Reservation struct {
+	ID        string            `json:"id"`
+	Items     []StockItem       `json:"items"`
+	Status    ReservationStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
 }
 type CatalogService struct {
-	repository Repository
+	repository   Repository
+	baseCurrency string
+	rates        money.ExchangeRateProvider
+	logger       *slog.Logger
 }
 
-func NewService(r Repository) Service {
+// NewService создаёт каталог, в котором все цены хранятся в baseCurrency.
+// rates используется для пересчёта цен в другие валюты при чтении.
+func NewService(r Repository, baseCurrency string, rates money.ExchangeRateProvider, logger *slog.Logger) Service {
 	return &CatalogService{
-		repository: r,
+		repository:   r,
+		baseCurrency: baseCurrency,
+		rates:        rates,
+		logger:       logger,
 	}
 }
 
 // GetProduct implements Service.
+// Удалённые товары считаются отсутствующими.
 func (c *CatalogService) GetProduct(ctx context.Context, id string) (*Product, error) {
-	return c.repository.GetProductByID(ctx, id)
+	p, err := c.repository.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Deleted {
+		return nil, ErrProductNotFound
+	}
+	return p, nil
 }
 
 // GetProducts implements Service.
@@ -44,26 +161,46 @@ func (c *CatalogService) GetProducts(ctx context.Context, skip uint64, take uint
 }
 
 // GetProductsByIDs implements Service.
+// В отличие от остальных методов чтения возвращает и удалённые товары: по ним восстанавливаются старые заказы.
 func (c *CatalogService) GetProductsByIDs(ctx context.Context, ids []string) ([]Product, error) {
 	return c.repository.ListProductsWithIDs(ctx, ids)
 
 }
 
 // PostProduct implements Service.
-func (c *CatalogService) PostProduct(ctx context.Context, name string, description string, price float64) (*Product, error) {
+func (c *CatalogService) PostProduct(ctx context.Context, name string, description string, price money.Money, stock uint32) (*Product, error) {
+	if err := c.validatePrice(price); err != nil {
+		return nil, err
+	}
+
 	p := Product{
 		ID:          ksuid.New().String(),
 		Name:        name,
 		Description: description,
 		Price:       price,
+		Stock:       stock,
 	}
-	err := c.repository.PutProduct(ctx, p)
+	event, err := platform.NewEvent(p.ID, productCreatedEvent(p))
 	if err != nil {
 		return nil, err
 	}
+	if err := c.repository.PutProduct(ctx, p, event); err != nil {
+		return nil, err
+	}
+	c.logger.InfoContext(ctx, "Product created", "product_id", p.ID)
 	return &p, nil
 }
 
+func productCreatedEvent(p Product) *eventspb.ProductCreated {
+	return &eventspb.ProductCreated{
+		ProductId:   p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       money.ToProto(p.Price),
+		Stock:       p.Stock,
+	}
+}
+
 // SearchProducts implements Service.
 func (c *CatalogService) SearchProducts(ctx context.Context, query string, skip uint64, take uint64) ([]Product, error) {
 	if take > 100 || (skip == 0 && take == 0) {
@@ -72,3 +209,232 @@ func (c *CatalogService) SearchProducts(ctx context.Context, query string, skip
 	return c.repository.SearchProducts(ctx, query, skip, take)
 
 }
+
+// GetProductsPage implements Service.
+// Пустой query - список товаров по ID, иначе поиск. Курсор поиска нельзя передать в список и наоборот.
+func (c *CatalogService) GetProductsPage(ctx context.Context, query, after string, first uint64) (*ProductPage, error) {
+	cursor, err := decodeCursor(after)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil && (cursor.Score != nil) != (query != "") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCursor, after)
+	}
+	if first == 0 {
+		first = defaultPageSize
+	}
+	first = min(first, maxPageSize)
+
+	// Лишний товар показывает, есть ли следующая страница
+	var edges []ProductEdge
+	if query == "" {
+		var afterID string
+		if cursor != nil {
+			afterID = cursor.ID
+		}
+		products, err := c.repository.ListProductsAfter(ctx, afterID, first+1)
+		if err != nil {
+			return nil, err
+		}
+		edges = make([]ProductEdge, len(products))
+		for i, p := range products {
+			edges[i] = ProductEdge{Product: p, Cursor: encodeCursor(productCursor{ID: p.ID})}
+		}
+	} else {
+		var searchAfter *SearchAfter
+		if cursor != nil {
+			searchAfter = &SearchAfter{Score: *cursor.Score, ID: cursor.ID}
+		}
+		hits, err := c.repository.SearchProductsAfter(ctx, query, searchAfter, first+1)
+		if err != nil {
+			return nil, err
+		}
+		edges = make([]ProductEdge, len(hits))
+		for i, h := range hits {
+			edges[i] = ProductEdge{Product: h.Product, Cursor: encodeCursor(productCursor{ID: h.Product.ID, Score: &h.Score})}
+		}
+	}
+
+	page := &ProductPage{Edges: edges, HasNextPage: uint64(len(edges)) > first}
+	if page.HasNextPage {
+		page.Edges = edges[:first]
+	}
+	return page, nil
+}
+
+func encodeCursor(c productCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (*productCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	var c productCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	return &c, nil
+}
+
+// ConvertPrices implements Service.
+func (c *CatalogService) ConvertPrices(ctx context.Context, products []Product, currency string) ([]Product, error) {
+	converted := make([]Product, len(products))
+	rates := make(map[string]money.Rate)
+	for i, p := range products {
+		rate, ok := rates[p.Price.Currency]
+		if !ok {
+			var err error
+			rate, err = c.rates.Rate(ctx, p.Price.Currency, currency)
+			if err != nil {
+				return nil, err
+			}
+			rates[p.Price.Currency] = rate
+		}
+
+		price, err := money.Convert(p.Price, rate)
+		if err != nil {
+			return nil, err
+		}
+		p.Price = price
+		converted[i] = p
+	}
+	return converted, nil
+}
+
+// UpdateProduct implements Service.
+// Меняются только поля из paths, остальные поля update игнорируются.
+// В хранилище пишутся только они же, поэтому правка не затирает остаток, списанный параллельным резервом.
+func (c *CatalogService) UpdateProduct(ctx context.Context, update Product, paths []string) (*Product, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: no fields to update", ErrInvalidUpdateMask)
+	}
+	changed := &eventspb.ProductUpdated{ProductId: update.ID, UpdatedFields: paths}
+	for _, path := range paths {
+		switch path {
+		case FieldName:
+			changed.Name = update.Name
+		case FieldDescription:
+			changed.Description = update.Description
+		case FieldPrice:
+			if err := c.validatePrice(update.Price); err != nil {
+				return nil, err
+			}
+			changed.Price = money.ToProto(update.Price)
+		case FieldStock:
+			changed.Stock = update.Stock
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidUpdateMask, path)
+		}
+	}
+
+	if _, err := c.GetProduct(ctx, update.ID); err != nil {
+		return nil, err
+	}
+	event, err := platform.NewEvent(update.ID, changed)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.repository.UpdateProduct(ctx, update, paths, event); err != nil {
+		return nil, err
+	}
+	c.logger.InfoContext(ctx, "Product updated", "product_id", update.ID, "fields", paths)
+	return c.repository.GetProductByID(ctx, update.ID)
+}
+
+// applyUpdate переносит в p поля update из paths
+func applyUpdate(p *Product, update Product, paths []string) error {
+	for _, path := range paths {
+		switch path {
+		case FieldName:
+			p.Name = update.Name
+		case FieldDescription:
+			p.Description = update.Description
+		case FieldPrice:
+			p.Price = update.Price
+		case FieldStock:
+			p.Stock = update.Stock
+		default:
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidUpdateMask, path)
+		}
+	}
+	return nil
+}
+
+// DeleteProduct implements Service.
+func (c *CatalogService) DeleteProduct(ctx context.Context, id string) error {
+	if _, err := c.GetProduct(ctx, id); err != nil {
+		return err
+	}
+	if err := c.repository.DeleteProduct(ctx, id); err != nil {
+		return err
+	}
+	c.logger.InfoContext(ctx, "Product deleted", "product_id", id)
+	return nil
+}
+
+func (c *CatalogService) validatePrice(price money.Money) error {
+	if err := price.Validate(); err != nil || price.Amount < 0 {
+		return ErrInvalidPrice
+	}
+	if price.Currency != c.baseCurrency {
+		return fmt.Errorf("%w: price must be in %s", ErrInvalidPrice, c.baseCurrency)
+	}
+	return nil
+}
+
+// ReserveStock резервирует товары целиком: либо все позиции, либо ни одной.
+// Повторяющиеся товары объединяются в одну позицию.
+// Зафиксированный резерв не возвращается на склад: отмена или возврат заказа остаток не восстанавливают,
+// его нужно поправить через UpdateProduct.
+func (c *CatalogService) ReserveStock(ctx context.Context, items []StockItem) (*Reservation, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no items to reserve", ErrInvalidQuantity)
+	}
+
+	// Сумма в uint64, чтобы большой резерв не переполнил uint32 и не прошёл как маленький
+	quantities := make(map[string]uint64, len(items))
+	for _, item := range items {
+		if item.ProductID == "" || item.Quantity == 0 {
+			return nil, fmt.Errorf("%w: product %q, quantity %d", ErrInvalidQuantity, item.ProductID, item.Quantity)
+		}
+		quantities[item.ProductID] += uint64(item.Quantity)
+		if quantities[item.ProductID] > math.MaxUint32 {
+			return nil, fmt.Errorf("%w: product %q, total quantity exceeds %d", ErrInvalidQuantity, item.ProductID, uint32(math.MaxUint32))
+		}
+	}
+
+	// Порядок по ID, чтобы параллельные резервы одних и тех же товаров не мешали друг другу в разном порядке
+	merged := make([]StockItem, 0, len(quantities))
+	for id, q := range quantities {
+		merged = append(merged, StockItem{ProductID: id, Quantity: uint32(q)})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ProductID < merged[j].ProductID })
+
+	r := Reservation{
+		ID:        ksuid.New().String(),
+		Items:     merged,
+		Status:    ReservationReserved,
+		CreatedAt: time.Now(),
+	}
+	if err := c.repository.ReserveStock(ctx, r); err != nil {
+		return nil, err
+	}
+	c.logger.DebugContext(ctx, "Stock reserved", "reservation_id", r.ID, "items", len(r.Items))
+	return &r, nil
+}
+
+// ReleaseStock implements Service.
+func (c *CatalogService) ReleaseStock(ctx context.Context, reservationID string) error {
+	return c.repository.CloseReservation(ctx, reservationID, ReservationReleased)
+}
+
+// CommitStock implements Service.
+func (c *CatalogService) CommitStock(ctx context.Context, reservationID string) error {
+	return c.repository.CloseReservation(ctx, reservationID, ReservationCommitted)
+}
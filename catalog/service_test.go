@@ -0,0 +1,260 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	eventspb "go-microservice/events/pb"
+	"go-microservice/money"
+	"go-microservice/platform"
+	"math"
+	"testing"
+
+	"github.com/segmentio/ksuid"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestService(t *testing.T) Service {
+	t.Helper()
+	rates, err := money.NewStaticRateProvider("USD", nil)
+	if err != nil {
+		t.Fatalf("NewStaticRateProvider: %v", err)
+	}
+	return NewService(NewInMemoryRepository(), "USD", rates, platform.DiscardLogger())
+}
+
+func TestUpdateProductMask(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  Product
+		paths   []string
+		want    Product
+		wantErr error
+	}{
+		{
+			name:   "name only",
+			update: Product{Name: "Gaming mouse", Description: "ignored", Price: money.New(1, "USD")},
+			paths:  []string{FieldName},
+			want:   Product{Name: "Gaming mouse", Description: "Wireless mouse", Price: money.New(1999, "USD"), Stock: 10},
+		},
+		{
+			name:   "price and description",
+			update: Product{Description: "", Price: money.New(2499, "USD")},
+			paths:  []string{FieldDescription, FieldPrice},
+			want:   Product{Name: "Mouse", Description: "", Price: money.New(2499, "USD"), Stock: 10},
+		},
+		{
+			name:   "clear stock",
+			update: Product{Name: "ignored"},
+			paths:  []string{FieldStock},
+			want:   Product{Name: "Mouse", Description: "Wireless mouse", Price: money.New(1999, "USD")},
+		},
+		{name: "empty mask", wantErr: ErrInvalidUpdateMask},
+		{name: "unknown field", paths: []string{"id"}, wantErr: ErrInvalidUpdateMask},
+		{name: "foreign currency", update: Product{Price: money.New(100, "EUR")}, paths: []string{FieldPrice}, wantErr: ErrInvalidPrice},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestService(t)
+			p, err := s.PostProduct(ctx, "Mouse", "Wireless mouse", money.New(1999, "USD"), 10)
+			if err != nil {
+				t.Fatalf("PostProduct: %v", err)
+			}
+
+			tt.update.ID = p.ID
+			got, err := s.UpdateProduct(ctx, tt.update, tt.paths)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProduct error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			tt.want.ID = p.ID
+			if *got != tt.want {
+				t.Errorf("UpdateProduct = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateProductRecordsEvent(t *testing.T) {
+	ctx := context.Background()
+	rates, err := money.NewStaticRateProvider("USD", nil)
+	if err != nil {
+		t.Fatalf("NewStaticRateProvider: %v", err)
+	}
+	r := NewInMemoryRepository()
+	s := NewService(r, "USD", rates, platform.DiscardLogger())
+	p, err := s.PostProduct(ctx, "Mouse", "Wireless mouse", money.New(1999, "USD"), 10)
+	if err != nil {
+		t.Fatalf("PostProduct: %v", err)
+	}
+
+	update := Product{ID: p.ID, Name: "ignored", Price: money.New(2499, "USD"), Stock: 3}
+	if _, err := s.UpdateProduct(ctx, update, []string{FieldPrice}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+
+	events, err := r.PendingEvents(ctx, 10)
+	if err != nil {
+		t.Fatalf("PendingEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	var got eventspb.ProductUpdated
+	if err := proto.Unmarshal(events[1].Payload, &got); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if events[1].Type != "events.v1.ProductUpdated" || events[1].AggregateID != p.ID || got.ProductId != p.ID ||
+		len(got.UpdatedFields) != 1 || got.UpdatedFields[0] != FieldPrice || got.Price.GetAmount() != 2499 || got.Name != "" || got.Stock != 0 {
+		t.Errorf("event = %+v with payload %v, want ProductUpdated of price for %s", events[1], &got, p.ID)
+	}
+}
+
+func TestDeletedProductIsHidden(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	p, err := s.PostProduct(ctx, "Mouse", "Wireless mouse", money.New(1999, "USD"), 10)
+	if err != nil {
+		t.Fatalf("PostProduct: %v", err)
+	}
+
+	if err := s.DeleteProduct(ctx, p.ID); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+	if _, err := s.GetProduct(ctx, p.ID); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("GetProduct error = %v, want %v", err, ErrProductNotFound)
+	}
+	if _, err := s.UpdateProduct(ctx, Product{ID: p.ID, Name: "Revived"}, []string{FieldName}); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("UpdateProduct error = %v, want %v", err, ErrProductNotFound)
+	}
+	if err := s.DeleteProduct(ctx, p.ID); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("second DeleteProduct error = %v, want %v", err, ErrProductNotFound)
+	}
+
+	byIDs, err := s.GetProductsByIDs(ctx, []string{p.ID})
+	if err != nil {
+		t.Fatalf("GetProductsByIDs: %v", err)
+	}
+	if len(byIDs) != 1 || !byIDs[0].Deleted {
+		t.Errorf("GetProductsByIDs = %+v, want the deleted product", byIDs)
+	}
+}
+
+func TestReserveStock(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	mouse, err := s.PostProduct(ctx, "Mouse", "Wireless mouse", money.New(1999, "USD"), 3)
+	if err != nil {
+		t.Fatalf("PostProduct: %v", err)
+	}
+	pad, err := s.PostProduct(ctx, "Mouse pad", "Large", money.New(999, "USD"), 1)
+	if err != nil {
+		t.Fatalf("PostProduct: %v", err)
+	}
+
+	assertStock := func(p *Product, want uint32) {
+		t.Helper()
+		got, err := s.GetProduct(ctx, p.ID)
+		if err != nil {
+			t.Fatalf("GetProduct: %v", err)
+		}
+		if got.Stock != want {
+			t.Errorf("%s stock = %d, want %d", p.Name, got.Stock, want)
+		}
+	}
+
+	// Повторяющиеся позиции складываются: 1 + 1 мышь
+	r, err := s.ReserveStock(ctx, []StockItem{{mouse.ID, 1}, {pad.ID, 1}, {mouse.ID, 1}})
+	if err != nil {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+	if len(r.Items) != 2 {
+		t.Errorf("reservation has %d items, want 2", len(r.Items))
+	}
+	assertStock(mouse, 1)
+	assertStock(pad, 0)
+
+	// Нехватка одной позиции не списывает остальные
+	var outOfStock *OutOfStockError
+	if _, err := s.ReserveStock(ctx, []StockItem{{mouse.ID, 1}, {pad.ID, 1}}); !errors.As(err, &outOfStock) || outOfStock.ProductID != pad.ID {
+		t.Fatalf("ReserveStock error = %v, want out of stock for %s", err, pad.ID)
+	}
+	assertStock(mouse, 1)
+
+	if err := s.ReleaseStock(ctx, r.ID); err != nil {
+		t.Fatalf("ReleaseStock: %v", err)
+	}
+	if err := s.ReleaseStock(ctx, r.ID); err != nil {
+		t.Errorf("repeated ReleaseStock: %v", err)
+	}
+	assertStock(mouse, 3)
+	assertStock(pad, 1)
+	if err := s.CommitStock(ctx, r.ID); !errors.Is(err, ErrReservationClosed) {
+		t.Errorf("CommitStock of released reservation error = %v, want %v", err, ErrReservationClosed)
+	}
+
+	committed, err := s.ReserveStock(ctx, []StockItem{{mouse.ID, 3}})
+	if err != nil {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+	if err := s.CommitStock(ctx, committed.ID); err != nil {
+		t.Fatalf("CommitStock: %v", err)
+	}
+	if err := s.ReleaseStock(ctx, committed.ID); !errors.Is(err, ErrReservationClosed) {
+		t.Errorf("ReleaseStock of committed reservation error = %v, want %v", err, ErrReservationClosed)
+	}
+	assertStock(mouse, 0)
+
+	// Сумма повторяющихся позиций переполнила бы uint32 и превратилась в 1
+	for _, items := range [][]StockItem{nil, {{mouse.ID, 0}}, {{"", 1}}, {{mouse.ID, math.MaxUint32}, {mouse.ID, 2}}} {
+		if _, err := s.ReserveStock(ctx, items); !errors.Is(err, ErrInvalidQuantity) {
+			t.Errorf("ReserveStock(%v) error = %v, want %v", items, err, ErrInvalidQuantity)
+		}
+	}
+	if err := s.ReleaseStock(ctx, ksuid.New().String()); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("ReleaseStock of missing reservation error = %v, want %v", err, ErrReservationNotFound)
+	}
+}
+
+func TestGetProductsPage(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+
+	for _, name := range []string{"Red mug", "Blue mug", "Green mug"} {
+		if _, err := s.PostProduct(ctx, name, "Ceramic", money.New(999, "USD"), 1); err != nil {
+			t.Fatalf("PostProduct: %v", err)
+		}
+	}
+
+	for _, query := range []string{"", "mug"} {
+		first, err := s.GetProductsPage(ctx, query, "", 2)
+		if err != nil {
+			t.Fatalf("GetProductsPage(%q): %v", query, err)
+		}
+		if len(first.Edges) != 2 || !first.HasNextPage {
+			t.Fatalf("GetProductsPage(%q) first page = %d products, hasNextPage %v, want 2, true", query, len(first.Edges), first.HasNextPage)
+		}
+		second, err := s.GetProductsPage(ctx, query, first.Edges[1].Cursor, 2)
+		if err != nil {
+			t.Fatalf("GetProductsPage(%q): %v", query, err)
+		}
+		if len(second.Edges) != 1 || second.HasNextPage {
+			t.Errorf("GetProductsPage(%q) second page = %d products, hasNextPage %v, want 1, false", query, len(second.Edges), second.HasNextPage)
+		}
+	}
+
+	// Курсор списка не подходит для поиска: в нём нет релевантности
+	list, err := s.GetProductsPage(ctx, "", "", 1)
+	if err != nil {
+		t.Fatalf("GetProductsPage: %v", err)
+	}
+	if _, err := s.GetProductsPage(ctx, "mug", list.Edges[0].Cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("search with list cursor error = %v, want %v", err, ErrInvalidCursor)
+	}
+	if _, err := s.GetProductsPage(ctx, "", "garbage", 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("GetProductsPage with malformed cursor error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
@@ -0,0 +1,540 @@
+// protoc   --go_out=.   --go_opt=paths=source_relative   events/pb/events.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: events/pb/events.proto
+
+// Версия схемы - в имени пакета. Совместимые изменения (новые поля) добавляются в v1,
+// несовместимые публикуются новыми сообщениями в events.v2, пока подписчики не перейдут на них.
+
+package pb
+
+import (
+	pb "go-microservice/money/pb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AccountCreated - создан аккаунт, через createAccount или регистрацию
+type AccountCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountCreated) Reset() {
+	*x = AccountCreated{}
+	mi := &file_events_pb_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountCreated) ProtoMessage() {}
+
+func (x *AccountCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_pb_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountCreated.ProtoReflect.Descriptor instead.
+func (*AccountCreated) Descriptor() ([]byte, []int) {
+	return file_events_pb_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AccountCreated) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AccountCreated) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AccountCreated) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+// ProductCreated - в каталог добавлен товар, price - в базовой валюте каталога
+type ProductCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price         *pb.Money              `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         uint32                 `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductCreated) Reset() {
+	*x = ProductCreated{}
+	mi := &file_events_pb_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductCreated) ProtoMessage() {}
+
+func (x *ProductCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_pb_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductCreated.ProtoReflect.Descriptor instead.
+func (*ProductCreated) Descriptor() ([]byte, []int) {
+	return file_events_pb_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProductCreated) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductCreated) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductCreated) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProductCreated) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductCreated) GetStock() uint32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+// ProductUpdated - изменены поля товара из updatedFields (name, description, price, stock).
+// Заполнены только они, price - в базовой валюте каталога. Остаток меняется и резервами заказов,
+// о них событие не публикуется.
+type ProductUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	UpdatedFields []string               `protobuf:"bytes,2,rep,name=updatedFields,proto3" json:"updatedFields,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Price         *pb.Money              `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         uint32                 `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductUpdated) Reset() {
+	*x = ProductUpdated{}
+	mi := &file_events_pb_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductUpdated) ProtoMessage() {}
+
+func (x *ProductUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_pb_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductUpdated.ProtoReflect.Descriptor instead.
+func (*ProductUpdated) Descriptor() ([]byte, []int) {
+	return file_events_pb_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProductUpdated) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductUpdated) GetUpdatedFields() []string {
+	if x != nil {
+		return x.UpdatedFields
+	}
+	return nil
+}
+
+func (x *ProductUpdated) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductUpdated) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProductUpdated) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductUpdated) GetStock() uint32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+// OrderCreated - оформлен заказ, цены - в валюте заказа
+type OrderCreated struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	OrderId       string                  `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	AccountId     string                  `protobuf:"bytes,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Products      []*OrderCreated_Product `protobuf:"bytes,3,rep,name=products,proto3" json:"products,omitempty"`
+	TotalPrice    *pb.Money               `protobuf:"bytes,4,opt,name=totalPrice,proto3" json:"totalPrice,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCreated) Reset() {
+	*x = OrderCreated{}
+	mi := &file_events_pb_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCreated) ProtoMessage() {}
+
+func (x *OrderCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_pb_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCreated.ProtoReflect.Descriptor instead.
+func (*OrderCreated) Descriptor() ([]byte, []int) {
+	return file_events_pb_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderCreated) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderCreated) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *OrderCreated) GetProducts() []*OrderCreated_Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *OrderCreated) GetTotalPrice() *pb.Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+// OrderStatusChanged - заказ переведён из статуса from в to (pending, paid, shipped, delivered, cancelled, refunded)
+type OrderStatusChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusChanged) Reset() {
+	*x = OrderStatusChanged{}
+	mi := &file_events_pb_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChanged) ProtoMessage() {}
+
+func (x *OrderStatusChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_events_pb_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChanged.ProtoReflect.Descriptor instead.
+func (*OrderStatusChanged) Descriptor() ([]byte, []int) {
+	return file_events_pb_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderStatusChanged) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type OrderCreated_Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         *pb.Money              `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCreated_Product) Reset() {
+	*x = OrderCreated_Product{}
+	mi := &file_events_pb_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCreated_Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCreated_Product) ProtoMessage() {}
+
+func (x *OrderCreated_Product) ProtoReflect() protoreflect.Message {
+	mi := &file_events_pb_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCreated_Product.ProtoReflect.Descriptor instead.
+func (*OrderCreated_Product) Descriptor() ([]byte, []int) {
+	return file_events_pb_events_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *OrderCreated_Product) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderCreated_Product) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderCreated_Product) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+var File_events_pb_events_proto protoreflect.FileDescriptor
+
+const file_events_pb_events_proto_rawDesc = "" +
+	"\n" +
+	"\x16events/pb/events.proto\x12\tevents.v1\x1a\x14money/pb/money.proto\"V\n" +
+	"\x0eAccountCreated\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"\x9e\x01\n" +
+	"\x0eProductCreated\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\"\n" +
+	"\x05price\x18\x04 \x01(\v2\f.money.MoneyR\x05price\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\rR\x05stock\"\xc4\x01\n" +
+	"\x0eProductUpdated\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12$\n" +
+	"\rupdatedFields\x18\x02 \x03(\tR\rupdatedFields\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\"\n" +
+	"\x05price\x18\x05 \x01(\v2\f.money.MoneyR\x05price\x12\x14\n" +
+	"\x05stock\x18\x06 \x01(\rR\x05stock\"\x9a\x02\n" +
+	"\fOrderCreated\x12\x18\n" +
+	"\aorderId\x18\x01 \x01(\tR\aorderId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\tR\taccountId\x12;\n" +
+	"\bproducts\x18\x03 \x03(\v2\x1f.events.v1.OrderCreated.ProductR\bproducts\x12,\n" +
+	"\n" +
+	"totalPrice\x18\x04 \x01(\v2\f.money.MoneyR\n" +
+	"totalPrice\x1ag\n" +
+	"\aProduct\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\rR\bquantity\x12\"\n" +
+	"\x05price\x18\x03 \x01(\v2\f.money.MoneyR\x05price\"R\n" +
+	"\x12OrderStatusChanged\x12\x18\n" +
+	"\aorderId\x18\x01 \x01(\tR\aorderId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02toB\x1bZ\x19go-microservice/events/pbb\x06proto3"
+
+var (
+	file_events_pb_events_proto_rawDescOnce sync.Once
+	file_events_pb_events_proto_rawDescData []byte
+)
+
+func file_events_pb_events_proto_rawDescGZIP() []byte {
+	file_events_pb_events_proto_rawDescOnce.Do(func() {
+		file_events_pb_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_pb_events_proto_rawDesc), len(file_events_pb_events_proto_rawDesc)))
+	})
+	return file_events_pb_events_proto_rawDescData
+}
+
+var file_events_pb_events_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_events_pb_events_proto_goTypes = []any{
+	(*AccountCreated)(nil),       // 0: events.v1.AccountCreated
+	(*ProductCreated)(nil),       // 1: events.v1.ProductCreated
+	(*ProductUpdated)(nil),       // 2: events.v1.ProductUpdated
+	(*OrderCreated)(nil),         // 3: events.v1.OrderCreated
+	(*OrderStatusChanged)(nil),   // 4: events.v1.OrderStatusChanged
+	(*OrderCreated_Product)(nil), // 5: events.v1.OrderCreated.Product
+	(*pb.Money)(nil),             // 6: money.Money
+}
+var file_events_pb_events_proto_depIdxs = []int32{
+	6, // 0: events.v1.ProductCreated.price:type_name -> money.Money
+	6, // 1: events.v1.ProductUpdated.price:type_name -> money.Money
+	5, // 2: events.v1.OrderCreated.products:type_name -> events.v1.OrderCreated.Product
+	6, // 3: events.v1.OrderCreated.totalPrice:type_name -> money.Money
+	6, // 4: events.v1.OrderCreated.Product.price:type_name -> money.Money
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_events_pb_events_proto_init() }
+func file_events_pb_events_proto_init() {
+	if File_events_pb_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_pb_events_proto_rawDesc), len(file_events_pb_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_events_pb_events_proto_goTypes,
+		DependencyIndexes: file_events_pb_events_proto_depIdxs,
+		MessageInfos:      file_events_pb_events_proto_msgTypes,
+	}.Build()
+	File_events_pb_events_proto = out.File
+	file_events_pb_events_proto_goTypes = nil
+	file_events_pb_events_proto_depIdxs = nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"go-microservice/account"
+	"go-microservice/order"
 	"time"
 )
 
@@ -9,33 +11,57 @@ type accountResolver struct {
 	server *Server
 }
 
+// Orders загружает заказы через OrdersByAccount, чтобы список аккаунтов обходился одним запросом к сервису заказов.
+// Батч доступен только администратору; остальные видят лишь свой аккаунт и читают его заказы напрямую.
 func (r *accountResolver) Orders(ctx context.Context, obj *Account) ([]*Order, error) {
+	if err := authorizeAccount(ctx, obj.ID); err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
-	orderList, err := r.server.orderClient.GetOrdersForAccount(ctx, obj.ID)
+	var orderList []order.Order
+	var err error
+	if viewerFrom(ctx).Role.Includes(account.RoleAdmin) {
+		orderList, err = loadersFor(ctx, r.server).OrdersByAccount.Load(ctx, obj.ID)
+	} else {
+		orderList, err = r.server.orderClient.GetOrdersForAccount(ctx, obj.ID)
+	}
 	if err != nil {
 		return nil, err
 	}
 	var orders []*Order
 	for _, o := range orderList {
-		orderedProduct := make([]*OrderedProduct, 0, len(o.Products))
-		for _, p := range o.Products {
-			orderedProduct = append(orderedProduct, &OrderedProduct{
-				ID:          p.ID,
-				Name:        p.Name,
-				Description: p.Description,
-				Price:       p.Price,
-				Quantity:    int(p.Quantity),
-			})
-		}
-
-		orders = append(orders, &Order{
-			ID:         o.ID,
-			CreatedAt:  o.CreatedAt,
-			TotalPrice: o.TotalPrice,
-			Products:   orderedProduct,
-		})
+		orders = append(orders, toOrder(o))
 	}
 	return orders, nil
 }
+
+// OrdersConnection постранично загружает заказы аккаунта. Аргументы страницы у каждого аккаунта свои,
+// поэтому в отличие от Orders запросы не объединяются в батч.
+func (r *accountResolver) OrdersConnection(ctx context.Context, obj *Account, first *int, after *string) (*OrderConnection, error) {
+	if err := authorizeAccount(ctx, obj.ID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	cursor, limit, err := pageArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+	page, err := r.server.orderClient.GetOrdersPageForAccount(ctx, obj.ID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*OrderEdge, len(page.Edges))
+	cursors := make([]string, len(page.Edges))
+	for i, e := range page.Edges {
+		edges[i] = &OrderEdge{Cursor: e.Cursor, Node: toOrder(e.Order)}
+		cursors[i] = e.Cursor
+	}
+	return &OrderConnection{Edges: edges, PageInfo: toPageInfo(cursors, page.HasNextPage)}, nil
+}
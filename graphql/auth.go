@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-microservice/account"
+	"go-microservice/platform"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// tokenIssuerName записывается в iss и проверяется при разборе токена
+const tokenIssuerName = "go-microservice/graphql"
+
+var errInvalidToken = errors.New("invalid access token")
+
+type viewerKey struct{}
+
+// viewer - аккаунт, от имени которого выполняется запрос
+type viewer struct {
+	ID   string
+	Role account.Role
+}
+
+// accessClaims - содержимое access токена. Роль хранится в токене, чтобы @hasRole
+// не ходил в сервис аккаунтов на каждое поле.
+type accessClaims struct {
+	jwt.RegisteredClaims
+	Role account.Role `json:"role"`
+}
+
+// TokenIssuer выпускает и проверяет access токены (JWT, HS256). В sub записывается ID аккаунта.
+// Токены короткие: продлеваются мутацией refreshToken через сервис аккаунтов.
+type TokenIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenIssuer(secret string, ttl time.Duration) *TokenIssuer {
+	return &TokenIssuer{secret: []byte(secret), ttl: ttl}
+}
+
+// Issue выпускает токен для аккаунта и возвращает время его истечения
+func (t *TokenIssuer) Issue(a account.Account) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(t.ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuerName,
+			Subject:   a.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Role: a.Role,
+	})
+	signed, err := token.SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify проверяет подпись и срок действия токена и возвращает аккаунт из него
+func (t *TokenIssuer) Verify(token string) (*viewer, error) {
+	claims := &accessClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuerName),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Subject == "" || !claims.Role.Valid() {
+		return nil, errInvalidToken
+	}
+	return &viewer{ID: claims.Subject, Role: claims.Role}, nil
+}
+
+// withAuth проверяет заголовок Authorization: Bearer <token>. Запрос без заголовка проходит
+// анонимно, а поля, которым нужен аккаунт, сами возвращают UNAUTHENTICATED.
+// С неверным или просроченным токеном запрос отклоняется целиком.
+func withAuth(tokens *TokenIssuer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		v, err := tokens.Verify(token)
+		if !ok || err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"errors": gqlerror.List{{
+					Message:    errInvalidToken.Error(),
+					Extensions: map[string]interface{}{"code": "UNAUTHENTICATED"},
+				}},
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withViewer(r.Context(), v)))
+	})
+}
+
+// websocketInit проверяет access токен подписок: браузер не может передать заголовок
+// Authorization при открытии websocket, поэтому токен приходит в payload connection_init.
+// Без токена соединение анонимное, с неверным токеном - отклоняется.
+func websocketInit(tokens *TokenIssuer) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		header := payload.Authorization()
+		// Payload не возвращается в connection_ack: в нём токен
+		if header == "" {
+			return ctx, nil, nil
+		}
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		v, err := tokens.Verify(token)
+		if !ok || err != nil {
+			return ctx, nil, errInvalidToken
+		}
+		return withViewer(ctx, v), nil, nil
+	}
+}
+
+func withViewer(ctx context.Context, v *viewer) context.Context {
+	ctx = context.WithValue(ctx, viewerKey{}, v)
+	// Пользователь передаётся в gRPC metadata, чтобы сервисы могли проверять права сами
+	return platform.WithCaller(ctx, platform.Caller{Subject: v.ID, Roles: []string{string(v.Role)}})
+}
+
+// viewerFrom возвращает аккаунт из access токена запроса, nil для анонимного запроса
+func viewerFrom(ctx context.Context) *viewer {
+	v, _ := ctx.Value(viewerKey{}).(*viewer)
+	return v
+}
+
+// requireViewer возвращает аккаунт запроса или ошибку UNAUTHENTICATED для анонимного запроса
+func requireViewer(ctx context.Context) (*viewer, error) {
+	v := viewerFrom(ctx)
+	if v == nil {
+		return nil, &gqlerror.Error{
+			Message:    "authentication required",
+			Path:       graphql.GetPath(ctx),
+			Extensions: map[string]interface{}{"code": "UNAUTHENTICATED"},
+		}
+	}
+	return v, nil
+}
+
+// hasRole реализует директиву @hasRole
+func hasRole(ctx context.Context, obj interface{}, next graphql.Resolver, role Role) (interface{}, error) {
+	v, err := requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Role.Includes(fromRole(role)) {
+		return nil, forbidden(ctx, fmt.Sprintf("role %s is required", role))
+	}
+	return next(ctx)
+}
+
+// authorizeAccount пропускает владельца аккаунта accountID и ADMIN
+func authorizeAccount(ctx context.Context, accountID string) error {
+	v, err := requireViewer(ctx)
+	if err != nil {
+		return err
+	}
+	if v.ID != accountID && !v.Role.Includes(account.RoleAdmin) {
+		return forbidden(ctx, "access to another account is not allowed")
+	}
+	return nil
+}
+
+func forbidden(ctx context.Context, message string) error {
+	return &gqlerror.Error{
+		Message:    message,
+		Path:       graphql.GetPath(ctx),
+		Extensions: map[string]interface{}{"code": "FORBIDDEN"},
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"go-microservice/account"
+	"go-microservice/platform"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+var customer = account.Account{ID: "account-1", Role: account.RoleCustomer}
+
+func TestTokenIssuerRoundTrip(t *testing.T) {
+	tokens := NewTokenIssuer("secret", time.Minute)
+
+	token, expiresAt, err := tokens.Issue(customer)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if until := time.Until(expiresAt); until <= 0 || until > time.Minute {
+		t.Errorf("expiresAt in %v, want within a minute", until)
+	}
+
+	v, err := tokens.Verify(token)
+	if err != nil || v.ID != customer.ID || v.Role != customer.Role {
+		t.Errorf("Verify = %+v, %v, want %s with role %s", v, err, customer.ID, customer.Role)
+	}
+}
+
+func TestTokenIssuerRejectsInvalidTokens(t *testing.T) {
+	tokens := NewTokenIssuer("secret", time.Minute)
+	otherKey, _, _ := NewTokenIssuer("other secret", time.Minute).Issue(customer)
+	expired, _, _ := NewTokenIssuer("secret", -time.Minute).Issue(customer)
+	valid, _, _ := tokens.Issue(customer)
+
+	tests := map[string]string{
+		"other key": otherKey,
+		"expired":   expired,
+		"tampered":  valid[:len(valid)-2] + "xx",
+		"garbage":   "not a token",
+		// alg=none без подписи
+		"unsigned": "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJzdWIiOiJhY2NvdW50LTEifQ.",
+	}
+	for name, token := range tests {
+		if _, err := tokens.Verify(token); err == nil {
+			t.Errorf("Verify(%s) succeeded, want error", name)
+		}
+	}
+}
+
+func TestWithAuth(t *testing.T) {
+	tokens := NewTokenIssuer("secret", time.Minute)
+	valid, _, _ := tokens.Issue(customer)
+
+	var got *viewer
+	h := withAuth(tokens, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = viewerFrom(r.Context())
+	}))
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantID     string
+	}{
+		{name: "anonymous", wantStatus: http.StatusOK},
+		{name: "valid token", header: "Bearer " + valid, wantStatus: http.StatusOK, wantID: "account-1"},
+		{name: "invalid token", header: "Bearer nope", wantStatus: http.StatusUnauthorized},
+		{name: "wrong scheme", header: "Basic " + valid, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var gotID string
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.wantID {
+				t.Errorf("viewer = %q, want %q", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestWebsocketInit(t *testing.T) {
+	tokens := NewTokenIssuer("secret", time.Minute)
+	valid, _, _ := tokens.Issue(customer)
+	init := websocketInit(tokens)
+
+	ctx, _, err := init(context.Background(), transport.InitPayload{"Authorization": "Bearer " + valid})
+	if v := viewerFrom(ctx); err != nil || v == nil || v.ID != customer.ID {
+		t.Errorf("valid token: viewer = %+v, %v, want %s", v, err, customer.ID)
+	}
+	if c, ok := platform.CallerFrom(ctx); !ok || c.Subject != customer.ID {
+		t.Errorf("valid token: caller = %+v, want %s for gRPC calls", c, customer.ID)
+	}
+
+	ctx, _, err = init(context.Background(), transport.InitPayload{})
+	if err != nil || viewerFrom(ctx) != nil {
+		t.Errorf("no token: viewer = %+v, %v, want anonymous connection", viewerFrom(ctx), err)
+	}
+
+	if _, _, err := init(context.Background(), transport.InitPayload{"Authorization": "Bearer not a token"}); !errors.Is(err, errInvalidToken) {
+		t.Errorf("invalid token: error = %v, want %v", err, errInvalidToken)
+	}
+}
+
+func errorCode(err error) string {
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) {
+		return ""
+	}
+	code, _ := gqlErr.Extensions["code"].(string)
+	return code
+}
+
+func TestHasRole(t *testing.T) {
+	next := func(ctx context.Context) (interface{}, error) { return "ok", nil }
+	as := func(role account.Role) context.Context {
+		return context.WithValue(context.Background(), viewerKey{}, &viewer{ID: "account-1", Role: role})
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		role     Role
+		wantCode string
+	}{
+		{name: "anonymous", ctx: context.Background(), role: RoleCustomer, wantCode: "UNAUTHENTICATED"},
+		{name: "same role", ctx: as(account.RoleMerchandiser), role: RoleMerchandiser},
+		{name: "admin includes merchandiser", ctx: as(account.RoleAdmin), role: RoleMerchandiser},
+		{name: "customer below merchandiser", ctx: as(account.RoleCustomer), role: RoleMerchandiser, wantCode: "FORBIDDEN"},
+		{name: "merchandiser below admin", ctx: as(account.RoleMerchandiser), role: RoleAdmin, wantCode: "FORBIDDEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := hasRole(tt.ctx, nil, next, tt.role)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("error = %v (code %q), want code %q", err, code, tt.wantCode)
+			}
+			if tt.wantCode == "" && res != "ok" {
+				t.Errorf("result = %v, want resolver result", res)
+			}
+		})
+	}
+}
+
+func TestAuthorizeAccount(t *testing.T) {
+	owner := context.WithValue(context.Background(), viewerKey{}, &viewer{ID: "account-1", Role: account.RoleCustomer})
+	admin := context.WithValue(context.Background(), viewerKey{}, &viewer{ID: "admin", Role: account.RoleAdmin})
+	merchandiser := context.WithValue(context.Background(), viewerKey{}, &viewer{ID: "staff", Role: account.RoleMerchandiser})
+
+	if err := authorizeAccount(owner, "account-1"); err != nil {
+		t.Errorf("owner: %v", err)
+	}
+	if err := authorizeAccount(admin, "account-1"); err != nil {
+		t.Errorf("admin: %v", err)
+	}
+	if code := errorCode(authorizeAccount(owner, "account-2")); code != "FORBIDDEN" {
+		t.Errorf("other customer code = %q, want FORBIDDEN", code)
+	}
+	if code := errorCode(authorizeAccount(merchandiser, "account-1")); code != "FORBIDDEN" {
+		t.Errorf("merchandiser code = %q, want FORBIDDEN", code)
+	}
+}
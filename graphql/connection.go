@@ -0,0 +1,45 @@
+package main
+
+import "go-microservice/platform"
+
+// Размер страницы connection в сервисах: по умолчанию и наибольший
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var errInvalidFirst = platform.NewError(platform.InvalidArgument, "INVALID_PAGE_SIZE", "first must not be negative")
+
+// pageArgs переводит аргументы connection в параметры сервисов.
+// Курсоры выдают и разбирают сами сервисы, шлюз передаёт их как есть.
+func pageArgs(first *int, after *string) (string, uint64, error) {
+	var cursor string
+	if after != nil {
+		cursor = *after
+	}
+	if first == nil {
+		return cursor, 0, nil
+	}
+	if *first < 0 {
+		return "", 0, errInvalidFirst
+	}
+	return cursor, uint64(*first), nil
+}
+
+func toPageInfo(cursors []string, hasNextPage bool) *PageInfo {
+	info := &PageInfo{HasNextPage: hasNextPage}
+	if len(cursors) > 0 {
+		info.StartCursor = &cursors[0]
+		info.EndCursor = &cursors[len(cursors)-1]
+	}
+	return info
+}
+
+// pageComplexity - сложность connection: сложность одного элемента, умноженная на размер страницы
+func pageComplexity(childComplexity int, first *int) int {
+	n := defaultPageSize
+	if first != nil && *first > 0 {
+		n = min(*first, maxPageSize)
+	}
+	return 1 + childComplexity*n
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"go-microservice/account"
+	"go-microservice/catalog"
+	"go-microservice/money"
+	"go-microservice/order"
+	"strings"
+)
+
+func toAccount(a account.Account) *Account {
+	return &Account{
+		ID:        a.ID,
+		Name:      a.Name,
+		Status:    AccountStatus(strings.ToUpper(string(a.Status))),
+		Role:      toRole(a.Role),
+		UpdatedAt: a.UpdatedAt,
+	}
+}
+
+func toRole(r account.Role) Role {
+	return Role(strings.ToUpper(string(r)))
+}
+
+func fromRole(r Role) account.Role {
+	return account.Role(strings.ToLower(string(r)))
+}
+
+func toMoney(m money.Money) *Money {
+	return &Money{
+		Amount:     m.Decimal(),
+		Currency:   m.Currency,
+		MinorUnits: int(m.Amount),
+	}
+}
+
+func fromMoneyInput(in *MoneyInput) (money.Money, error) {
+	return money.Parse(in.Amount, in.Currency)
+}
+
+func toExchangeRate(r money.Rate) *ExchangeRate {
+	if r.From == "" {
+		return nil
+	}
+	return &ExchangeRate{
+		From:  r.From,
+		To:    r.To,
+		Value: r.Value,
+	}
+}
+
+func toProduct(p catalog.Product) *Product {
+	return &Product{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       toMoney(p.Price),
+		Stock:       int(p.Stock),
+	}
+}
+
+func toOrder(o order.Order) *Order {
+	products := make([]*OrderedProduct, 0, len(o.Products))
+	for _, p := range o.Products {
+		products = append(products, &OrderedProduct{
+			ID:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       toMoney(p.Price),
+			Quantity:    int(p.Quantity),
+		})
+	}
+
+	return &Order{
+		ID:            o.ID,
+		CreatedAt:     o.CreatedAt,
+		TotalPrice:    toMoney(o.TotalPrice),
+		ExchangeRate:  toExchangeRate(o.ExchangeRate),
+		Products:      products,
+		Status:        toOrderStatus(o.Status),
+		StatusHistory: toOrderStatusHistory(o.StatusHistory),
+	}
+}
+
+func toCart(c order.PricedCart) *Cart {
+	cart := &Cart{Items: make([]*CartItem, 0, len(c.Items))}
+	for _, item := range c.Items {
+		cartItem := &CartItem{
+			ProductID:   item.ProductID,
+			Name:        item.Name,
+			Description: item.Description,
+			Quantity:    int(item.Quantity),
+			Available:   item.Available,
+		}
+		if item.Available {
+			cartItem.Price = toMoney(item.Price)
+			cartItem.Subtotal = toMoney(item.Subtotal)
+		}
+		cart.Items = append(cart.Items, cartItem)
+	}
+	if c.TotalPrice.Currency != "" {
+		cart.TotalPrice = toMoney(c.TotalPrice)
+	}
+	return cart
+}
+
+// optionalString возвращает значение необязательного аргумента, пустую строку если он не задан
+func optionalString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func toOrderStatus(s order.OrderStatus) OrderStatus {
+	return OrderStatus(strings.ToUpper(string(s)))
+}
+
+func fromOrderStatus(s OrderStatus) order.OrderStatus {
+	return order.OrderStatus(strings.ToLower(string(s)))
+}
+
+func toOrderStatusHistory(history []order.StatusChange) []*OrderStatusChange {
+	changes := make([]*OrderStatusChange, 0, len(history))
+	for _, c := range history {
+		change := &OrderStatusChange{
+			To:        toOrderStatus(c.To),
+			ChangedAt: c.ChangedAt,
+		}
+		if c.From != "" {
+			from := toOrderStatus(c.From)
+			change.From = &from
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func toOrderStatusUpdate(orderID string, history []order.StatusChange) *OrderStatusUpdate {
+	update := &OrderStatusUpdate{
+		OrderID:       orderID,
+		StatusHistory: toOrderStatusHistory(history),
+	}
+	if len(history) > 0 {
+		update.Status = toOrderStatus(history[len(history)-1].To)
+	}
+	return update
+}
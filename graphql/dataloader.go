@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Loader собирает ключи, запрошенные резолверами в течение wait, и загружает их одним вызовом fetch.
+// gqlgen резолвит элементы списков параллельно, поэтому поля вроде accounts { orders } попадают в один батч.
+// Результаты кешируются до конца запроса: Loader создаётся на каждый HTTP запрос.
+type Loader[K comparable, V any] struct {
+	// ctx - контекст HTTP запроса. fetch выполняется в нём, а не в контексте первого резолвера,
+	// чтобы таймаут или отмена одного поля не ломали весь батч.
+	ctx      context.Context
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*loaderResult[V]
+	batch *loaderBatch[K, V]
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loaderResult[V]
+}
+
+// NewLoader создаёт загрузчик. fetch возвращает значения по ключам;
+// для ключей, отсутствующих в ответе, Load возвращает нулевое значение V.
+func NewLoader[K comparable, V any](ctx context.Context, wait time.Duration, maxBatch int, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		ctx:      ctx,
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*loaderResult[V]),
+	}
+}
+
+// Load возвращает значение по ключу, дожидаясь загрузки батча
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	r, ok := l.cache[key]
+	if !ok {
+		r = &loaderResult[V]{done: make(chan struct{})}
+		l.cache[key] = r
+		l.enqueue(key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// enqueue добавляет ключ в текущий батч. Вызывается под l.mu.
+func (l *Loader[K, V]) enqueue(key K, r *loaderResult[V]) {
+	if l.batch == nil {
+		b := &loaderBatch[K, V]{}
+		l.batch = b
+		time.AfterFunc(l.wait, func() { l.dispatch(b) })
+	}
+
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.results = append(l.batch.results, r)
+	if len(l.batch.keys) >= l.maxBatch {
+		b := l.batch
+		l.batch = nil
+		go l.run(b)
+	}
+}
+
+// dispatch запускает батч по таймеру, если он ещё не ушёл из-за maxBatch
+func (l *Loader[K, V]) dispatch(b *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.batch != b {
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+func (l *Loader[K, V]) run(b *loaderBatch[K, V]) {
+	values, err := l.fetch(l.ctx, b.keys)
+	if err != nil {
+		// Ошибку не кешируем: повторный Load в том же запросе попробует снова
+		l.mu.Lock()
+		for _, key := range b.keys {
+			delete(l.cache, key)
+		}
+		l.mu.Unlock()
+	}
+
+	for i, r := range b.results {
+		if err != nil {
+			r.err = err
+		} else {
+			r.value = values[b.keys[i]]
+		}
+		close(r.done)
+	}
+}
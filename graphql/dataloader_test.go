@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingFetch возвращает key*10 и запоминает, какими батчами его вызывали
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (f *recordingFetch) fetch(ctx context.Context, keys []int) (map[int]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	batch := append([]int(nil), keys...)
+	sort.Ints(batch)
+	f.batches = append(f.batches, batch)
+	if f.err != nil {
+		return nil, f.err
+	}
+	values := make(map[int]int, len(keys))
+	for _, k := range keys {
+		if k >= 0 {
+			values[k] = k * 10
+		}
+	}
+	return values, nil
+}
+
+func loadAll(t *testing.T, l *Loader[int, int], keys []int) []int {
+	t.Helper()
+	values := make([]int, len(keys))
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.Load(context.Background(), k)
+			if err != nil {
+				t.Errorf("Load(%d): %v", k, err)
+			}
+			values[i] = v
+		}()
+	}
+	wg.Wait()
+	return values
+}
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	f := &recordingFetch{}
+	l := NewLoader(context.Background(), 50*time.Millisecond, 100, f.fetch)
+
+	values := loadAll(t, l, []int{1, 2, 3, 2, 1, -1})
+	for i, want := range []int{10, 20, 30, 20, 10, 0} {
+		if values[i] != want {
+			t.Errorf("value[%d] = %d, want %d", i, values[i], want)
+		}
+	}
+	if len(f.batches) != 1 || len(f.batches[0]) != 4 {
+		t.Fatalf("batches = %v, want one batch of 4 unique keys", f.batches)
+	}
+
+	// Повторные ключи берутся из кеша
+	loadAll(t, l, []int{1, 3})
+	if len(f.batches) != 1 {
+		t.Errorf("batches = %v, want cached values", f.batches)
+	}
+}
+
+func TestLoaderSplitsByMaxBatch(t *testing.T) {
+	f := &recordingFetch{}
+	l := NewLoader(context.Background(), 10*time.Millisecond, 2, f.fetch)
+
+	loadAll(t, l, []int{1, 2, 3, 4, 5})
+	if len(f.batches) != 3 {
+		t.Errorf("batches = %v, want 3 batches of at most 2 keys", f.batches)
+	}
+	for _, b := range f.batches {
+		if len(b) > 2 {
+			t.Errorf("batch %v exceeds maxBatch", b)
+		}
+	}
+}
+
+func TestLoaderDoesNotCacheErrors(t *testing.T) {
+	f := &recordingFetch{err: errors.New("unavailable")}
+	l := NewLoader(context.Background(), time.Millisecond, 100, f.fetch)
+
+	if _, err := l.Load(context.Background(), 1); !errors.Is(err, f.err) {
+		t.Fatalf("Load error = %v, want %v", err, f.err)
+	}
+
+	f.mu.Lock()
+	f.err = nil
+	f.mu.Unlock()
+	if v, err := l.Load(context.Background(), 1); err != nil || v != 10 {
+		t.Errorf("Load after error = %d, %v, want 10", v, err)
+	}
+}
+
+func TestLoaderRespectsCallerContext(t *testing.T) {
+	l := NewLoader(context.Background(), time.Hour, 100, (&recordingFetch{}).fetch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"go-microservice/platform"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcErrorCodes - extensions.code для gRPC ошибок без Reason, например проверок запроса в сервисах
+var grpcErrorCodes = map[codes.Code]string{
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "CONFLICT",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+	codes.PermissionDenied:   "FORBIDDEN",
+	codes.DeadlineExceeded:   "TIMEOUT",
+	codes.Unavailable:        "UNAVAILABLE",
+}
+
+// presentError добавляет к ошибке резолвера машиночитаемый extensions.code:
+//   - ошибка домена - её Reason и Metadata, например {"code": "OUT_OF_STOCK", "productId": "..."}
+//   - прочая gRPC ошибка - код по gRPC статусу, в сообщении только описание без "rpc error: ..."
+//   - остальные - INTERNAL
+//
+// Ошибки, у которых code уже есть (UNAUTHENTICATED, FORBIDDEN из auth.go), не меняются.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if _, ok := gqlErr.Extensions["code"]; ok {
+		return gqlErr
+	}
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+
+	var domainErr *platform.Error
+	if errors.As(err, &domainErr) {
+		gqlErr.Extensions["code"] = domainErr.Reason
+		for k, v := range domainErr.Metadata {
+			gqlErr.Extensions[k] = v
+		}
+		return gqlErr
+	}
+	if st, ok := status.FromError(err); ok {
+		code, known := grpcErrorCodes[st.Code()]
+		if !known {
+			code = "INTERNAL"
+		}
+		gqlErr.Message = st.Message()
+		gqlErr.Extensions["code"] = code
+		return gqlErr
+	}
+	gqlErr.Extensions["code"] = "INTERNAL"
+	return gqlErr
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-microservice/catalog"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPresentError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    string
+		wantMessage string
+	}{
+		{"domain error", fmt.Errorf("%w: product p1", catalog.ErrOutOfStock.WithMetadata("productId", "p1")), "OUT_OF_STOCK", "out of stock: product p1"},
+		{"grpc status", status.Error(codes.InvalidArgument, "id is required"), "INVALID_ARGUMENT", "id is required"},
+		{"unknown grpc code", status.Error(codes.DataLoss, "corrupted"), "INTERNAL", "corrupted"},
+		{"other error", errors.New("boom"), "INTERNAL", "boom"},
+		{"auth error", forbidden(context.Background(), "role ADMIN is required"), "FORBIDDEN", "role ADMIN is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := presentError(context.Background(), tt.err)
+			if got.Extensions["code"] != tt.wantCode || got.Message != tt.wantMessage {
+				t.Errorf("got %q with code %v, want %q with code %s", got.Message, got.Extensions["code"], tt.wantMessage, tt.wantCode)
+			}
+		})
+	}
+
+	got := presentError(context.Background(), catalog.ErrOutOfStock.WithMetadata("productId", "p1"))
+	if got.Extensions["productId"] != "p1" {
+		t.Errorf("extensions = %v, want productId from error metadata", got.Extensions)
+	}
+}
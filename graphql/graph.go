@@ -4,6 +4,8 @@ import (
 	"go-microservice/account"
 	"go-microservice/catalog"
 	"go-microservice/order"
+	"go-microservice/platform"
+	"log/slog"
 
 	"github.com/99designs/gqlgen/graphql"
 )
@@ -12,21 +14,23 @@ type Server struct {
 	accountClient *account.Client
 	catalogClient *catalog.Client
 	orderClient   *order.Client
+	tokens        *TokenIssuer
+	logger        *slog.Logger
 }
 
-func NewGraphQlServer(accountUrl, catalogUrl, orderUrl string) (*Server, error) {
-	accountClient, err := account.NewClient(accountUrl)
+func NewGraphQlServer(accountUrl, catalogUrl, orderUrl string, tlsConfig platform.TLSConfig, tokens *TokenIssuer, logger *slog.Logger) (*Server, error) {
+	accountClient, err := account.NewClient(accountUrl, tlsConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	catalogClient, err := catalog.NewClient(catalogUrl)
+	catalogClient, err := catalog.NewClient(catalogUrl, tlsConfig)
 	if err != nil {
 		accountClient.Close()
 		return nil, err
 	}
 
-	orderClient, err := order.NewClient(orderUrl)
+	orderClient, err := order.NewClient(orderUrl, tlsConfig)
 	if err != nil {
 		accountClient.Close()
 		catalogClient.Close()
@@ -37,9 +41,18 @@ func NewGraphQlServer(accountUrl, catalogUrl, orderUrl string) (*Server, error)
 		accountClient: accountClient,
 		catalogClient: catalogClient,
 		orderClient:   orderClient,
+		tokens:        tokens,
+		logger:        logger,
 	}, nil
 }
 
+// Close закрывает соединения с сервисами
+func (s *Server) Close() {
+	s.orderClient.Close()
+	s.catalogClient.Close()
+	s.accountClient.Close()
+}
+
 func (s *Server) Mutation() MutationResolver {
 	return &mutationResolver{
 		server: s,
@@ -51,6 +64,13 @@ func (s *Server) Query() QueryResolver {
 		server: s,
 	}
 }
+
+func (s *Server) Subscription() SubscriptionResolver {
+	return &subscriptionResolver{
+		server: s,
+	}
+}
+
 func (s *Server) Account() AccountResolver {
 	return &accountResolver{
 		server: s,
@@ -58,7 +78,21 @@ func (s *Server) Account() AccountResolver {
 }
 
 func (s *Server) ToExecutableSchema() graphql.ExecutableSchema {
-	return NewExecutableSchema(Config{
+	c := Config{
 		Resolvers: s,
-	})
+		Directives: DirectiveRoot{
+			HasRole: hasRole,
+		},
+	}
+	// Страницы connection стоят столько, сколько в них может оказаться элементов
+	c.Complexity.Query.AccountsConnection = func(childComplexity int, first *int, after *string) int {
+		return pageComplexity(childComplexity, first)
+	}
+	c.Complexity.Query.ProductsConnection = func(childComplexity int, first *int, after, query, currency *string) int {
+		return pageComplexity(childComplexity, first)
+	}
+	c.Complexity.Account.OrdersConnection = func(childComplexity int, first *int, after *string) int {
+		return pageComplexity(childComplexity, first)
+	}
+	return NewExecutableSchema(c)
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gorilla/websocket"
+	"github.com/rs/cors"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// newGraphQLHandler собирает обработчик /graphql: транспорты, кеши, ограничения и расширения по cfg
+func newGraphQLHandler(s *Server, cfg AppConfig) *handler.Server {
+	srv := handler.New(s.ToExecutableSchema())
+
+	srv.AddTransport(transport.Websocket{
+		Upgrader:              websocket.Upgrader{CheckOrigin: checkOrigin(cfg.CORSAllowedOrigins)},
+		InitFunc:              websocketInit(s.tokens),
+		KeepAlivePingInterval: cfg.WebsocketKeepAlive,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{MaxUploadSize: cfg.MaxUploadSize})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](cfg.QueryCacheSize))
+	srv.SetErrorPresenter(presentError)
+
+	if cfg.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	if cfg.APQCacheSize > 0 {
+		srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New[string](cfg.APQCacheSize)})
+	}
+	if cfg.ComplexityLimit > 0 {
+		srv.Use(extension.FixedComplexityLimit(cfg.ComplexityLimit))
+	}
+	srv.Use(tracingExtension{})
+	srv.Use(metricsExtension{})
+	return srv
+}
+
+// withCORS разрешает запросы из браузера с origins; без origins CORS заголовки не отдаются.
+// Токен передаётся в заголовке Authorization, а не в cookie, поэтому credentials не нужны.
+func withCORS(origins []string, next http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return next
+	}
+	return cors.New(cors.Options{
+		AllowedOrigins: origins,
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+		AllowedHeaders: []string{"Content-Type", "Authorization", "X-Request-ID"},
+		ExposedHeaders: []string{"X-Request-ID"},
+	}).Handler(next)
+}
+
+// checkOrigin применяет к websocket те же origins, что и CORS; свой origin (playground) разрешён всегда.
+// Запросы не из браузера приходят без Origin и пропускаются.
+func checkOrigin(origins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || sameOrigin(r, origin) || slices.Contains(origins, "*") || slices.Contains(origins, origin)
+	}
+}
+
+func sameOrigin(r *http.Request, origin string) bool {
+	return origin == "http://"+r.Host || origin == "https://"+r.Host
+}
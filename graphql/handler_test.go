@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testConfig() AppConfig {
+	return AppConfig{
+		Introspection:   true,
+		ComplexityLimit: 1000,
+		QueryCacheSize:  10,
+		APQCacheSize:    10,
+	}
+}
+
+// postQuery отправляет body в обработчик, собранный по cfg, и возвращает коды ошибок ответа
+func postQuery(t *testing.T, cfg AppConfig, body string) []string {
+	t.Helper()
+	h := newGraphQLHandler(&Server{tokens: NewTokenIssuer("secret", time.Minute)}, cfg)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var res struct {
+		Errors []struct {
+			Extensions map[string]any `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+	codes := []string{}
+	for _, e := range res.Errors {
+		code, _ := e.Extensions["code"].(string)
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func TestGraphQLHandlerIntrospection(t *testing.T) {
+	query := `{"query": "{ __schema { queryType { name } } }"}`
+
+	if codes := postQuery(t, testConfig(), query); len(codes) != 0 {
+		t.Errorf("introspection enabled: errors %v, want none", codes)
+	}
+	cfg := testConfig()
+	cfg.Introspection = false
+	if codes := postQuery(t, cfg, query); len(codes) == 0 {
+		t.Error("introspection disabled: no errors, want introspection to be rejected")
+	}
+}
+
+func TestGraphQLHandlerComplexityLimit(t *testing.T) {
+	cfg := testConfig()
+	cfg.ComplexityLimit = 50
+
+	// 100 товаров по 2 поля превышают лимит, 10 - нет
+	big := `{"query": "{ productsConnection(first: 100) { edges { node { id name } } } }"}`
+	if codes := postQuery(t, cfg, big); len(codes) != 1 || codes[0] != "COMPLEXITY_LIMIT_EXCEEDED" {
+		t.Errorf("errors = %v, want COMPLEXITY_LIMIT_EXCEEDED", codes)
+	}
+	if got := pageComplexity(2, nil); got != 1+2*defaultPageSize {
+		t.Errorf("pageComplexity without first = %d, want default page size", got)
+	}
+	tooMany := 1000
+	if got := pageComplexity(2, &tooMany); got != 1+2*maxPageSize {
+		t.Errorf("pageComplexity(first: 1000) = %d, want max page size", got)
+	}
+}
+
+func TestGraphQLHandlerPersistedQuery(t *testing.T) {
+	// Хеш без текста запроса, которого нет в кеше: клиент должен прислать запрос целиком
+	hashOnly := `{"extensions": {"persistedQuery": {"version": 1, "sha256Hash": "ecf4edb46db40b5132295c0291d62fb65d6759a9eedfa4d5d612dd5ec54a6b38"}}}`
+	if codes := postQuery(t, testConfig(), hashOnly); len(codes) != 1 || codes[0] != "PERSISTED_QUERY_NOT_FOUND" {
+		t.Errorf("errors = %v, want PERSISTED_QUERY_NOT_FOUND", codes)
+	}
+}
+
+func TestWithCORS(t *testing.T) {
+	h := withCORS([]string{"https://shop.example"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/graphql", nil)
+	req.Header.Set("Origin", "https://shop.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "authorization,content-type")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://shop.example" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want https://shop.example", got)
+	}
+
+	req.Header.Set("Origin", "https://evil.example")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q for unknown origin, want none", got)
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	check := checkOrigin([]string{"https://shop.example"})
+	tests := map[string]bool{
+		"":                      true,
+		"https://shop.example":  true,
+		"http://localhost:8080": true,
+		"https://evil.example":  false,
+	}
+	for origin, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/graphql", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if got := check(req); got != want {
+			t.Errorf("checkOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+}
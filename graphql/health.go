@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"go-microservice/platform"
+	"net/http"
+)
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// healthChecks проверяет сервисы, без которых шлюз не может отвечать на запросы
+func (s *Server) healthChecks() []platform.HealthCheck {
+	return []platform.HealthCheck{
+		{Name: "account", Check: s.accountClient.Health},
+		{Name: "catalog", Check: s.catalogClient.Health},
+		{Name: "order", Check: s.orderClient.Health},
+	}
+}
+
+// healthz отвечает, что процесс шлюза жив; зависимости не проверяются
+func healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// readyz проверяет сервисы и отвечает 503, если хотя бы один недоступен или не готов
+func readyz(checks []platform.HealthCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := healthResponse{Status: "ok", Checks: make(map[string]string, len(checks))}
+		code := http.StatusOK
+		for name, err := range platform.RunChecks(r.Context(), checks) {
+			if err != nil {
+				res.Checks[name] = err.Error()
+				res.Status = "unavailable"
+				code = http.StatusServiceUnavailable
+				continue
+			}
+			res.Checks[name] = "ok"
+		}
+		writeHealth(w, code, res)
+	})
+}
+
+func writeHealth(w http.ResponseWriter, code int, res healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-microservice/platform"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadyz(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name     string
+		checks   []platform.HealthCheck
+		wantCode int
+		want     map[string]string
+	}{
+		{
+			name:     "all services up",
+			checks:   []platform.HealthCheck{{Name: "account", Check: up}, {Name: "order", Check: up}},
+			wantCode: http.StatusOK,
+			want:     map[string]string{"account": "ok", "order": "ok"},
+		},
+		{
+			name:     "service down",
+			checks:   []platform.HealthCheck{{Name: "account", Check: up}, {Name: "order", Check: down}},
+			wantCode: http.StatusServiceUnavailable,
+			want:     map[string]string{"account": "ok", "order": "connection refused"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			readyz(tt.checks).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			var res healthResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatalf("decode %s: %v", rec.Body, err)
+			}
+			for name, want := range tt.want {
+				if res.Checks[name] != want {
+					t.Errorf("checks[%s] = %q, want %q", name, res.Checks[name], want)
+				}
+			}
+		})
+	}
+}
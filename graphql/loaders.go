@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"go-microservice/catalog"
+	"go-microservice/order"
+	"net/http"
+	"time"
+)
+
+const (
+	loaderWait     = 2 * time.Millisecond
+	loaderMaxBatch = 100
+)
+
+type loadersKey struct{}
+
+// productKey - товар с ценой в конкретной валюте
+type productKey struct {
+	ID       string
+	Currency string
+}
+
+// Loaders - загрузчики одного HTTP запроса
+type Loaders struct {
+	OrdersByAccount *Loader[string, []order.Order]
+	ProductByID     *Loader[productKey, *catalog.Product]
+}
+
+func newLoaders(ctx context.Context, s *Server) *Loaders {
+	return &Loaders{
+		OrdersByAccount: NewLoader(ctx, loaderWait, loaderMaxBatch, func(ctx context.Context, accountIDs []string) (map[string][]order.Order, error) {
+			ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+			defer cancel()
+
+			return s.orderClient.GetOrdersForAccounts(ctx, accountIDs)
+		}),
+		ProductByID: NewLoader(ctx, loaderWait, loaderMaxBatch, func(ctx context.Context, keys []productKey) (map[productKey]*catalog.Product, error) {
+			ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+			defer cancel()
+
+			// Цена пересчитывается каталогом, поэтому товары в разных валютах запрашиваются отдельно
+			idsByCurrency := make(map[string][]string)
+			for _, k := range keys {
+				idsByCurrency[k.Currency] = append(idsByCurrency[k.Currency], k.ID)
+			}
+
+			products := make(map[productKey]*catalog.Product, len(keys))
+			for currency, ids := range idsByCurrency {
+				found, err := s.catalogClient.GetProducts(ctx, ids, "", 0, 0, currency)
+				if err != nil {
+					return nil, err
+				}
+				for _, p := range found {
+					// Поиск по ID возвращает и удалённые товары, но для клиента их больше нет
+					if p.Deleted {
+						continue
+					}
+					products[productKey{ID: p.ID, Currency: currency}] = &p
+				}
+			}
+			return products, nil
+		}),
+	}
+}
+
+// withLoaders создаёт загрузчики на каждый запрос, чтобы кеш не переживал запрос
+func withLoaders(s *Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, newLoaders(r.Context(), s))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// loadersFor возвращает загрузчики запроса. Вне HTTP запроса (например, в тестах)
+// создаются новые загрузчики: батчинга между вызовами не будет, но результат тот же.
+func loadersFor(ctx context.Context, s *Server) *Loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return l
+	}
+	return newLoaders(ctx, s)
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"go-microservice/platform"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"time"
 
-	"github.com/99designs/gqlgen/handler"
+	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -12,6 +17,40 @@ type AppConfig struct {
 	AccountURL string `envconfig:"ACCOUNT_SERVICE_URL"`
 	CatalogURL string `envconfig:"CATALOG_SERVICE_URL"`
 	OrderURL   string `envconfig:"ORDER_SERVICE_URL"`
+	// JWTSecret - ключ подписи access токенов
+	JWTSecret      string        `envconfig:"JWT_SECRET" required:"true"`
+	AccessTokenTTL time.Duration `envconfig:"ACCESS_TOKEN_TTL" default:"15m"`
+	// CORSAllowedOrigins - origins через запятую, которым разрешены запросы из браузера; пусто - только свой
+	CORSAllowedOrigins []string `envconfig:"CORS_ALLOWED_ORIGINS"`
+	// Introspection включает __schema и __type, без неё не работает playground
+	Introspection bool `envconfig:"GRAPHQL_INTROSPECTION" default:"false"`
+	// ComplexityLimit - наибольшая сложность запроса, 0 - без ограничения
+	ComplexityLimit int `envconfig:"GRAPHQL_COMPLEXITY_LIMIT" default:"5000"`
+	// QueryCacheSize - сколько разобранных запросов хранится в LRU кеше
+	QueryCacheSize int `envconfig:"GRAPHQL_QUERY_CACHE_SIZE" default:"1000"`
+	// APQCacheSize - сколько automatic persisted queries хранится в LRU кеше, 0 выключает APQ
+	APQCacheSize       int           `envconfig:"GRAPHQL_APQ_CACHE_SIZE" default:"1000"`
+	MaxUploadSize      int64         `envconfig:"GRAPHQL_MAX_UPLOAD_SIZE" default:"33554432"`
+	WebsocketKeepAlive time.Duration `envconfig:"GRAPHQL_WEBSOCKET_KEEPALIVE" default:"10s"`
+	// MetricsAddr - адрес /metrics. Метрики отдаются отдельно от публичного порта, как у сервисов
+	MetricsAddr string `envconfig:"METRICS_ADDR" default:":9090"`
+	// TLSConfig - клиентский сертификат шлюза для вызовов сервисов
+	platform.TLSConfig
+	platform.TracingConfig
+	platform.LogConfig
+}
+
+// withRequestID берёт ID запроса из заголовка X-Request-ID или создаёт новый.
+// ID уходит во все gRPC вызовы запроса и возвращается клиенту в ответе.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" {
+			id = platform.NewRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(platform.WithRequestID(r.Context(), id)))
+	})
 }
 
 func main() {
@@ -21,30 +60,64 @@ func main() {
 		log.Fatal(err)
 	}
 
-	s, err := NewGraphQlServer(cfg.AccountURL, cfg.CatalogURL, cfg.OrderURL)
+	logger, err := platform.NewLogger("graphql", cfg.LogConfig)
 	if err != nil {
 		log.Fatal(err)
 	}
-	http.Handle("/graphql", handler.GraphQL(s.ToExecutableSchema()))
-	http.Handle("/playground", handler.Playground("akhil", "/graphql"))
+	slog.SetDefault(logger)
 
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	ctx, stop := platform.SignalContext()
+	defer stop()
 
-	// router := http.NewServeMux()
-	// router.Handle("/graphql", handler.New(s.ToExecutableSchema()))
-	// router.Handle("/playground", playground.Handler("GraphQL Playground", "/graphql"))
+	shutdownTracing, err := platform.SetupTracing(ctx, "graphql", cfg.TracingConfig)
+	if err != nil {
+		logger.Error("Tracing setup failed", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing()
 
-	// c := cors.New(cors.Options{
-	// 	AllowedOrigins: []string{"*"},
-	// 	AllowedMethods: []string{"GET", "POST", "OPTIONS"},
-	// 	AllowedHeaders: []string{"Content-Type"},
-	// })
+	tokens := NewTokenIssuer(cfg.JWTSecret, cfg.AccessTokenTTL)
+	s, err := NewGraphQlServer(cfg.AccountURL, cfg.CatalogURL, cfg.OrderURL, cfg.TLSConfig, tokens, logger)
+	if err != nil {
+		logger.Error("Service clients setup failed", "error", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+	http.Handle("/graphql", withRequestID(withTracing(withAccessLog(logger, withCORS(cfg.CORSAllowedOrigins,
+		withAuth(tokens, withLoaders(s, newGraphQLHandler(s, cfg))))))))
+	if cfg.Introspection {
+		http.Handle("/playground", playground.Handler("akhil", "/graphql"))
+	}
+	http.HandleFunc("/healthz", healthz)
+	http.Handle("/readyz", readyz(s.healthChecks()))
+
+	go func() {
+		if err := platform.ServeMetrics(ctx, cfg.MetricsAddr); err != nil {
+			logger.Error("Metrics server failed", "error", err)
+		}
+	}()
 
-	// server := &http.Server{
-	// 	Addr:    ":8080",
-	// 	Handler: c.Handler(router),
-	// }
+	server := &http.Server{Addr: ":8080"}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- server.ListenAndServe()
+	}()
+	logger.Info("Listening", "addr", server.Addr)
 
-	// log.Println("Server started at http://localhost:8080")
-	// log.Fatal(server.ListenAndServe())
+	select {
+	case err := <-errc:
+		s.Close()
+		logger.Error("HTTP server failed", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+
+	// Shutdown перестаёт принимать соединения и ждёт текущие запросы; клиенты сервисов закрываются после
+	logger.Info("Shutting down, waiting for in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), platform.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Shutdown failed", "error", err)
+	}
+	logger.Info("Stopped")
 }
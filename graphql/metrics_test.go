@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func sampleCount(t *testing.T, o prometheus.Observer) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := o.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("write metric: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func fieldContext(object, field string, isResolver bool) context.Context {
+	return graphql.WithFieldContext(context.Background(), &graphql.FieldContext{
+		Object:     object,
+		Field:      graphql.CollectedField{Field: &ast.Field{Name: field}},
+		IsResolver: isResolver,
+	})
+}
+
+func TestMetricsExtensionFields(t *testing.T) {
+	ext := metricsExtension{}
+	ok := func(ctx context.Context) (any, error) { return "value", nil }
+	fail := func(ctx context.Context) (any, error) { return nil, errors.New("boom") }
+
+	meOK := resolverDuration.WithLabelValues("Query", "me", "ok")
+	meErr := resolverDuration.WithLabelValues("Query", "me", "error")
+	name := resolverDuration.WithLabelValues("Account", "name", "ok")
+	okBefore, errBefore, nameBefore := sampleCount(t, meOK), sampleCount(t, meErr), sampleCount(t, name)
+
+	ext.InterceptField(fieldContext("Query", "me", true), ok)
+	ext.InterceptField(fieldContext("Query", "me", true), fail)
+	ext.InterceptField(fieldContext("Account", "name", false), ok)
+
+	if got := sampleCount(t, meOK) - okBefore; got != 1 {
+		t.Errorf("Query.me ok observed %d times, want 1", got)
+	}
+	if got := sampleCount(t, meErr) - errBefore; got != 1 {
+		t.Errorf("Query.me error observed %d times, want 1", got)
+	}
+	if got := sampleCount(t, name) - nameBefore; got != 0 {
+		t.Errorf("Account.name observed %d times, want 0 for a plain struct field", got)
+	}
+}
+
+func TestMetricsExtensionOperations(t *testing.T) {
+	ext := metricsExtension{}
+	opCtx := &graphql.OperationContext{Operation: &ast.OperationDefinition{Operation: ast.Mutation}}
+	opCtx.Stats.OperationStart = time.Now()
+	ctx := graphql.WithOperationContext(context.Background(), opCtx)
+
+	mutationErr := operationDuration.WithLabelValues("mutation", "error")
+	before := sampleCount(t, mutationErr)
+
+	ext.InterceptResponse(ctx, func(ctx context.Context) *graphql.Response {
+		return &graphql.Response{Errors: gqlerror.List{{Message: "forbidden"}}}
+	})
+
+	if got := sampleCount(t, mutationErr) - before; got != 1 {
+		t.Errorf("failed mutation observed %d times, want 1", got)
+	}
+}
@@ -1,7 +1,12 @@
 package main
 
+import "time"
+
 type Account struct {
-	ID     string  `json:"id"`
-	Name   string  `json:"name"`
-	Orders []Order `json:"orders"`
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Status    AccountStatus `json:"status"`
+	Role      Role          `json:"role"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	Orders    []Order       `json:"orders"`
 }
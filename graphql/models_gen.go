@@ -3,26 +3,109 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 )
 
+type AccountConnection struct {
+	Edges    []*AccountEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
+type AccountEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Account `json:"node"`
+}
+
 type AccountInput struct {
 	Name string `json:"name"`
 }
 
+// Результат входа. accessToken передаётся в заголовке Authorization: Bearer <token>
+// и действует до expiresAt; после этого его продлевает refreshToken.
+type AuthPayload struct {
+	AccessToken string    `json:"accessToken"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	// Одноразовый: каждый вызов refreshToken возвращает новый
+	RefreshToken string   `json:"refreshToken"`
+	Account      *Account `json:"account"`
+}
+
+// Корзина аккаунта. Хранятся только товары и количество,
+// названия и цены берутся из каталога при каждом запросе.
+type Cart struct {
+	Items []*CartItem `json:"items"`
+	// Сумма доступных товаров, null если таких нет
+	TotalPrice *Money `json:"totalPrice,omitempty"`
+}
+
+type CartItem struct {
+	ProductID   string `json:"productId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	// Текущая цена каталога в валюте корзины, null для удалённого товара
+	Price    *Money `json:"price,omitempty"`
+	Subtotal *Money `json:"subtotal,omitempty"`
+	// false - товар удалён из каталога: он не входит в сумму, и оформить корзину можно только без него
+	Available bool `json:"available"`
+}
+
+// Курс пересчёта: 1 единица from стоит value единиц to.
+type ExchangeRate struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+}
+
+// Денежная сумма. amount - десятичная запись (например "19.99"),
+// minorUnits - та же сумма в минимальных единицах валюты (центах, копейках).
+type Money struct {
+	Amount     string `json:"amount"`
+	Currency   string `json:"currency"`
+	MinorUnits int    `json:"minorUnits"`
+}
+
+type MoneyInput struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
 type Mutation struct {
 }
 
 type Order struct {
-	ID         string            `json:"id"`
-	CreatedAt  time.Time         `json:"createdAt"`
-	TotalPrice float64           `json:"totalPrice"`
-	Products   []*OrderedProduct `json:"products"`
+	ID            string               `json:"id"`
+	CreatedAt     time.Time            `json:"createdAt"`
+	TotalPrice    *Money               `json:"totalPrice"`
+	ExchangeRate  *ExchangeRate        `json:"exchangeRate,omitempty"`
+	Products      []*OrderedProduct    `json:"products"`
+	Status        OrderStatus          `json:"status"`
+	StatusHistory []*OrderStatusChange `json:"statusHistory"`
+}
+
+type OrderConnection struct {
+	Edges    []*OrderEdge `json:"edges"`
+	PageInfo *PageInfo    `json:"pageInfo"`
+}
+
+type OrderEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Order `json:"node"`
 }
 
 type OrderInput struct {
-	AccountID string               `json:"accountId"`
+	AccountID *string              `json:"accountId,omitempty"`
 	Products  []*OrderProductInput `json:"products"`
+	// Валюта заказа, по умолчанию - базовая валюта каталога
+	Currency *string `json:"currency,omitempty"`
+	// Уникальный ключ запроса, например UUID. Повтор createOrder с тем же ключом в течение суток
+	// возвращает уже оформленный заказ; тот же ключ с другими товарами или валютой - ошибку
+	// с extensions.code = IDEMPOTENCY_KEY_CONFLICT
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
 }
 
 type OrderProductInput struct {
@@ -30,12 +113,34 @@ type OrderProductInput struct {
 	Quantity int    `json:"quantity"`
 }
 
+type OrderStatusChange struct {
+	From      *OrderStatus `json:"from,omitempty"`
+	To        OrderStatus  `json:"to"`
+	ChangedAt time.Time    `json:"changedAt"`
+}
+
+type OrderStatusUpdate struct {
+	OrderID       string               `json:"orderId"`
+	Status        OrderStatus          `json:"status"`
+	StatusHistory []*OrderStatusChange `json:"statusHistory"`
+}
+
 type OrderedProduct struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       *Money `json:"price"`
+	Quantity    int    `json:"quantity"`
+}
+
+// Пагинация в стиле Relay: курсоры непрозрачны, следующая страница запрашивается
+// с after = pageInfo.endCursor. Поддерживается только движение вперёд (first/after),
+// поэтому hasPreviousPage всегда false.
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
 }
 
 type PaginationInput struct {
@@ -44,17 +149,226 @@ type PaginationInput struct {
 }
 
 type Product struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       *Money `json:"price"`
+	// Количество, доступное для заказа
+	Stock int `json:"stock"`
+}
+
+type ProductConnection struct {
+	Edges    []*ProductEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
+type ProductEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Product `json:"node"`
 }
 
 type ProductInput struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       *MoneyInput `json:"price"`
+	Stock       *int        `json:"stock,omitempty"`
+}
+
+// Незаполненные поля товара не меняются.
+type ProductUpdateInput struct {
+	Name        *string     `json:"name,omitempty"`
+	Description *string     `json:"description,omitempty"`
+	Price       *MoneyInput `json:"price,omitempty"`
+	Stock       *int        `json:"stock,omitempty"`
 }
 
 type Query struct {
 }
+
+type RegisterInput struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Не короче 8 символов
+	Password string `json:"password"`
+}
+
+// Подписки работают по websocket (протоколы graphql-ws и graphql-transport-ws) на том же /graphql.
+// Access токен передаётся в payload connection_init: {"Authorization": "Bearer <token>"}.
+// Если подписчик не успевает читать изменения, подписка завершается, и её нужно создать заново.
+type Subscription struct {
+}
+
+type AccountStatus string
+
+const (
+	AccountStatusActive      AccountStatus = "ACTIVE"
+	AccountStatusDeactivated AccountStatus = "DEACTIVATED"
+)
+
+var AllAccountStatus = []AccountStatus{
+	AccountStatusActive,
+	AccountStatusDeactivated,
+}
+
+func (e AccountStatus) IsValid() bool {
+	switch e {
+	case AccountStatusActive, AccountStatusDeactivated:
+		return true
+	}
+	return false
+}
+
+func (e AccountStatus) String() string {
+	return string(e)
+}
+
+func (e *AccountStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AccountStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AccountStatus", str)
+	}
+	return nil
+}
+
+func (e AccountStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AccountStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AccountStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "PENDING"
+	OrderStatusPaid      OrderStatus = "PAID"
+	OrderStatusShipped   OrderStatus = "SHIPPED"
+	OrderStatusDelivered OrderStatus = "DELIVERED"
+	OrderStatusCancelled OrderStatus = "CANCELLED"
+	OrderStatusRefunded  OrderStatus = "REFUNDED"
+)
+
+var AllOrderStatus = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusPaid,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCancelled,
+	OrderStatusRefunded,
+}
+
+func (e OrderStatus) IsValid() bool {
+	switch e {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled, OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func (e OrderStatus) String() string {
+	return string(e)
+}
+
+func (e *OrderStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderStatus", str)
+	}
+	return nil
+}
+
+func (e OrderStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OrderStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OrderStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type Role string
+
+const (
+	RoleCustomer     Role = "CUSTOMER"
+	RoleMerchandiser Role = "MERCHANDISER"
+	RoleAdmin        Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleCustomer,
+	RoleMerchandiser,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleCustomer, RoleMerchandiser, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Role) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Role) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -2,19 +2,72 @@ package main
 
 import (
 	"context"
-	"errors"
+	"go-microservice/account"
+	"go-microservice/catalog"
 	"go-microservice/order"
+	"go-microservice/platform"
+	"math"
 	"time"
 )
 
 var (
-	ErrInvalidParameter = errors.New("invalid parameter")
+	ErrInvalidParameter = platform.NewError(platform.InvalidArgument, "INVALID_PARAMETER", "invalid parameter")
 )
 
 type mutationResolver struct {
 	server *Server
 }
 
+func (r mutationResolver) Register(ctx context.Context, in RegisterInput) (*AuthPayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if _, err := r.server.accountClient.Register(ctx, in.Name, in.Email, in.Password); err != nil {
+		return nil, err
+	}
+	// Сразу входим, чтобы клиенту не пришлось отдельно вызывать login
+	session, err := r.server.accountClient.Login(ctx, in.Email, in.Password)
+	if err != nil {
+		return nil, err
+	}
+	return r.authPayload(session)
+}
+
+func (r mutationResolver) Login(ctx context.Context, email string, password string) (*AuthPayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	session, err := r.server.accountClient.Login(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	return r.authPayload(session)
+}
+
+func (r mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*AuthPayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	session, err := r.server.accountClient.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return r.authPayload(session)
+}
+
+func (r mutationResolver) authPayload(session *account.Session) (*AuthPayload, error) {
+	token, expiresAt, err := r.server.tokens.Issue(session.Account)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthPayload{
+		AccessToken:  token,
+		ExpiresAt:    expiresAt,
+		RefreshToken: session.RefreshToken,
+		Account:      toAccount(session.Account),
+	}, nil
+}
+
 func (r mutationResolver) CreateAccount(ctx context.Context, in AccountInput) (*Account, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
@@ -23,35 +76,148 @@ func (r mutationResolver) CreateAccount(ctx context.Context, in AccountInput) (*
 	if err != nil {
 		return nil, err
 	}
-	return &Account{
-		ID:   a.ID,
-		Name: a.Name,
-	}, nil
+	return toAccount(*a), nil
+}
+
+func (r mutationResolver) UpdateAccount(ctx context.Context, id string, in AccountInput) (*Account, error) {
+	if err := authorizeAccount(ctx, id); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	a, err := r.server.accountClient.UpdateAccount(ctx, id, in.Name)
+	if err != nil {
+		return nil, err
+	}
+	return toAccount(*a), nil
+}
+
+func (r mutationResolver) DeactivateAccount(ctx context.Context, id string) (*Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	a, err := r.server.accountClient.DeactivateAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toAccount(*a), nil
+}
+
+func (r mutationResolver) ReactivateAccount(ctx context.Context, id string) (*Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	a, err := r.server.accountClient.ReactivateAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toAccount(*a), nil
+}
+
+func (r mutationResolver) SetAccountRole(ctx context.Context, id string, role Role) (*Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	a, err := r.server.accountClient.SetAccountRole(ctx, id, fromRole(role))
+	if err != nil {
+		return nil, err
+	}
+	return toAccount(*a), nil
 }
 
 func (r mutationResolver) CreateProduct(ctx context.Context, in ProductInput) (*Product, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
-	p, err := r.server.catalogClient.PostProduct(ctx, in.Price, in.Name, in.Description)
+	price, err := fromMoneyInput(in.Price)
 	if err != nil {
 		return nil, err
 	}
-	return &Product{
-		ID:          p.ID,
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-	}, nil
+
+	var stock uint32
+	if in.Stock != nil {
+		if *in.Stock < 0 || *in.Stock > math.MaxUint32 {
+			return nil, ErrInvalidParameter
+		}
+		stock = uint32(*in.Stock)
+	}
+
+	p, err := r.server.catalogClient.PostProduct(ctx, price, in.Name, in.Description, stock)
+	if err != nil {
+		return nil, err
+	}
+	return toProduct(*p), nil
+}
+
+func (r mutationResolver) UpdateProduct(ctx context.Context, id string, in ProductUpdateInput) (*Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	update := catalog.Product{ID: id}
+	var paths []string
+	if in.Name != nil {
+		update.Name = *in.Name
+		paths = append(paths, catalog.FieldName)
+	}
+	if in.Description != nil {
+		update.Description = *in.Description
+		paths = append(paths, catalog.FieldDescription)
+	}
+	if in.Price != nil {
+		price, err := fromMoneyInput(in.Price)
+		if err != nil {
+			return nil, err
+		}
+		update.Price = price
+		paths = append(paths, catalog.FieldPrice)
+	}
+	if in.Stock != nil {
+		if *in.Stock < 0 || *in.Stock > math.MaxUint32 {
+			return nil, ErrInvalidParameter
+		}
+		update.Stock = uint32(*in.Stock)
+		paths = append(paths, catalog.FieldStock)
+	}
+	if len(paths) == 0 {
+		return nil, ErrInvalidParameter
+	}
+
+	p, err := r.server.catalogClient.UpdateProduct(ctx, update, paths)
+	if err != nil {
+		return nil, err
+	}
+	return toProduct(*p), nil
+}
+
+func (r mutationResolver) DeleteProduct(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if err := r.server.catalogClient.DeleteProduct(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func (r mutationResolver) CreateOrder(ctx context.Context, in OrderInput) (*Order, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	v, err := requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accountID := v.ID
+	// accountId оставлен для совместимости, но заказать на чужой аккаунт нельзя
+	if in.AccountID != nil && *in.AccountID != accountID {
+		return nil, forbidden(ctx, "cannot place an order for another account")
+	}
+
 	products := make([]order.OrderedProduct, 0, len(in.Products))
 	for _, p := range in.Products {
-		if p.Quantity <= 0 {
+		if p.Quantity <= 0 || p.Quantity > math.MaxUint32 {
 			return nil, ErrInvalidParameter
 		}
 		products = append(products, order.OrderedProduct{
@@ -60,26 +226,110 @@ func (r mutationResolver) CreateOrder(ctx context.Context, in OrderInput) (*Orde
 		})
 	}
 
-	o, err := r.server.orderClient.PostOrder(ctx, in.AccountID, products)
+	o, err := r.server.orderClient.PostOrder(ctx, accountID, products, optionalString(in.Currency), optionalString(in.IdempotencyKey))
 	if err != nil {
 		return nil, err
 	}
 
-	newProducts := make([]*OrderedProduct, 0, len(o.Products))
-	for _, p := range o.Products {
-		newProducts = append(newProducts, &OrderedProduct{
-			ID:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			Quantity:    int(p.Quantity),
-		})
+	return toOrder(*o), nil
+}
+
+func (r mutationResolver) UpdateOrderStatus(ctx context.Context, id string, status OrderStatus) (*OrderStatusUpdate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	history, err := r.server.orderClient.UpdateOrderStatus(ctx, id, fromOrderStatus(status))
+	if err != nil {
+		return nil, err
 	}
+	return toOrderStatusUpdate(id, history), nil
+}
 
-	return &Order{
-		ID:         o.ID,
-		CreatedAt:  o.CreatedAt,
-		TotalPrice: o.TotalPrice,
-		Products:   newProducts,
-	}, nil
+func (r mutationResolver) CancelOrder(ctx context.Context, id string) (*OrderStatusUpdate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	o, err := r.server.orderClient.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, o.AccountID); err != nil {
+		return nil, err
+	}
+
+	history, err := r.server.orderClient.CancelOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toOrderStatusUpdate(id, history), nil
+}
+
+func (r mutationResolver) AddToCart(ctx context.Context, productID string, quantity int, currency *string) (*Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	v, err := requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if quantity <= 0 || quantity > math.MaxUint32 {
+		return nil, ErrInvalidParameter
+	}
+
+	c, err := r.server.orderClient.AddToCart(ctx, v.ID, productID, uint32(quantity), optionalString(currency))
+	if err != nil {
+		return nil, err
+	}
+	return toCart(*c), nil
+}
+
+func (r mutationResolver) UpdateCartItem(ctx context.Context, productID string, quantity int, currency *string) (*Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	v, err := requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if quantity < 0 || quantity > math.MaxUint32 {
+		return nil, ErrInvalidParameter
+	}
+
+	c, err := r.server.orderClient.UpdateCartItem(ctx, v.ID, productID, uint32(quantity), optionalString(currency))
+	if err != nil {
+		return nil, err
+	}
+	return toCart(*c), nil
+}
+
+func (r mutationResolver) RemoveFromCart(ctx context.Context, productID string, currency *string) (*Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	v, err := requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := r.server.orderClient.RemoveFromCart(ctx, v.ID, productID, optionalString(currency))
+	if err != nil {
+		return nil, err
+	}
+	return toCart(*c), nil
+}
+
+func (r mutationResolver) Checkout(ctx context.Context, currency *string, idempotencyKey *string) (*Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	v, err := requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := r.server.orderClient.Checkout(ctx, v.ID, optionalString(currency), optionalString(idempotencyKey))
+	if err != nil {
+		return nil, err
+	}
+	return toOrder(*o), nil
 }
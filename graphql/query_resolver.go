@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"log"
+	"go-microservice/catalog"
 	"time"
 )
 
@@ -19,10 +19,7 @@ func (q queryResolver) Accounts(ctx context.Context, pagination *PaginationInput
 		if err != nil {
 			return nil, err
 		}
-		return []*Account{{
-			ID:   a.ID,
-			Name: a.Name,
-		}}, nil
+		return []*Account{toAccount(*a)}, nil
 	}
 	var skip, take uint64
 	if pagination != nil {
@@ -37,27 +34,31 @@ func (q queryResolver) Accounts(ctx context.Context, pagination *PaginationInput
 
 	accs := make([]*Account, 0, len(accounts))
 	for _, a := range accounts {
-		accs = append(accs, &Account{ID: a.ID, Name: a.Name})
+		accs = append(accs, toAccount(a))
 	}
 	return accs, nil
 
 }
-func (q queryResolver) Products(ctx context.Context, pagination *PaginationInput, query *string, id *string) ([]*Product, error) {
+func (q queryResolver) Products(ctx context.Context, pagination *PaginationInput, query *string, id *string, currency *string) ([]*Product, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	var priceCurrency string
+	if currency != nil {
+		priceCurrency = *currency
+	}
+
 	if id != nil {
-		p, err := q.server.catalogClient.GetProduct(ctx, *id)
+		// Несколько products(id: ...) в одном запросе загружаются одним вызовом каталога
+		p, err := loadersFor(ctx, q.server).ProductByID.Load(ctx, productKey{ID: *id, Currency: priceCurrency})
 		if err != nil {
-			log.Printf("Product query error: %v", err)
+			q.server.logger.ErrorContext(ctx, "Product query failed", "error", err)
 			return nil, err
 		}
-		return []*Product{{
-			ID:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-		}}, nil
+		if p == nil {
+			return nil, catalog.ErrProductNotFound
+		}
+		return []*Product{toProduct(*p)}, nil
 	}
 
 	var skip, take uint64
@@ -71,21 +72,115 @@ func (q queryResolver) Products(ctx context.Context, pagination *PaginationInput
 		searchQuery = *query
 	}
 
-	products, err := q.server.catalogClient.GetProducts(ctx, []string{}, searchQuery, skip, take)
+	products, err := q.server.catalogClient.GetProducts(ctx, []string{}, searchQuery, skip, take, priceCurrency)
 	if err != nil {
-		log.Printf("Product query error: %v", err)
+		q.server.logger.ErrorContext(ctx, "Product query failed", "error", err)
 		return nil, err
 	}
 
 	pr := make([]*Product, 0, len(products))
 	for _, p := range products {
-		pr = append(pr, &Product{
-			ID:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-		})
+		pr = append(pr, toProduct(p))
 	}
 	return pr, nil
 
 }
+
+func (q queryResolver) AccountsConnection(ctx context.Context, first *int, after *string) (*AccountConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	cursor, limit, err := pageArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+	page, err := q.server.accountClient.GetAccountsPage(ctx, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*AccountEdge, len(page.Edges))
+	cursors := make([]string, len(page.Edges))
+	for i, e := range page.Edges {
+		edges[i] = &AccountEdge{Cursor: e.Cursor, Node: toAccount(e.Account)}
+		cursors[i] = e.Cursor
+	}
+	return &AccountConnection{Edges: edges, PageInfo: toPageInfo(cursors, page.HasNextPage)}, nil
+}
+
+func (q queryResolver) ProductsConnection(ctx context.Context, first *int, after *string, query *string, currency *string) (*ProductConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	cursor, limit, err := pageArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+	var searchQuery, priceCurrency string
+	if query != nil {
+		searchQuery = *query
+	}
+	if currency != nil {
+		priceCurrency = *currency
+	}
+
+	page, err := q.server.catalogClient.GetProductsPage(ctx, searchQuery, cursor, limit, priceCurrency)
+	if err != nil {
+		q.server.logger.ErrorContext(ctx, "Product query failed", "error", err)
+		return nil, err
+	}
+
+	edges := make([]*ProductEdge, len(page.Edges))
+	cursors := make([]string, len(page.Edges))
+	for i, e := range page.Edges {
+		edges[i] = &ProductEdge{Cursor: e.Cursor, Node: toProduct(e.Product)}
+		cursors[i] = e.Cursor
+	}
+	return &ProductConnection{Edges: edges, PageInfo: toPageInfo(cursors, page.HasNextPage)}, nil
+}
+
+func (q queryResolver) Order(ctx context.Context, id string) (*Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	o, err := q.server.orderClient.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, o.AccountID); err != nil {
+		return nil, err
+	}
+	return toOrder(*o), nil
+}
+
+func (q queryResolver) Me(ctx context.Context) (*Account, error) {
+	v := viewerFrom(ctx)
+	if v == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	a, err := q.server.accountClient.GetAccount(ctx, v.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toAccount(*a), nil
+}
+
+func (q queryResolver) Cart(ctx context.Context, currency *string) (*Cart, error) {
+	v, err := requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	c, err := q.server.orderClient.GetCart(ctx, v.ID, optionalString(currency))
+	if err != nil {
+		return nil, err
+	}
+	return toCart(*c), nil
+}
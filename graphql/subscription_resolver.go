@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+type subscriptionResolver struct {
+	server *Server
+}
+
+func (r subscriptionResolver) OrderUpdated(ctx context.Context, orderID string) (<-chan *Order, error) {
+	// Права на заказ проверяет сервис заказов: аккаунт заказа известен только ему
+	return r.watchOrders(ctx, orderID, "")
+}
+
+func (r subscriptionResolver) OrdersForAccount(ctx context.Context, accountID string) (<-chan *Order, error) {
+	if err := authorizeAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.watchOrders(ctx, "", accountID)
+}
+
+// watchOrders передаёт заказы из WatchOrders сервиса заказов в подписку. Канал закрывается,
+// когда поток завершён: клиент отписался, сервис останавливается или подписчик отстал.
+// Поток живёт столько же, сколько подписка, поэтому таймаута у вызова нет.
+func (r subscriptionResolver) watchOrders(ctx context.Context, orderID, accountID string) (<-chan *Order, error) {
+	stream, err := r.server.orderClient.WatchOrders(ctx, orderID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make(chan *Order)
+	go func() {
+		defer close(orders)
+		for {
+			o, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() == nil && !errors.Is(err, io.EOF) {
+					r.server.logger.WarnContext(ctx, "Order subscription ended", "order_id", orderID, "account_id", accountID, "error", err)
+				}
+				return
+			}
+			select {
+			case orders <- toOrder(*o):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return orders, nil
+}
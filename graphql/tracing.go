@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"go-microservice/platform"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// withTracing начинает span HTTP запроса, продолжая трассировку клиента из заголовка traceparent.
+// Загрузчики создаются с контекстом запроса, поэтому их вызовы сервисов тоже попадают в трассировку.
+func withTracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := platform.Tracer().Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("request_id", platform.RequestID(ctx))),
+		)
+		defer span.End()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// tracingExtension - расширение gqlgen: span на операцию и на каждое поле с резолвером.
+// Поля, которые просто читаются из структуры, span не получают.
+type tracingExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = tracingExtension{}
+
+func (tracingExtension) ExtensionName() string {
+	return "OpenTelemetry"
+}
+
+func (tracingExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (tracingExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	opCtx := graphql.GetOperationContext(ctx)
+	operation := "unknown"
+	if opCtx.Operation != nil {
+		operation = string(opCtx.Operation.Operation)
+	}
+
+	name := "graphql " + operation
+	if opCtx.OperationName != "" {
+		name += " " + opCtx.OperationName
+	}
+	ctx, span := platform.Tracer().Start(ctx, name,
+		trace.WithAttributes(
+			attribute.String("graphql.operation.type", operation),
+			attribute.String("graphql.operation.name", opCtx.OperationName),
+		),
+	)
+	res := next(ctx)
+	var err error
+	if res != nil && len(res.Errors) > 0 {
+		err = res.Errors
+	}
+	platform.EndSpan(span, err)
+	return res
+}
+
+func (tracingExtension) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := platform.Tracer().Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(attribute.String("graphql.field.path", fc.Path().String())),
+	)
+	res, err := next(ctx)
+	platform.EndSpan(span, err)
+	return res, err
+}
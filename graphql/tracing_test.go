@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingExtension(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ext := tracingExtension{}
+	opCtx := &graphql.OperationContext{
+		OperationName: "CreateOrder",
+		Operation:     &ast.OperationDefinition{Operation: ast.Mutation},
+	}
+	opCtx.Stats.OperationStart = time.Now()
+	ctx := graphql.WithOperationContext(context.Background(), opCtx)
+
+	ext.InterceptResponse(ctx, func(ctx context.Context) *graphql.Response {
+		ext.InterceptField(fieldContextFrom(ctx, "Mutation", "createOrder", true), func(ctx context.Context) (any, error) {
+			return nil, nil
+		})
+		ext.InterceptField(fieldContextFrom(ctx, "Order", "id", false), func(ctx context.Context) (any, error) {
+			return "id", nil
+		})
+		return &graphql.Response{}
+	})
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want operation and createOrder", len(spans))
+	}
+	field, operation := spans[0], spans[1]
+	if operation.Name() != "graphql mutation CreateOrder" || operation.Status().Code == codes.Error {
+		t.Errorf("operation span = %s with status %v, want successful graphql mutation CreateOrder", operation.Name(), operation.Status())
+	}
+	if field.Name() != "Mutation.createOrder" || field.Parent().SpanID() != operation.SpanContext().SpanID() {
+		t.Errorf("field span = %s, want Mutation.createOrder as a child of the operation", field.Name())
+	}
+}
+
+func fieldContextFrom(ctx context.Context, object, field string, isResolver bool) context.Context {
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object:     object,
+		Field:      graphql.CollectedField{Field: &ast.Field{Name: field}},
+		IsResolver: isResolver,
+	})
+}
@@ -0,0 +1,185 @@
+package money
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-microservice/money/pb"
+	"go-microservice/platform"
+	"math/big"
+	"os"
+	"strings"
+)
+
+var ErrRateNotFound = platform.NewError(platform.InvalidArgument, "EXCHANGE_RATE_NOT_FOUND", "exchange rate not found")
+
+// rateScale - количество знаков после запятой у вычисленных (кросс- и обратных) курсов
+const rateScale = 10
+
+// Rate - курс обмена: 1 единица From стоит Value единиц To.
+// Value хранится десятичной строкой, чтобы снимок курса сохранялся без потерь.
+type Rate struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+}
+
+type ExchangeRateProvider interface {
+	Rate(ctx context.Context, from, to string) (Rate, error)
+}
+
+// Convert переводит сумму в валюту rate.To с округлением до минимальной единицы (половина - от нуля)
+func Convert(m Money, rate Rate) (Money, error) {
+	if m.Currency != rate.From {
+		return Money{}, fmt.Errorf("%w: cannot convert %s with %s->%s rate", ErrCurrencyMismatch, m.Currency, rate.From, rate.To)
+	}
+	if m.Currency == rate.To {
+		return m, nil
+	}
+
+	r, ok := new(big.Rat).SetString(rate.Value)
+	if !ok || r.Sign() <= 0 {
+		return Money{}, fmt.Errorf("invalid exchange rate %q", rate.Value)
+	}
+
+	// amount * rate * 10^(exp(to) - exp(from))
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), r)
+	shift := Exponent(rate.To) - Exponent(rate.From)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		v.Mul(v, scale)
+	} else {
+		v.Quo(v, scale)
+	}
+
+	return Money{Amount: roundRat(v), Currency: rate.To}, nil
+}
+
+// StaticRateProvider отдаёт курсы из фиксированной таблицы,
+// заданной относительно базовой валюты: 1 Base = Rates[X] единиц X.
+// Подходит для локального запуска и тестов.
+type StaticRateProvider struct {
+	base  string
+	rates map[string]*big.Rat
+}
+
+type ratesFile struct {
+	Base  string            `json:"base"`
+	Rates map[string]string `json:"rates"`
+}
+
+func NewStaticRateProvider(base string, rates map[string]string) (*StaticRateProvider, error) {
+	base = strings.ToUpper(base)
+	if err := ValidateCurrency(base); err != nil {
+		return nil, err
+	}
+
+	p := &StaticRateProvider{
+		base:  base,
+		rates: map[string]*big.Rat{base: big.NewRat(1, 1)},
+	}
+	for currency, value := range rates {
+		currency = strings.ToUpper(currency)
+		if err := ValidateCurrency(currency); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, currency)
+		}
+		r, ok := new(big.Rat).SetString(value)
+		if !ok || r.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for %s: %q", currency, value)
+		}
+		p.rates[currency] = r
+	}
+	return p, nil
+}
+
+// LoadRatesFile читает таблицу курсов из JSON-файла вида
+// {"base": "USD", "rates": {"EUR": "0.92", "RUB": "91.5"}}
+func LoadRatesFile(path string) (*StaticRateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f ratesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse rates file %s: %w", path, err)
+	}
+	return NewStaticRateProvider(f.Base, f.Rates)
+}
+
+// LoadRates читает таблицу курсов из файла path и проверяет, что она задана относительно baseCurrency.
+// Если path пустой, доступна только сама базовая валюта.
+func LoadRates(path, baseCurrency string) (*StaticRateProvider, error) {
+	if path == "" {
+		return NewStaticRateProvider(baseCurrency, nil)
+	}
+	p, err := LoadRatesFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if p.Base() != strings.ToUpper(baseCurrency) {
+		return nil, fmt.Errorf("exchange rates file %s has base %s, expected %s", path, p.Base(), baseCurrency)
+	}
+	return p, nil
+}
+
+func (p *StaticRateProvider) Base() string {
+	return p.base
+}
+
+// Rate implements ExchangeRateProvider.
+func (p *StaticRateProvider) Rate(ctx context.Context, from, to string) (Rate, error) {
+	if from == to {
+		return Rate{From: from, To: to, Value: "1"}, nil
+	}
+
+	fromRate, ok := p.rates[from]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %s->%s", ErrRateNotFound, from, to)
+	}
+	toRate, ok := p.rates[to]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %s->%s", ErrRateNotFound, from, to)
+	}
+
+	value := new(big.Rat).Quo(toRate, fromRate)
+	return Rate{From: from, To: to, Value: formatRat(value)}, nil
+}
+
+func RateToProto(r Rate) *pb.ExchangeRate {
+	return &pb.ExchangeRate{From: r.From, To: r.To, Value: r.Value}
+}
+
+func RateFromProto(r *pb.ExchangeRate) Rate {
+	if r == nil {
+		return Rate{}
+	}
+	return Rate{From: r.From, To: r.To, Value: r.Value}
+}
+
+// formatRat записывает число без лишних нулей, не более rateScale знаков после запятой
+func formatRat(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	s := strings.TrimRight(r.FloatString(rateScale), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func roundRat(r *big.Rat) int64 {
+	num := new(big.Int).Abs(r.Num())
+	q, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,87 @@
+package money
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		m    Money
+		rate Rate
+		want Money
+	}{
+		{New(1999, "USD"), Rate{From: "USD", To: "EUR", Value: "0.92"}, New(1839, "EUR")},
+		{New(1999, "USD"), Rate{From: "USD", To: "RUB", Value: "91.5"}, New(182909, "RUB")},
+		{New(1000, "USD"), Rate{From: "USD", To: "JPY", Value: "150.25"}, New(1503, "JPY")},
+		{New(1500, "JPY"), Rate{From: "JPY", To: "USD", Value: "0.0066556"}, New(998, "USD")},
+		{New(5, "USD"), Rate{From: "USD", To: "EUR", Value: "0.5"}, New(3, "EUR")},
+		{New(1999, "USD"), Rate{From: "USD", To: "USD", Value: "1"}, New(1999, "USD")},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.m, tt.rate)
+		if err != nil {
+			t.Fatalf("Convert(%s, %+v): %v", tt.m, tt.rate, err)
+		}
+		if got != tt.want {
+			t.Errorf("Convert(%s, %+v) = %s, want %s", tt.m, tt.rate, got, tt.want)
+		}
+	}
+
+	if _, err := Convert(New(100, "EUR"), Rate{From: "USD", To: "RUB", Value: "90"}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Convert with wrong source currency error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestStaticRateProvider(t *testing.T) {
+	p, err := NewStaticRateProvider("USD", map[string]string{"EUR": "0.8", "RUB": "90"})
+	if err != nil {
+		t.Fatalf("NewStaticRateProvider: %v", err)
+	}
+
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"USD", "USD", "1"},
+		{"USD", "EUR", "0.8"},
+		{"EUR", "USD", "1.25"},
+		{"EUR", "RUB", "112.5"},
+		{"RUB", "USD", "0.0111111111"},
+	}
+	for _, tt := range tests {
+		r, err := p.Rate(context.Background(), tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("Rate(%s, %s): %v", tt.from, tt.to, err)
+		}
+		if r.Value != tt.want || r.From != tt.from || r.To != tt.to {
+			t.Errorf("Rate(%s, %s) = %+v, want value %s", tt.from, tt.to, r, tt.want)
+		}
+	}
+
+	if _, err := p.Rate(context.Background(), "USD", "GBP"); !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("Rate(USD, GBP) error = %v, want %v", err, ErrRateNotFound)
+	}
+}
+
+func TestLoadRatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`{"base": "eur", "rates": {"usd": "1.08"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadRatesFile(path)
+	if err != nil {
+		t.Fatalf("LoadRatesFile: %v", err)
+	}
+	if p.Base() != "EUR" {
+		t.Errorf("Base = %s, want EUR", p.Base())
+	}
+	r, err := p.Rate(context.Background(), "EUR", "USD")
+	if err != nil || r.Value != "1.08" {
+		t.Errorf("Rate(EUR, USD) = %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package money описывает денежные суммы без потери точности:
+// сумма хранится целым числом минимальных единиц валюты вместе с кодом ISO 4217.
+package money
+
+import (
+	"fmt"
+	"go-microservice/money/pb"
+	"go-microservice/platform"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidAmount    = platform.NewError(platform.InvalidArgument, "INVALID_AMOUNT", "invalid money amount")
+	ErrInvalidCurrency  = platform.NewError(platform.InvalidArgument, "INVALID_CURRENCY", "invalid currency code")
+	ErrCurrencyMismatch = platform.NewError(platform.InvalidArgument, "CURRENCY_MISMATCH", "currency mismatch")
+)
+
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// exponents - число знаков после запятой для валют, у которых оно отличается от 2
+var exponents = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"IQD": 3,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"UGX": 0,
+	"VND": 0,
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Exponent возвращает количество минимальных единиц валюты в виде степени десяти
+func Exponent(currency string) int {
+	if e, ok := exponents[currency]; ok {
+		return e
+	}
+	return 2
+}
+
+func ValidateCurrency(currency string) error {
+	if len(currency) != 3 {
+		return ErrInvalidCurrency
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return ErrInvalidCurrency
+		}
+	}
+	return nil
+}
+
+// Parse разбирает десятичную запись суммы, например "19.99", в минимальные единицы валюты.
+// Знаков после точки не может быть больше, чем допускает валюта.
+func Parse(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if err := ValidateCurrency(currency); err != nil {
+		return Money{}, err
+	}
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	exp := Exponent(currency)
+	if whole == "" || len(frac) > exp || !digits(whole) || !digits(frac) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	if negative {
+		units = -units
+	}
+	return Money{Amount: units, Currency: currency}, nil
+}
+
+// FromFloat округляет значение с плавающей точкой до минимальных единиц валюты.
+// Нужна только для чтения данных, сохранённых до перехода на Money.
+func FromFloat(amount float64, currency string) Money {
+	scale := math.Pow10(Exponent(currency))
+	return Money{Amount: int64(math.Round(amount * scale)), Currency: currency}
+}
+
+func (m Money) Validate() error {
+	return ValidateCurrency(m.Currency)
+}
+
+// Decimal возвращает сумму в десятичной записи, например "19.99"
+func (m Money) Decimal() string {
+	exp := Exponent(m.Currency)
+	units := m.Amount
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	s := strconv.FormatInt(units, 10)
+	if exp == 0 {
+		return sign + s
+	}
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// Sum складывает суммы в одной валюте. Для пустого списка возвращается ноль в валюте currency.
+func Sum(currency string, amounts ...Money) (Money, error) {
+	total := Money{Currency: currency}
+	for _, a := range amounts {
+		var err error
+		if total, err = total.Add(a); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+func ToProto(m Money) *pb.Money {
+	return &pb.Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+func FromProto(m *pb.Money) Money {
+	if m == nil {
+		return Money{}
+	}
+	return Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{amount: "19.99", currency: "USD", want: New(1999, "USD")},
+		{amount: "19.9", currency: "usd", want: New(1990, "USD")},
+		{amount: "19", currency: "EUR", want: New(1900, "EUR")},
+		{amount: "0.01", currency: "RUB", want: New(1, "RUB")},
+		{amount: "-5.50", currency: "USD", want: New(-550, "USD")},
+		{amount: "1500", currency: "JPY", want: New(1500, "JPY")},
+		{amount: "1.234", currency: "KWD", want: New(1234, "KWD")},
+		{amount: "19.999", currency: "USD", wantErr: ErrInvalidAmount},
+		{amount: "15.5", currency: "JPY", wantErr: ErrInvalidAmount},
+		{amount: "1e3", currency: "USD", wantErr: ErrInvalidAmount},
+		{amount: "", currency: "USD", wantErr: ErrInvalidAmount},
+		{amount: ".5", currency: "USD", wantErr: ErrInvalidAmount},
+		{amount: "1.00", currency: "US", wantErr: ErrInvalidCurrency},
+		{amount: "1.00", currency: "U$D", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.amount, tt.currency)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Parse(%q, %q) error = %v, want %v", tt.amount, tt.currency, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("Parse(%q, %q) = %+v, want %+v", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{New(1999, "USD"), "19.99"},
+		{New(5, "USD"), "0.05"},
+		{New(0, "EUR"), "0.00"},
+		{New(-550, "RUB"), "-5.50"},
+		{New(1500, "JPY"), "1500"},
+		{New(1234, "KWD"), "1.234"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Decimal(); got != tt.want {
+			t.Errorf("%+v.Decimal() = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestSumIsExact(t *testing.T) {
+	// 0.1 + 0.2 в float64 даёт 0.30000000000000004
+	total, err := Sum("USD", New(10, "USD"), New(20, "USD"))
+	if err != nil {
+		t.Fatalf("Sum: %v", err)
+	}
+	if total.Decimal() != "0.30" {
+		t.Errorf("Sum = %s, want 0.30", total.Decimal())
+	}
+
+	if _, err := Sum("USD", New(10, "USD"), New(20, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sum with mixed currencies error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	if got := FromFloat(19.99, "USD"); got != New(1999, "USD") {
+		t.Errorf("FromFloat(19.99) = %+v", got)
+	}
+	if got := FromFloat(0.125, "USD"); got != New(13, "USD") {
+		t.Errorf("FromFloat(0.125) = %+v", got)
+	}
+}
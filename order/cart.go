@@ -0,0 +1,137 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"go-microservice/money"
+	"go-microservice/platform"
+	"time"
+)
+
+var (
+	ErrCartEmpty        = platform.NewError(platform.FailedPrecondition, "CART_EMPTY", "cart is empty")
+	ErrCartFull         = platform.NewError(platform.FailedPrecondition, "CART_FULL", "cart is full")
+	ErrCartItemNotFound = platform.NewError(platform.NotFound, "CART_ITEM_NOT_FOUND", "product is not in the cart")
+	ErrInvalidQuantity  = platform.NewError(platform.InvalidArgument, "INVALID_QUANTITY", "invalid quantity")
+)
+
+const (
+	// maxCartItems ограничивает число разных товаров в корзине
+	maxCartItems = 100
+	// maxCartQuantity - наибольшее количество одного товара в корзине
+	maxCartQuantity = 1000
+)
+
+// Cart - корзина аккаунта. Хранятся только товары и их количество:
+// название и цена берутся из каталога при каждом чтении, поэтому всегда актуальны.
+type Cart struct {
+	AccountID string
+	Items     []CartItem
+}
+
+type CartItem struct {
+	ProductID string
+	Quantity  uint32
+	AddedAt   time.Time
+}
+
+// PricedCart - корзина с названиями и ценами каталога на момент запроса, её возвращает Client
+type PricedCart struct {
+	AccountID string
+	Items     []PricedCartItem
+	// TotalPrice - сумма доступных товаров; пустая, если таких нет
+	TotalPrice money.Money
+}
+
+type PricedCartItem struct {
+	ProductID   string
+	Name        string
+	Description string
+	Quantity    uint32
+	Price       money.Money
+	Subtotal    money.Money
+	// Available - товар есть в каталоге; удалённый товар не входит в сумму, и оформить корзину с ним нельзя
+	Available bool
+}
+
+// item возвращает товар корзины по ID или nil
+func (c *Cart) item(productID string) *CartItem {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			return &c.Items[i]
+		}
+	}
+	return nil
+}
+
+// GetCart возвращает корзину аккаунта; у аккаунта, который ничего не добавлял, она пустая
+func (s orderService) GetCart(ctx context.Context, accountID string) (*Cart, error) {
+	return s.repository.GetCart(ctx, accountID)
+}
+
+// AddToCart добавляет quantity штук товара к уже лежащим в корзине
+func (s orderService) AddToCart(ctx context.Context, accountID, productID string, quantity uint32) (*Cart, error) {
+	if quantity == 0 {
+		return nil, fmt.Errorf("%w: must be positive", ErrInvalidQuantity)
+	}
+	cart, err := s.repository.GetCart(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := CartItem{ProductID: productID, Quantity: quantity, AddedAt: time.Now()}
+	if existing := cart.item(productID); existing != nil {
+		// Проверка до сложения: сумма uint32 может переполниться и пройти проверку в putCartItem
+		if quantity > maxCartQuantity-min(existing.Quantity, maxCartQuantity) {
+			return nil, fmt.Errorf("%w: at most %d per product", ErrInvalidQuantity, maxCartQuantity)
+		}
+		item.Quantity += existing.Quantity
+		item.AddedAt = existing.AddedAt
+	} else if len(cart.Items) >= maxCartItems {
+		return nil, fmt.Errorf("%w: at most %d products", ErrCartFull, maxCartItems)
+	}
+	return s.putCartItem(ctx, accountID, item)
+}
+
+// UpdateCartItem заменяет количество товара, который уже есть в корзине; 0 удаляет товар
+func (s orderService) UpdateCartItem(ctx context.Context, accountID, productID string, quantity uint32) (*Cart, error) {
+	if quantity == 0 {
+		return s.RemoveFromCart(ctx, accountID, productID)
+	}
+	cart, err := s.repository.GetCart(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	existing := cart.item(productID)
+	if existing == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCartItemNotFound, productID)
+	}
+	return s.putCartItem(ctx, accountID, CartItem{ProductID: productID, Quantity: quantity, AddedAt: existing.AddedAt})
+}
+
+func (s orderService) putCartItem(ctx context.Context, accountID string, item CartItem) (*Cart, error) {
+	if item.Quantity > maxCartQuantity {
+		return nil, fmt.Errorf("%w: at most %d per product", ErrInvalidQuantity, maxCartQuantity)
+	}
+	if err := s.repository.PutCartItem(ctx, accountID, item); err != nil {
+		return nil, err
+	}
+	s.logger.DebugContext(ctx, "Cart item updated", "account_id", accountID, "product_id", item.ProductID, "quantity", item.Quantity)
+	return s.repository.GetCart(ctx, accountID)
+}
+
+// RemoveFromCart удаляет товар из корзины
+func (s orderService) RemoveFromCart(ctx context.Context, accountID, productID string) (*Cart, error) {
+	if err := s.repository.DeleteCartItem(ctx, accountID, productID); err != nil {
+		return nil, err
+	}
+	s.logger.DebugContext(ctx, "Cart item removed", "account_id", accountID, "product_id", productID)
+	return s.repository.GetCart(ctx, accountID)
+}
+
+// PriceProducts пересчитывает цены товаров из валюты каталога в currency так же, как PostOrder,
+// и возвращает товары с новыми ценами и их сумму. Пустая currency оставляет валюту каталога.
+func (s orderService) PriceProducts(ctx context.Context, products []OrderedProduct, currency string) ([]OrderedProduct, money.Money, error) {
+	converted, total, _, err := s.convertPrices(ctx, products, currency)
+	return converted, total, err
+}
@@ -3,11 +3,12 @@ package order
 import (
 	"context"
 	"fmt"
+	"go-microservice/money"
 	"go-microservice/order/pb"
+	"go-microservice/platform"
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type Client struct {
@@ -15,8 +16,9 @@ type Client struct {
 	client pb.OrderServiceClient
 }
 
-func NewClient(url string) (*Client, error) {
-	conn, err := grpc.NewClient(url, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewClient подключается к сервису по url; без сертификатов в tlsConfig соединение не шифруется
+func NewClient(url string, tlsConfig platform.TLSConfig) (*Client, error) {
+	conn, err := platform.Dial(url, tlsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +30,14 @@ func (c *Client) Close() {
 	c.conn.Close()
 }
 
-func (c *Client) PostOrder(ctx context.Context, accountID string, products []OrderedProduct) (*Order, error) {
+// Health проверяет сервис и его зависимости по grpc.health.v1
+func (c *Client) Health(ctx context.Context) error {
+	return platform.CheckConn(ctx, c.conn)
+}
+
+// PostOrder оформляет заказ в валюте currency; пустая currency означает базовую валюту каталога.
+// Повтор с тем же непустым idempotencyKey возвращает уже оформленный заказ.
+func (c *Client) PostOrder(ctx context.Context, accountID string, products []OrderedProduct, currency, idempotencyKey string) (*Order, error) {
 	protoProducts := []*pb.PostOrderRequest_OrderProduct{}
 	for _, p := range products {
 		protoProducts = append(protoProducts, &pb.PostOrderRequest_OrderProduct{
@@ -37,22 +46,15 @@ func (c *Client) PostOrder(ctx context.Context, accountID string, products []Ord
 		})
 	}
 	r, err := c.client.PostOrder(ctx, &pb.PostOrderRequest{
-		AccountId: accountID,
-		Products:  protoProducts,
+		AccountId:      accountID,
+		Products:       protoProducts,
+		Currency:       currency,
+		IdempotencyKey: idempotencyKey,
 	})
 	if err != nil {
 		return nil, err
 	}
-	newOrder := r.Order
-	newOrderCreatedAt := time.Time{}
-	newOrderCreatedAt.UnmarshalBinary(newOrder.CreatedAt)
-	return &Order{
-		ID:         newOrder.Id,
-		CreatedAt:  newOrderCreatedAt,
-		TotalPrice: newOrder.TotalPrice,
-		AccountID:  newOrder.AccountId,
-		Products:   products,
-	}, nil
+	return fromProtoOrder(r.Order)
 }
 
 func (c *Client) GetOrdersForAccount(ctx context.Context, accountID string) ([]Order, error) {
@@ -63,37 +65,238 @@ func (c *Client) GetOrdersForAccount(ctx context.Context, accountID string) ([]O
 		return nil, err
 	}
 
-	pbOrders := res.Orders
-	orders := make([]Order, 0, len(pbOrders))
-	for _, o := range pbOrders {
-		pbProducts := o.Products
-		products := make([]OrderedProduct, 0, len(pbProducts))
-
-		for _, p := range pbProducts {
-			products = append(products, OrderedProduct{
-				ID:          p.Id,
-				Name:        p.Name,
-				Description: p.Description,
-				Price:       p.Price,
-				Quantity:    p.Quantity,
-			})
+	orders := make([]Order, 0, len(res.Orders))
+	for _, o := range res.Orders {
+		order, err := fromProtoOrder(o)
+		if err != nil {
+			return nil, err
 		}
+		orders = append(orders, *order)
+	}
+	return orders, nil
+}
+
+// GetOrdersForAccounts возвращает заказы нескольких аккаунтов, сгруппированные по ID аккаунта.
+// Аккаунты без заказов в результат не попадают.
+func (c *Client) GetOrdersForAccounts(ctx context.Context, accountIDs []string) (map[string][]Order, error) {
+	res, err := c.client.GetOrdersForAccounts(ctx, &pb.GetOrdersForAccountsRequest{
+		AccountIds: accountIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		var orderCreatedAt time.Time
-		if err := orderCreatedAt.UnmarshalBinary(o.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal createdAt: %w", err)
+	orders := make(map[string][]Order, len(accountIDs))
+	for _, o := range res.Orders {
+		order, err := fromProtoOrder(o)
+		if err != nil {
+			return nil, err
 		}
+		orders[order.AccountID] = append(orders[order.AccountID], *order)
+	}
+	return orders, nil
+}
 
-		order := Order{
-			ID:         o.Id,
-			AccountID:  o.AccountId,
-			TotalPrice: o.TotalPrice,
-			CreatedAt:  orderCreatedAt,
-			Products:   products,
+// GetOrdersPageForAccount возвращает страницу заказов аккаунта после курсора after
+func (c *Client) GetOrdersPageForAccount(ctx context.Context, accountID, after string, first uint64) (*OrderPage, error) {
+	res, err := c.client.GetOrdersPageForAccount(ctx, &pb.GetOrdersPageForAccountRequest{
+		AccountId: accountID,
+		After:     after,
+		First:     first,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &OrderPage{
+		Edges:       make([]OrderEdge, len(res.Edges)),
+		HasNextPage: res.HasNextPage,
+	}
+	for i, e := range res.Edges {
+		order, err := fromProtoOrder(e.Order)
+		if err != nil {
+			return nil, err
 		}
+		page.Edges[i] = OrderEdge{Order: *order, Cursor: e.Cursor}
+	}
+	return page, nil
+}
 
-		orders = append(orders, order)
+func (c *Client) GetOrder(ctx context.Context, id string) (*Order, error) {
+	res, err := c.client.GetOrder(ctx, &pb.GetOrderRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoOrder(res.Order)
+}
 
+// GetCart возвращает корзину аккаунта с ценами в currency; пустая currency - базовая валюта каталога
+func (c *Client) GetCart(ctx context.Context, accountID, currency string) (*PricedCart, error) {
+	res, err := c.client.GetCart(ctx, &pb.GetCartRequest{AccountId: accountID, Currency: currency})
+	if err != nil {
+		return nil, err
 	}
-	return orders, nil
+	return fromProtoCart(res.Cart), nil
+}
+
+// AddToCart добавляет quantity штук товара в корзину и возвращает её с ценами в currency
+func (c *Client) AddToCart(ctx context.Context, accountID, productID string, quantity uint32, currency string) (*PricedCart, error) {
+	res, err := c.client.AddToCart(ctx, &pb.AddToCartRequest{
+		AccountId: accountID,
+		ProductId: productID,
+		Quantity:  quantity,
+		Currency:  currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoCart(res.Cart), nil
+}
+
+// UpdateCartItem заменяет количество товара в корзине, 0 удаляет товар
+func (c *Client) UpdateCartItem(ctx context.Context, accountID, productID string, quantity uint32, currency string) (*PricedCart, error) {
+	res, err := c.client.UpdateCartItem(ctx, &pb.UpdateCartItemRequest{
+		AccountId: accountID,
+		ProductId: productID,
+		Quantity:  quantity,
+		Currency:  currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoCart(res.Cart), nil
+}
+
+func (c *Client) RemoveFromCart(ctx context.Context, accountID, productID, currency string) (*PricedCart, error) {
+	res, err := c.client.RemoveFromCart(ctx, &pb.RemoveFromCartRequest{
+		AccountId: accountID,
+		ProductId: productID,
+		Currency:  currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoCart(res.Cart), nil
+}
+
+// Checkout оформляет заказ из корзины в валюте currency и убирает оформленные товары из корзины.
+// Повтор с тем же непустым idempotencyKey возвращает уже оформленный заказ.
+func (c *Client) Checkout(ctx context.Context, accountID, currency, idempotencyKey string) (*Order, error) {
+	res, err := c.client.Checkout(ctx, &pb.CheckoutRequest{
+		AccountId:      accountID,
+		Currency:       currency,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoOrder(res.Order)
+}
+
+func fromProtoCart(c *pb.Cart) *PricedCart {
+	cart := &PricedCart{
+		AccountID:  c.AccountId,
+		Items:      make([]PricedCartItem, 0, len(c.Items)),
+		TotalPrice: money.FromProto(c.TotalPrice),
+	}
+	for _, item := range c.Items {
+		cart.Items = append(cart.Items, PricedCartItem{
+			ProductID:   item.ProductId,
+			Name:        item.Name,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			Price:       money.FromProto(item.Price),
+			Subtotal:    money.FromProto(item.Subtotal),
+			Available:   item.Available,
+		})
+	}
+	return cart
+}
+
+func fromProtoOrder(o *pb.Order) (*Order, error) {
+	products := make([]OrderedProduct, 0, len(o.Products))
+	for _, p := range o.Products {
+		products = append(products, OrderedProduct{
+			ID:          p.Id,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       money.FromProto(p.Price),
+			Quantity:    p.Quantity,
+		})
+	}
+
+	var orderCreatedAt time.Time
+	if err := orderCreatedAt.UnmarshalBinary(o.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal createdAt: %w", err)
+	}
+
+	statusHistory, err := fromProtoStatusHistory(o.StatusHistory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal status history: %w", err)
+	}
+
+	return &Order{
+		ID:            o.Id,
+		AccountID:     o.AccountId,
+		TotalPrice:    money.FromProto(o.TotalPrice),
+		ExchangeRate:  money.RateFromProto(o.ExchangeRate),
+		CreatedAt:     orderCreatedAt,
+		Products:      products,
+		Status:        fromProtoStatus(o.Status),
+		StatusHistory: statusHistory,
+	}, nil
+}
+
+// OrderStream - поток изменений заказов из WatchOrders
+type OrderStream struct {
+	stream pb.OrderService_WatchOrdersClient
+}
+
+// WatchOrders подписывается на изменения заказа orderID или, если он пуст, заказов аккаунта accountID.
+// Возвращает управление, когда сервер создал подписку; ошибки проверки (нет заказа, нет прав) - сразу.
+// Подписка живёт, пока не отменён ctx.
+func (c *Client) WatchOrders(ctx context.Context, orderID, accountID string) (*OrderStream, error) {
+	stream, err := c.client.WatchOrders(ctx, &pb.WatchOrdersRequest{OrderId: orderID, AccountId: accountID})
+	if err != nil {
+		return nil, err
+	}
+	header, err := stream.Header()
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		// Сервер завершил вызов без заголовков, ошибку возвращает чтение
+		_, err := stream.Recv()
+		return nil, err
+	}
+	return &OrderStream{stream: stream}, nil
+}
+
+// Recv ждёт следующее изменение. io.EOF - сервер завершил поток штатно.
+// ErrWatchLagging - изменения приходили быстрее, чем читались, и часть потеряна.
+func (s *OrderStream) Recv() (*Order, error) {
+	res, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoOrder(res.Order)
+}
+
+func (c *Client) UpdateOrderStatus(ctx context.Context, orderID string, status OrderStatus) ([]StatusChange, error) {
+	res, err := c.client.UpdateOrderStatus(ctx, &pb.UpdateOrderStatusRequest{
+		OrderId: orderID,
+		Status:  toProtoStatus(status),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoStatusHistory(res.StatusHistory)
+}
+
+func (c *Client) CancelOrder(ctx context.Context, orderID string) ([]StatusChange, error) {
+	res, err := c.client.CancelOrder(ctx, &pb.CancelOrderRequest{OrderId: orderID})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoStatusHistory(res.StatusHistory)
 }
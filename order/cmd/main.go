@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
+	"go-microservice/money"
 	"go-microservice/order"
+	"go-microservice/platform"
 	"log"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -12,6 +17,15 @@ type Config struct {
 	DatabaseURL string `envconfig:"DATABASE_URL"`
 	AccountURL  string `envconfig:"ACCOUNT_SERVICE_URL"`
 	CatalogURL  string `envconfig:"CATALOG_SERVICE_URL"`
+	// BaseCurrency должна совпадать с базовой валютой каталога
+	BaseCurrency      string `envconfig:"BASE_CURRENCY" default:"USD"`
+	ExchangeRatesFile string `envconfig:"EXCHANGE_RATES_FILE"`
+	MetricsAddr       string `envconfig:"METRICS_ADDR" default:":9090"`
+	// TLSConfig - сертификат сервиса, он же клиентский для вызовов Account и Catalog
+	platform.TLSConfig
+	platform.TracingConfig
+	platform.LogConfig
+	platform.EventsConfig
 }
 
 func main() {
@@ -21,19 +35,64 @@ func main() {
 		log.Fatal(err)
 	}
 
-	time.Sleep(10 * time.Second)
+	logger, err := platform.NewLogger("order", cfg.LogConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logger)
 
-	r, err := order.NewPostgresReposytory(cfg.DatabaseURL)
+	ctx, stop := platform.SignalContext()
+	defer stop()
+
+	if err := run(ctx, cfg, logger); err != nil {
+		logger.Error("Service failed", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("Stopped")
+}
+
+// run работает до отмены ctx. Клиенты Account и Catalog закрываются в ListenGRPC
+// после остановки сервера, затем relay событий, репозиторий - последним.
+func run(ctx context.Context, cfg Config, logger *slog.Logger) error {
+	shutdownTracing, err := platform.SetupTracing(ctx, "order", cfg.TracingConfig)
 	if err != nil {
-		log.Println(err)
-		return
+		return err
+	}
+	defer shutdownTracing()
+
+	// Ждём, пока поднимется Postgres. Account и Catalog не ждём: до их запуска сервис не готов по health check
+	startCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	var r order.Repository
+	err = platform.Retry(startCtx, "connect to postgres", func() (err error) {
+		r, err = order.NewPostgresReposytory(cfg.DatabaseURL)
+		return err
+	})
+	cancel()
+	if err != nil {
+		return err
 	}
 	defer r.Close()
+	r = order.NewInstrumentedRepository(r, "postgres", logger)
 
-	s := order.NewService(r)
+	// Relay останавливается раньше, чем закрывается репозиторий
+	stopRelay, err := platform.StartEventRelay(ctx, r, cfg.EventsConfig)
+	if err != nil {
+		return err
+	}
+	defer stopRelay()
 
-	if err = order.ListenGRPC(s, cfg.AccountURL, cfg.CatalogURL, 50051); err != nil {
-		log.Fatal(err)
+	go func() {
+		if err := platform.ServeMetrics(ctx, cfg.MetricsAddr); err != nil {
+			logger.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	rates, err := money.LoadRates(cfg.ExchangeRatesFile, cfg.BaseCurrency)
+	if err != nil {
+		return err
 	}
-	log.Println("Listening on port 50051")
+
+	s := order.NewService(r, rates, logger)
+
+	return order.ListenGRPC(ctx, s, cfg.AccountURL, cfg.CatalogURL, 50051, cfg.TLSConfig, logger, platform.HealthCheck{Name: "postgres", Check: r.Ping})
 }
@@ -0,0 +1,97 @@
+package order
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go-microservice/catalog"
+	"go-microservice/platform"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidIdempotencyKey    = platform.NewError(platform.InvalidArgument, "INVALID_IDEMPOTENCY_KEY", "invalid idempotency key")
+	ErrIdempotencyKeyConflict   = platform.NewError(platform.AlreadyExists, "IDEMPOTENCY_KEY_CONFLICT", "idempotency key was used for a different request")
+	ErrIdempotencyKeyInProgress = platform.NewError(platform.Conflict, "IDEMPOTENCY_KEY_IN_PROGRESS", "request with this idempotency key is in progress")
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	// idempotencyKeyTTL - сколько после оформления заказа повтор с тем же ключом возвращает этот заказ
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyLockTTL - сколько ключ занят запросом, который ещё не завершился.
+	// Если сервис упал посреди оформления, ключ освободится сам.
+	idempotencyLockTTL = time.Minute
+)
+
+// IdempotencyKey - ключ идемпотентности PostOrder и Checkout, уникальный в пределах аккаунта.
+// Пока заказ оформляется, OrderID пустой.
+type IdempotencyKey struct {
+	AccountID string
+	Key       string
+	// RequestHash - хеш запроса, с которым ключ использован впервые
+	RequestHash string
+	OrderID     string
+	ExpiresAt   time.Time
+}
+
+// BeginIdempotentRequest занимает ключ key для запроса с хешем requestHash.
+// Возвращает пустую строку, если запрос нужно выполнить, и ID заказа, если запрос
+// с этим ключом уже выполнен. Тот же ключ с другим запросом - ErrIdempotencyKeyConflict,
+// с незавершённым запросом - ErrIdempotencyKeyInProgress.
+func (s orderService) BeginIdempotentRequest(ctx context.Context, accountID, key, requestHash string) (string, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return "", fmt.Errorf("%w: longer than %d bytes", ErrInvalidIdempotencyKey, maxIdempotencyKeyLength)
+	}
+
+	now := time.Now()
+	existing, err := s.repository.ClaimIdempotencyKey(ctx, IdempotencyKey{
+		AccountID:   accountID,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(idempotencyLockTTL),
+	}, now)
+	if err != nil {
+		return "", err
+	}
+	if existing == nil {
+		return "", nil
+	}
+	if existing.RequestHash != requestHash {
+		return "", fmt.Errorf("%w: %q", ErrIdempotencyKeyConflict, key)
+	}
+	if existing.OrderID == "" {
+		return "", fmt.Errorf("%w: %q", ErrIdempotencyKeyInProgress, key)
+	}
+	s.logger.InfoContext(ctx, "Order request replayed", "order_id", existing.OrderID, "account_id", accountID)
+	return existing.OrderID, nil
+}
+
+// AbortIdempotentRequest освобождает ключ после неудачного запроса, чтобы его можно было повторить
+func (s orderService) AbortIdempotentRequest(ctx context.Context, accountID, key string) error {
+	return s.repository.DeleteIdempotencyKey(ctx, accountID, key)
+}
+
+// postOrderHash - хеш содержимого PostOrder: набор товаров без учёта порядка и валюта
+func postOrderHash(accountID string, items []catalog.StockItem, currency string) string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = fmt.Sprintf("%s:%d", item.ProductID, item.Quantity)
+	}
+	slices.Sort(lines)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s", accountID, currency, strings.Join(lines, "\n"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checkoutHash - хеш содержимого Checkout. Корзина в него не входит: после оформления она меняется,
+// и повтор с тем же ключом должен вернуть заказ, а не ошибку IDEMPOTENCY_KEY_CONFLICT.
+func checkoutHash(accountID, currency string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "checkout\n%s\n%s", accountID, currency)
+	return hex.EncodeToString(h.Sum(nil))
+}
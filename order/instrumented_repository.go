@@ -0,0 +1,110 @@
+package order
+
+import (
+	"context"
+	"go-microservice/platform"
+	"log/slog"
+	"time"
+)
+
+// instrumentedRepository замеряет каждый запрос к хранилищу через platform.QueryObserver.
+// Close и Ping передаются как есть.
+type instrumentedRepository struct {
+	Repository
+	queries platform.QueryObserver
+}
+
+// NewInstrumentedRepository оборачивает r, store - имя хранилища в метриках и span (postgres, elasticsearch)
+func NewInstrumentedRepository(r Repository, store string, logger *slog.Logger) Repository {
+	return &instrumentedRepository{Repository: r, queries: platform.QueryObserver{Store: store, Logger: logger}}
+}
+
+func (r *instrumentedRepository) PutOrder(ctx context.Context, o Order, opts PutOrderOptions, events ...platform.Event) error {
+	return platform.ObserveExec(ctx, r.queries, "PutOrder", func(ctx context.Context) error {
+		return r.Repository.PutOrder(ctx, o, opts, events...)
+	})
+}
+
+func (r *instrumentedRepository) GetOrdersForAccount(ctx context.Context, accountID string) ([]Order, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetOrdersForAccount", func(ctx context.Context) ([]Order, error) {
+		return r.Repository.GetOrdersForAccount(ctx, accountID)
+	})
+}
+
+func (r *instrumentedRepository) GetOrdersForAccounts(ctx context.Context, accountIDs []string) ([]Order, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetOrdersForAccounts", func(ctx context.Context) ([]Order, error) {
+		return r.Repository.GetOrdersForAccounts(ctx, accountIDs)
+	})
+}
+
+func (r *instrumentedRepository) GetOrdersForAccountAfter(ctx context.Context, accountID, after string, limit uint64) ([]Order, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetOrdersForAccountAfter", func(ctx context.Context) ([]Order, error) {
+		return r.Repository.GetOrdersForAccountAfter(ctx, accountID, after, limit)
+	})
+}
+
+func (r *instrumentedRepository) GetOrderByID(ctx context.Context, id string) (*Order, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetOrderByID", func(ctx context.Context) (*Order, error) {
+		return r.Repository.GetOrderByID(ctx, id)
+	})
+}
+
+func (r *instrumentedRepository) GetOrderStatus(ctx context.Context, orderID string) (OrderStatus, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetOrderStatus", func(ctx context.Context) (OrderStatus, error) {
+		return r.Repository.GetOrderStatus(ctx, orderID)
+	})
+}
+
+func (r *instrumentedRepository) UpdateOrderStatus(ctx context.Context, orderID string, change StatusChange, events ...platform.Event) error {
+	return platform.ObserveExec(ctx, r.queries, "UpdateOrderStatus", func(ctx context.Context) error {
+		return r.Repository.UpdateOrderStatus(ctx, orderID, change, events...)
+	})
+}
+
+func (r *instrumentedRepository) GetStatusHistory(ctx context.Context, orderID string) ([]StatusChange, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetStatusHistory", func(ctx context.Context) ([]StatusChange, error) {
+		return r.Repository.GetStatusHistory(ctx, orderID)
+	})
+}
+
+func (r *instrumentedRepository) GetCart(ctx context.Context, accountID string) (*Cart, error) {
+	return platform.ObserveQuery(ctx, r.queries, "GetCart", func(ctx context.Context) (*Cart, error) {
+		return r.Repository.GetCart(ctx, accountID)
+	})
+}
+
+func (r *instrumentedRepository) PutCartItem(ctx context.Context, accountID string, item CartItem) error {
+	return platform.ObserveExec(ctx, r.queries, "PutCartItem", func(ctx context.Context) error {
+		return r.Repository.PutCartItem(ctx, accountID, item)
+	})
+}
+
+func (r *instrumentedRepository) DeleteCartItem(ctx context.Context, accountID, productID string) error {
+	return platform.ObserveExec(ctx, r.queries, "DeleteCartItem", func(ctx context.Context) error {
+		return r.Repository.DeleteCartItem(ctx, accountID, productID)
+	})
+}
+
+func (r *instrumentedRepository) ClaimIdempotencyKey(ctx context.Context, k IdempotencyKey, now time.Time) (*IdempotencyKey, error) {
+	return platform.ObserveQuery(ctx, r.queries, "ClaimIdempotencyKey", func(ctx context.Context) (*IdempotencyKey, error) {
+		return r.Repository.ClaimIdempotencyKey(ctx, k, now)
+	})
+}
+
+func (r *instrumentedRepository) DeleteIdempotencyKey(ctx context.Context, accountID, key string) error {
+	return platform.ObserveExec(ctx, r.queries, "DeleteIdempotencyKey", func(ctx context.Context) error {
+		return r.Repository.DeleteIdempotencyKey(ctx, accountID, key)
+	})
+}
+
+func (r *instrumentedRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	return platform.ObserveQuery(ctx, r.queries, "PendingEvents", func(ctx context.Context) ([]platform.Event, error) {
+		return r.Repository.PendingEvents(ctx, limit)
+	})
+}
+
+func (r *instrumentedRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	return platform.ObserveExec(ctx, r.queries, "DeleteEvents", func(ctx context.Context) error {
+		return r.Repository.DeleteEvents(ctx, ids)
+	})
+}
@@ -0,0 +1,293 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"go-microservice/platform"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// inMemoryRepository хранит заказы в памяти процесса.
+// Используется в тестах и для локального запуска без Postgres.
+type inMemoryRepository struct {
+	mu     sync.RWMutex
+	orders map[string]Order
+	// carts - товары корзин по ID аккаунта
+	carts map[string][]CartItem
+	// idempotencyKeys - ключи идемпотентности по ID аккаунта и ключу
+	idempotencyKeys map[[2]string]IdempotencyKey
+	// outbox - неопубликованные события в порядке записи
+	outbox []platform.Event
+}
+
+func NewInMemoryRepository() Repository {
+	return &inMemoryRepository{
+		orders:          make(map[string]Order),
+		carts:           make(map[string][]CartItem),
+		idempotencyKeys: make(map[[2]string]IdempotencyKey),
+	}
+}
+
+// Close implements Repository.
+func (r *inMemoryRepository) Close() {}
+
+// Ping implements Repository.
+func (r *inMemoryRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+// PutOrder implements Repository.
+// Как и в Postgres, у товаров сохраняются только ID, количество и цена.
+func (r *inMemoryRepository) PutOrder(ctx context.Context, o Order, opts PutOrderOptions, events ...platform.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[o.ID]; exists {
+		return fmt.Errorf("order %s already exists", o.ID)
+	}
+
+	products := make([]OrderedProduct, len(o.Products))
+	for i, p := range o.Products {
+		products[i] = OrderedProduct{ID: p.ID, Quantity: p.Quantity, Price: p.Price}
+	}
+	o.Products = products
+	o.StatusHistory = append([]StatusChange(nil), o.StatusHistory...)
+	r.orders[o.ID] = o
+	r.outbox = append(r.outbox, events...)
+	if k := opts.IdempotencyKey; k != nil {
+		id := [2]string{o.AccountID, k.Key}
+		if stored, ok := r.idempotencyKeys[id]; ok {
+			stored.OrderID = o.ID
+			stored.ExpiresAt = k.ExpiresAt
+			r.idempotencyKeys[id] = stored
+		}
+	}
+	for _, item := range opts.CartItems {
+		r.removeCartItem(o.AccountID, item)
+	}
+	return nil
+}
+
+// GetOrdersForAccount implements Repository.
+func (r *inMemoryRepository) GetOrdersForAccount(ctx context.Context, accountId string) ([]Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orders := []Order{}
+	for _, o := range r.orders {
+		// Postgres соединяет заказы с товарами через JOIN, поэтому заказы без товаров не попадают в выдачу
+		if o.AccountID == accountId && len(o.Products) > 0 {
+			orders = append(orders, copyOrder(o))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].ID < orders[j].ID
+	})
+	return orders, nil
+}
+
+// GetOrdersForAccounts implements Repository.
+func (r *inMemoryRepository) GetOrdersForAccounts(ctx context.Context, accountIDs []string) ([]Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	accounts := make(map[string]bool, len(accountIDs))
+	for _, id := range accountIDs {
+		accounts[id] = true
+	}
+
+	orders := []Order{}
+	for _, o := range r.orders {
+		if accounts[o.AccountID] && len(o.Products) > 0 {
+			orders = append(orders, copyOrder(o))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].ID < orders[j].ID
+	})
+	return orders, nil
+}
+
+// GetOrdersForAccountAfter implements Repository.
+func (r *inMemoryRepository) GetOrdersForAccountAfter(ctx context.Context, accountID, after string, limit uint64) ([]Order, error) {
+	orders, err := r.GetOrdersForAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(orders), func(i int) bool {
+		return orders[i].ID > after
+	})
+	orders = orders[i:]
+	if limit < uint64(len(orders)) {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+// GetOrderByID implements Repository.
+func (r *inMemoryRepository) GetOrderByID(ctx context.Context, id string) (*Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	o, ok := r.orders[id]
+	if !ok || len(o.Products) == 0 {
+		return nil, ErrOrderNotFound
+	}
+	o = copyOrder(o)
+	return &o, nil
+}
+
+// GetOrderStatus implements Repository.
+func (r *inMemoryRepository) GetOrderStatus(ctx context.Context, orderID string) (OrderStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	o, ok := r.orders[orderID]
+	if !ok {
+		return "", ErrOrderNotFound
+	}
+	return o.Status, nil
+}
+
+// UpdateOrderStatus implements Repository.
+func (r *inMemoryRepository) UpdateOrderStatus(ctx context.Context, orderID string, change StatusChange, events ...platform.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := r.orders[orderID]
+	if !ok || o.Status != change.From {
+		return ErrStatusConflict
+	}
+	o.Status = change.To
+	o.StatusHistory = append(o.StatusHistory, change)
+	r.orders[orderID] = o
+	r.outbox = append(r.outbox, events...)
+	return nil
+}
+
+// GetStatusHistory implements Repository.
+func (r *inMemoryRepository) GetStatusHistory(ctx context.Context, orderID string) ([]StatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	o, ok := r.orders[orderID]
+	if !ok || len(o.StatusHistory) == 0 {
+		return nil, ErrOrderNotFound
+	}
+	return append([]StatusChange(nil), o.StatusHistory...), nil
+}
+
+// GetCart implements Repository.
+func (r *inMemoryRepository) GetCart(ctx context.Context, accountID string) (*Cart, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return &Cart{AccountID: accountID, Items: append([]CartItem{}, r.carts[accountID]...)}, nil
+}
+
+// PutCartItem implements Repository.
+// Новые товары добавляются в конец, поэтому порядок совпадает с сортировкой по added_at в Postgres.
+func (r *inMemoryRepository) PutCartItem(ctx context.Context, accountID string, item CartItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := r.carts[accountID]
+	for i := range items {
+		if items[i].ProductID == item.ProductID {
+			items[i].Quantity = item.Quantity
+			return nil
+		}
+	}
+	r.carts[accountID] = append(items, item)
+	return nil
+}
+
+// DeleteCartItem implements Repository.
+func (r *inMemoryRepository) DeleteCartItem(ctx context.Context, accountID, productID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := r.carts[accountID]
+	for i := range items {
+		if items[i].ProductID == productID {
+			r.carts[accountID] = append(items[:i:i], items[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrCartItemNotFound, productID)
+}
+
+// removeCartItem вычитает item.Quantity из корзины, как removeCartItem в Postgres. Вызывается под r.mu.
+func (r *inMemoryRepository) removeCartItem(accountID string, item CartItem) {
+	items := r.carts[accountID]
+	for i := range items {
+		if items[i].ProductID != item.ProductID {
+			continue
+		}
+		if items[i].Quantity <= item.Quantity {
+			r.carts[accountID] = append(items[:i:i], items[i+1:]...)
+		} else {
+			items[i].Quantity -= item.Quantity
+		}
+		return
+	}
+}
+
+// ClaimIdempotencyKey implements Repository.
+func (r *inMemoryRepository) ClaimIdempotencyKey(ctx context.Context, k IdempotencyKey, now time.Time) (*IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, existing := range r.idempotencyKeys {
+		if existing.AccountID == k.AccountID && !existing.ExpiresAt.After(now) {
+			delete(r.idempotencyKeys, id)
+		}
+	}
+	id := [2]string{k.AccountID, k.Key}
+	if existing, ok := r.idempotencyKeys[id]; ok {
+		return &existing, nil
+	}
+	k.OrderID = ""
+	r.idempotencyKeys[id] = k
+	return nil, nil
+}
+
+// DeleteIdempotencyKey implements Repository.
+func (r *inMemoryRepository) DeleteIdempotencyKey(ctx context.Context, accountID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := [2]string{accountID, key}
+	if k, ok := r.idempotencyKeys[id]; ok && k.OrderID == "" {
+		delete(r.idempotencyKeys, id)
+	}
+	return nil
+}
+
+// PendingEvents implements Repository.
+func (r *inMemoryRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]platform.Event{}, r.outbox[:min(limit, len(r.outbox))]...), nil
+}
+
+// DeleteEvents implements Repository.
+func (r *inMemoryRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.outbox = slices.DeleteFunc(r.outbox, func(e platform.Event) bool {
+		return slices.Contains(ids, e.ID)
+	})
+	return nil
+}
+
+func copyOrder(o Order) Order {
+	o.Products = append([]OrderedProduct(nil), o.Products...)
+	o.StatusHistory = append([]StatusChange(nil), o.StatusHistory...)
+	return o
+}
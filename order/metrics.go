@@ -0,0 +1,32 @@
+package order
+
+import (
+	"go-microservice/money"
+	"math"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	ordersCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_created_total",
+		Help: "Оформленные заказы по валюте заказа.",
+	}, []string{"currency"})
+
+	ordersValue = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_value_total",
+		Help: "Сумма оформленных заказов в единицах валюты заказа (не в центах).",
+	}, []string{"currency"})
+
+	orderStatusChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "order_status_changes_total",
+		Help: "Смены статуса заказа по новому статусу.",
+	}, []string{"status"})
+)
+
+func observeOrderCreated(o Order) {
+	currency := o.TotalPrice.Currency
+	ordersCreated.WithLabelValues(currency).Inc()
+	ordersValue.WithLabelValues(currency).Add(float64(o.TotalPrice.Amount) / math.Pow10(money.Exponent(currency)))
+}
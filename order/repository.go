@@ -3,6 +3,10 @@ package order
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"go-microservice/money"
+	"go-microservice/platform"
 	"time"
 
 	"github.com/lib/pq"
@@ -10,8 +14,41 @@ import (
 
 type Repository interface {
 	Close()
-	PutOrder(ctx context.Context, o Order) error
+	// Ping проверяет, что хранилище доступно
+	Ping(ctx context.Context) error
+	// PutOrder сохраняет заказ и в той же транзакции записывает events в outbox и изменения из opts
+	PutOrder(ctx context.Context, o Order, opts PutOrderOptions, events ...platform.Event) error
 	GetOrdersForAccount(ctx context.Context, accountId string) ([]Order, error)
+	GetOrdersForAccounts(ctx context.Context, accountIDs []string) ([]Order, error)
+	// GetOrdersForAccountAfter возвращает до limit заказов аккаунта с ID больше after в порядке возрастания ID
+	GetOrdersForAccountAfter(ctx context.Context, accountID, after string, limit uint64) ([]Order, error)
+	GetOrderByID(ctx context.Context, id string) (*Order, error)
+	GetOrderStatus(ctx context.Context, orderID string) (OrderStatus, error)
+	// UpdateOrderStatus переводит заказ из change.From в change.To и в той же транзакции записывает events в outbox
+	UpdateOrderStatus(ctx context.Context, orderID string, change StatusChange, events ...platform.Event) error
+	GetStatusHistory(ctx context.Context, orderID string) ([]StatusChange, error)
+	// GetCart возвращает товары корзины в порядке добавления; у аккаунта без корзины она пустая
+	GetCart(ctx context.Context, accountID string) (*Cart, error)
+	// PutCartItem сохраняет количество товара в корзине, добавляя товар при необходимости
+	PutCartItem(ctx context.Context, accountID string, item CartItem) error
+	// DeleteCartItem удаляет товар из корзины, ErrCartItemNotFound если его там нет
+	DeleteCartItem(ctx context.Context, accountID, productID string) error
+	// ClaimIdempotencyKey сохраняет k и возвращает nil, если у аккаунта нет действующего на момент now
+	// ключа k.Key, иначе возвращает этот ключ. Просроченные ключи аккаунта при этом удаляются.
+	ClaimIdempotencyKey(ctx context.Context, k IdempotencyKey, now time.Time) (*IdempotencyKey, error)
+	// DeleteIdempotencyKey удаляет ключ, к которому ещё не привязан заказ
+	DeleteIdempotencyKey(ctx context.Context, accountID, key string) error
+	platform.OutboxStore
+}
+
+// PutOrderOptions - что PutOrder сохраняет в одной транзакции с заказом, кроме событий
+type PutOrderOptions struct {
+	// IdempotencyKey - занятый ключ аккаунта заказа. К нему привязывается заказ o,
+	// и ключ продлевается до IdempotencyKey.ExpiresAt.
+	IdempotencyKey *IdempotencyKey
+	// CartItems - оформленные заказом товары корзины аккаунта. Их количество вычитается из корзины,
+	// а товары, которых осталось 0 или меньше, удаляются; добавленное после оформления остаётся.
+	CartItems []CartItem
 }
 
 type postgresRepository struct {
@@ -26,6 +63,8 @@ func NewPostgresReposytory(url string) (Repository, error) {
 
 	err = db.Ping()
 	if err != nil {
+		// Конструктор повторяется при старте, пул незапустившейся попытки нужно закрыть
+		db.Close()
 		return nil, err
 	}
 
@@ -37,8 +76,13 @@ func (r *postgresRepository) Close() {
 	r.db.Close()
 }
 
+// Ping implements Repository.
+func (r *postgresRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
 // PutOrder implements Repository.
-func (r *postgresRepository) PutOrder(ctx context.Context, o Order) error {
+func (r *postgresRepository) PutOrder(ctx context.Context, o Order, opts PutOrderOptions, events ...platform.Event) (err error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -48,47 +92,105 @@ func (r *postgresRepository) PutOrder(ctx context.Context, o Order) error {
 			tx.Rollback()
 			return
 		}
-		tx.Commit()
+		err = tx.Commit()
 	}()
 
-	_, err = tx.ExecContext(ctx, "INSERT INTO orders(id,created_at,account_id,total_price) VALUES($1,$2,$3,$4)",
-		o.ID, o.CreatedAt, o.AccountID, o.TotalPrice)
+	_, err = tx.ExecContext(ctx, "INSERT INTO orders(id,created_at,account_id,total_amount,currency,status,base_currency,exchange_rate) VALUES($1,$2,$3,$4,$5,$6,$7,$8)",
+		o.ID, o.CreatedAt, o.AccountID, o.TotalPrice.Amount, o.TotalPrice.Currency, o.Status, o.ExchangeRate.From, o.ExchangeRate.Value)
 	if err != nil {
 		return err
 	}
 
-	stmt, _ := tx.PrepareContext(ctx, pq.CopyIn("order_products", "order_id", "product_id", "quantity"))
-	for _, p := range o.Products {
-		_, err = stmt.ExecContext(ctx, o.ID, p.ID, p.Quantity)
+	for _, c := range o.StatusHistory {
+		if err = insertStatusChange(ctx, tx, o.ID, c); err != nil {
+			return err
+		}
+	}
+	if err = platform.InsertEvents(ctx, tx, events); err != nil {
+		return err
+	}
+	if k := opts.IdempotencyKey; k != nil {
+		_, err = tx.ExecContext(ctx,
+			"UPDATE idempotency_keys SET order_id = $3, expires_at = $4 WHERE account_id = $1 AND idempotency_key = $2",
+			o.AccountID, k.Key, o.ID, k.ExpiresAt)
 		if err != nil {
 			return err
 		}
 	}
-	_, err = stmt.ExecContext(ctx)
+	for _, item := range opts.CartItems {
+		if err = removeCartItem(ctx, tx, o.AccountID, item); err != nil {
+			return err
+		}
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("order_products", "order_id", "product_id", "quantity", "price_amount"))
 	if err != nil {
 		return err
 	}
+	defer stmt.Close()
 
-	stmt.Close()
-	return nil
+	for _, p := range o.Products {
+		_, err = stmt.ExecContext(ctx, o.ID, p.ID, p.Quantity, p.Price.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = stmt.ExecContext(ctx)
+	return err
 }
 
 // GetOrdersForAccount implements Repository.
 func (r *postgresRepository) GetOrdersForAccount(ctx context.Context, accountId string) ([]Order, error) {
+	return r.queryOrders(ctx, "o.account_id=$1", accountId)
+}
+
+// GetOrdersForAccounts implements Repository.
+func (r *postgresRepository) GetOrdersForAccounts(ctx context.Context, accountIDs []string) ([]Order, error) {
+	return r.queryOrders(ctx, "o.account_id = ANY($1)", pq.Array(accountIDs))
+}
+
+// GetOrdersForAccountAfter implements Repository.
+// LIMIT применяется к заказам в подзапросе: queryOrders возвращает по строке на каждый товар заказа.
+func (r *postgresRepository) GetOrdersForAccountAfter(ctx context.Context, accountID, after string, limit uint64) ([]Order, error) {
+	return r.queryOrders(ctx,
+		"o.id IN (SELECT id FROM orders WHERE account_id=$1 AND id > $2 ORDER BY id LIMIT $3)",
+		accountID, after, limit)
+}
+
+// GetOrderByID implements Repository.
+func (r *postgresRepository) GetOrderByID(ctx context.Context, id string) (*Order, error) {
+	orders, err := r.queryOrders(ctx, "o.id=$1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, ErrOrderNotFound
+	}
+	return &orders[0], nil
+}
+
+// queryOrders выбирает заказы вместе с товарами и историей статусов.
+// where подставляется в запрос как есть, значения передаются через args.
+func (r *postgresRepository) queryOrders(ctx context.Context, where string, args ...any) ([]Order, error) {
 	rows, err := r.db.QueryContext(ctx,
 		`SELECT
          o.id,
          o.account_id,
 		 o.created_at,
-         o.total_price::money::numeric::float8,
+         o.total_amount,
+         o.currency,
+         o.status,
+         o.base_currency,
+         o.exchange_rate,
          op.product_id,
-         op.quantity
+         op.quantity,
+         op.price_amount
          FROM orders o
          JOIN order_products op
          ON o.id=op.order_id
-         WHERE o.account_id=$1
+         WHERE `+where+`
          ORDER BY o.id`,
-		accountId)
+		args...)
 	if err != nil {
 		return nil, err
 	}
@@ -100,18 +202,26 @@ func (r *postgresRepository) GetOrdersForAccount(ctx context.Context, accountId
 
 	for rows.Next() {
 		var orderID, accountID string
-		var totalPrice float64
+		var totalPrice money.Money
+		var status OrderStatus
+		var rate money.Rate
 		var productID string
 		var quantity uint32
+		var priceAmount sql.NullInt64
 		var createdAt time.Time
 
 		if err := rows.Scan(
 			&orderID,
 			&accountID,
 			&createdAt,
-			&totalPrice,
+			&totalPrice.Amount,
+			&totalPrice.Currency,
+			&status,
+			&rate.From,
+			&rate.Value,
 			&productID,
 			&quantity,
+			&priceAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -124,21 +234,29 @@ func (r *postgresRepository) GetOrdersForAccount(ctx context.Context, accountId
 			}
 
 			// Создаём новый заказ
+			rate.To = totalPrice.Currency
 			currentOrder = &Order{
-				ID:         orderID,
-				AccountID:  accountID,
-				CreatedAt:  createdAt,
-				TotalPrice: totalPrice,
-				Products:   []OrderedProduct{},
+				ID:           orderID,
+				AccountID:    accountID,
+				CreatedAt:    createdAt,
+				TotalPrice:   totalPrice,
+				ExchangeRate: rate,
+				Status:       status,
+				Products:     []OrderedProduct{},
 			}
 			lastOrderID = orderID
 		}
 
-		// Добавляем продукт в текущий заказ
-		currentOrder.Products = append(currentOrder.Products, OrderedProduct{
+		// Добавляем продукт в текущий заказ.
+		// У заказов, оформленных до сохранения цен, цена остаётся пустой
+		product := OrderedProduct{
 			ID:       productID,
 			Quantity: quantity,
-		})
+		}
+		if priceAmount.Valid {
+			product.Price = money.New(priceAmount.Int64, totalPrice.Currency)
+		}
+		currentOrder.Products = append(currentOrder.Products, product)
 	}
 
 	// Добавляем последний заказ, если он есть
@@ -149,5 +267,250 @@ func (r *postgresRepository) GetOrdersForAccount(ctx context.Context, accountId
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+
+	orderIDs := make([]string, len(orders))
+	for i, o := range orders {
+		orderIDs[i] = o.ID
+	}
+	history, err := r.getStatusHistories(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].StatusHistory = history[orders[i].ID]
+	}
 	return orders, nil
 }
+
+// GetOrderStatus implements Repository.
+func (r *postgresRepository) GetOrderStatus(ctx context.Context, orderID string) (OrderStatus, error) {
+	var status OrderStatus
+	err := r.db.QueryRowContext(ctx, "SELECT status FROM orders WHERE id = $1", orderID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrOrderNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// UpdateOrderStatus implements Repository.
+// Статус меняется только если в базе всё ещё лежит change.From,
+// иначе возвращается ErrStatusConflict.
+func (r *postgresRepository) UpdateOrderStatus(ctx context.Context, orderID string, change StatusChange, events ...platform.Event) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	res, err := tx.ExecContext(ctx, "UPDATE orders SET status = $1 WHERE id = $2 AND status = $3",
+		change.To, orderID, change.From)
+	if err != nil {
+		return err
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrStatusConflict
+	}
+
+	if err = insertStatusChange(ctx, tx, orderID, change); err != nil {
+		return err
+	}
+	return platform.InsertEvents(ctx, tx, events)
+}
+
+// GetStatusHistory implements Repository.
+func (r *postgresRepository) GetStatusHistory(ctx context.Context, orderID string) ([]StatusChange, error) {
+	history, err := r.getStatusHistories(ctx, []string{orderID})
+	if err != nil {
+		return nil, err
+	}
+	if len(history[orderID]) == 0 {
+		return nil, ErrOrderNotFound
+	}
+	return history[orderID], nil
+}
+
+// getStatusHistories загружает историю статусов сразу для нескольких заказов
+func (r *postgresRepository) getStatusHistories(ctx context.Context, orderIDs []string) (map[string][]StatusChange, error) {
+	history := make(map[string][]StatusChange, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return history, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT order_id, COALESCE(from_status, ''), to_status, changed_at
+         FROM order_status_history
+         WHERE order_id = ANY($1)
+         ORDER BY changed_at, id`,
+		pq.Array(orderIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var c StatusChange
+		if err := rows.Scan(&orderID, &c.From, &c.To, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		history[orderID] = append(history[orderID], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetCart implements Repository.
+func (r *postgresRepository) GetCart(ctx context.Context, accountID string) (*Cart, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT product_id, quantity, added_at FROM cart_items WHERE account_id = $1 ORDER BY added_at, product_id",
+		accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cart := &Cart{AccountID: accountID, Items: []CartItem{}}
+	for rows.Next() {
+		var item CartItem
+		if err := rows.Scan(&item.ProductID, &item.Quantity, &item.AddedAt); err != nil {
+			return nil, err
+		}
+		cart.Items = append(cart.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// PutCartItem implements Repository.
+func (r *postgresRepository) PutCartItem(ctx context.Context, accountID string, item CartItem) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO cart_items(account_id,product_id,quantity,added_at) VALUES($1,$2,$3,$4)
+         ON CONFLICT (account_id,product_id) DO UPDATE SET quantity = EXCLUDED.quantity`,
+		accountID, item.ProductID, item.Quantity, item.AddedAt)
+	return err
+}
+
+// DeleteCartItem implements Repository.
+func (r *postgresRepository) DeleteCartItem(ctx context.Context, accountID, productID string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM cart_items WHERE account_id = $1 AND product_id = $2", accountID, productID)
+	if err != nil {
+		return err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: %s", ErrCartItemNotFound, productID)
+	}
+	return nil
+}
+
+// removeCartItem вычитает item.Quantity из количества товара в корзине. quantity в таблице
+// всегда положительно, поэтому товар, которого не останется, удаляется, а не обновляется.
+func removeCartItem(ctx context.Context, tx *sql.Tx, accountID string, item CartItem) error {
+	_, err := tx.ExecContext(ctx,
+		"DELETE FROM cart_items WHERE account_id = $1 AND product_id = $2 AND quantity <= $3",
+		accountID, item.ProductID, item.Quantity)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		"UPDATE cart_items SET quantity = quantity - $3 WHERE account_id = $1 AND product_id = $2 AND quantity > $3",
+		accountID, item.ProductID, item.Quantity)
+	return err
+}
+
+// ClaimIdempotencyKey implements Repository.
+// Параллельный INSERT того же ключа ждёт завершения первой транзакции, поэтому ключ достаётся одному запросу.
+func (r *postgresRepository) ClaimIdempotencyKey(ctx context.Context, k IdempotencyKey, now time.Time) (_ *IdempotencyKey, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	// Просроченные ключи больше не защищают от повторов, их место может занять новый запрос
+	_, err = tx.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE account_id = $1 AND expires_at <= $2", k.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO idempotency_keys(account_id,idempotency_key,request_hash,expires_at) VALUES($1,$2,$3,$4)
+         ON CONFLICT (account_id,idempotency_key) DO NOTHING`,
+		k.AccountID, k.Key, k.RequestHash, k.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if inserted == 1 {
+		return nil, nil
+	}
+
+	existing := IdempotencyKey{AccountID: k.AccountID, Key: k.Key}
+	var orderID sql.NullString
+	err = tx.QueryRowContext(ctx,
+		"SELECT request_hash, order_id, expires_at FROM idempotency_keys WHERE account_id = $1 AND idempotency_key = $2",
+		k.AccountID, k.Key).Scan(&existing.RequestHash, &orderID, &existing.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	existing.OrderID = orderID.String
+	return &existing, nil
+}
+
+// DeleteIdempotencyKey implements Repository.
+func (r *postgresRepository) DeleteIdempotencyKey(ctx context.Context, accountID, key string) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM idempotency_keys WHERE account_id = $1 AND idempotency_key = $2 AND order_id IS NULL",
+		accountID, key)
+	return err
+}
+
+// PendingEvents implements Repository.
+func (r *postgresRepository) PendingEvents(ctx context.Context, limit int) ([]platform.Event, error) {
+	return platform.PendingEvents(ctx, r.db, limit)
+}
+
+// DeleteEvents implements Repository.
+func (r *postgresRepository) DeleteEvents(ctx context.Context, ids []string) error {
+	return platform.DeleteEvents(ctx, r.db, ids)
+}
+
+func insertStatusChange(ctx context.Context, tx *sql.Tx, orderID string, c StatusChange) error {
+	var from sql.NullString
+	if c.From != "" {
+		from = sql.NullString{String: string(c.From), Valid: true}
+	}
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO order_status_history(order_id,from_status,to_status,changed_at) VALUES($1,$2,$3,$4)",
+		orderID, from, c.To, c.ChangedAt)
+	return err
+}
@@ -0,0 +1,365 @@
+package order
+
+import (
+	"context"
+	"errors"
+	eventspb "go-microservice/events/pb"
+	"go-microservice/money"
+	"go-microservice/platform"
+	"go-microservice/platform/storetest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// TestRepositoryContract гоняет контрактные тесты на всех реализациях Repository.
+// Postgres подключается только если задан TEST_ORDER_DATABASE_URL (схема из up.sql должна быть применена).
+func TestRepositoryContract(t *testing.T) {
+	tests := []storetest.Test[Repository]{
+		{Name: "put and get", Run: testPutAndGetOrder},
+		{Name: "get missing", Run: testGetMissingOrder},
+		{Name: "orders for account", Run: testGetOrdersForAccount},
+		{Name: "orders for accounts", Run: testGetOrdersForAccounts},
+		{Name: "orders for account after", Run: testGetOrdersForAccountAfter},
+		{Name: "update status", Run: testUpdateOrderStatus},
+		{Name: "update status conflict", Run: testUpdateOrderStatusConflict},
+		{Name: "cart", Run: testCart},
+		{Name: "idempotency key", Run: testIdempotencyKey},
+		{Name: "outbox", Run: testOutbox},
+	}
+	storetest.RunContract(t, tests, func() Repository { return NewInMemoryRepository() }, storetest.Store[Repository]{
+		Name: "postgres",
+		Env:  "TEST_ORDER_DATABASE_URL",
+		Open: func(t *testing.T, url string) Repository {
+			r, err := NewPostgresReposytory(url)
+			if err != nil {
+				t.Fatalf("connect to postgres: %v", err)
+			}
+			if _, err := r.(*postgresRepository).db.Exec("TRUNCATE orders, cart_items, idempotency_keys, outbox CASCADE"); err != nil {
+				t.Fatalf("truncate orders: %v", err)
+			}
+			t.Cleanup(r.Close)
+			return r
+		},
+	})
+}
+
+func newOrder(accountID string, products ...OrderedProduct) Order {
+	// Postgres хранит время с точностью до микросекунд
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+	total := money.New(0, "USD")
+	for _, p := range products {
+		total.Amount += p.Price.Amount * int64(p.Quantity)
+	}
+	return Order{
+		ID:           ksuid.New().String(),
+		AccountID:    accountID,
+		CreatedAt:    createdAt,
+		TotalPrice:   total,
+		Products:     products,
+		ExchangeRate: money.Rate{From: "USD", To: "USD", Value: "1"},
+		Status:       StatusPending,
+		StatusHistory: []StatusChange{
+			{To: StatusPending, ChangedAt: createdAt},
+		},
+	}
+}
+
+func putOrders(t *testing.T, r Repository, orders ...Order) {
+	t.Helper()
+	for _, o := range orders {
+		if err := r.PutOrder(context.Background(), o, PutOrderOptions{}); err != nil {
+			t.Fatalf("PutOrder(%s): %v", o.ID, err)
+		}
+	}
+}
+
+func assertOrder(t *testing.T, got, want Order) {
+	t.Helper()
+	if got.ID != want.ID || got.AccountID != want.AccountID || got.TotalPrice != want.TotalPrice || got.Status != want.Status {
+		t.Errorf("order = {%s %s %v %s}, want {%s %s %v %s}",
+			got.ID, got.AccountID, got.TotalPrice, got.Status,
+			want.ID, want.AccountID, want.TotalPrice, want.Status)
+	}
+	if got.ExchangeRate != want.ExchangeRate {
+		t.Errorf("ExchangeRate = %+v, want %+v", got.ExchangeRate, want.ExchangeRate)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, want.CreatedAt)
+	}
+
+	// Репозиторий хранит только ID, количество и цену товара, порядок товаров не гарантируется
+	sort.Slice(got.Products, func(i, j int) bool { return got.Products[i].ID < got.Products[j].ID })
+	wantProducts := make([]OrderedProduct, len(want.Products))
+	for i, p := range want.Products {
+		wantProducts[i] = OrderedProduct{ID: p.ID, Quantity: p.Quantity, Price: p.Price}
+	}
+	sort.Slice(wantProducts, func(i, j int) bool { return wantProducts[i].ID < wantProducts[j].ID })
+	if len(got.Products) != len(wantProducts) {
+		t.Fatalf("got %d products, want %d", len(got.Products), len(wantProducts))
+	}
+	for i := range wantProducts {
+		if got.Products[i] != wantProducts[i] {
+			t.Errorf("product[%d] = %+v, want %+v", i, got.Products[i], wantProducts[i])
+		}
+	}
+
+	assertHistory(t, got.StatusHistory, want.StatusHistory)
+}
+
+func assertHistory(t *testing.T, got, want []StatusChange) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("history has %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].From != want[i].From || got[i].To != want[i].To || !got[i].ChangedAt.Equal(want[i].ChangedAt) {
+			t.Errorf("history[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func testPutAndGetOrder(t *testing.T, r Repository) {
+	want := newOrder(ksuid.New().String(),
+		OrderedProduct{ID: ksuid.New().String(), Name: "Keyboard", Price: money.New(4999, "USD"), Quantity: 1},
+		OrderedProduct{ID: ksuid.New().String(), Name: "Mouse", Price: money.New(2050, "USD"), Quantity: 2},
+	)
+	putOrders(t, r, want)
+
+	got, err := r.GetOrderByID(context.Background(), want.ID)
+	if err != nil {
+		t.Fatalf("GetOrderByID: %v", err)
+	}
+	assertOrder(t, *got, want)
+}
+
+func testGetMissingOrder(t *testing.T, r Repository) {
+	ctx := context.Background()
+	id := ksuid.New().String()
+
+	if _, err := r.GetOrderByID(ctx, id); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("GetOrderByID error = %v, want %v", err, ErrOrderNotFound)
+	}
+	if _, err := r.GetOrderStatus(ctx, id); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("GetOrderStatus error = %v, want %v", err, ErrOrderNotFound)
+	}
+	if _, err := r.GetStatusHistory(ctx, id); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("GetStatusHistory error = %v, want %v", err, ErrOrderNotFound)
+	}
+}
+
+func testGetOrdersForAccount(t *testing.T, r Repository) {
+	accountID := ksuid.New().String()
+	product := OrderedProduct{ID: ksuid.New().String(), Price: money.New(1000, "USD"), Quantity: 1}
+	first := newOrder(accountID, product)
+	second := newOrder(accountID, product)
+	other := newOrder(ksuid.New().String(), product)
+	putOrders(t, r, first, second, other)
+
+	got, err := r.GetOrdersForAccount(context.Background(), accountID)
+	if err != nil {
+		t.Fatalf("GetOrdersForAccount: %v", err)
+	}
+
+	want := []Order{first, second}
+	sort.Slice(want, func(i, j int) bool { return want[i].ID < want[j].ID })
+	if len(got) != len(want) {
+		t.Fatalf("got %d orders, want %d", len(got), len(want))
+	}
+	for i := range want {
+		assertOrder(t, got[i], want[i])
+	}
+
+	none, err := r.GetOrdersForAccount(context.Background(), ksuid.New().String())
+	if err != nil {
+		t.Fatalf("GetOrdersForAccount: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("got %d orders for unknown account, want 0", len(none))
+	}
+}
+
+func testGetOrdersForAccountAfter(t *testing.T, r Repository) {
+	ctx := context.Background()
+	accountID := ksuid.New().String()
+	// У заказа несколько товаров: limit считает заказы, а не строки с товарами
+	products := []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(1000, "USD"), Quantity: 1},
+		{ID: ksuid.New().String(), Price: money.New(500, "USD"), Quantity: 2},
+	}
+	orders := []Order{newOrder(accountID, products...), newOrder(accountID, products...), newOrder(accountID, products...)}
+	putOrders(t, r, append(orders, newOrder(ksuid.New().String(), products...))...)
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+
+	tests := []struct {
+		after string
+		limit uint64
+		want  []Order
+	}{
+		{after: "", limit: 2, want: orders[0:2]},
+		{after: orders[1].ID, limit: 2, want: orders[2:3]},
+		{after: orders[2].ID, limit: 2, want: nil},
+	}
+	for _, tt := range tests {
+		got, err := r.GetOrdersForAccountAfter(ctx, accountID, tt.after, tt.limit)
+		if err != nil {
+			t.Fatalf("GetOrdersForAccountAfter(%q, %d): %v", tt.after, tt.limit, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("GetOrdersForAccountAfter(%q, %d) returned %d orders, want %d", tt.after, tt.limit, len(got), len(tt.want))
+		}
+		for i := range got {
+			assertOrder(t, got[i], tt.want[i])
+		}
+	}
+}
+
+func testGetOrdersForAccounts(t *testing.T, r Repository) {
+	first, second := ksuid.New().String(), ksuid.New().String()
+	product := OrderedProduct{ID: ksuid.New().String(), Price: money.New(1000, "USD"), Quantity: 1}
+	a := newOrder(first, product)
+	b := newOrder(second, product)
+	c := newOrder(second, product)
+	other := newOrder(ksuid.New().String(), product)
+	putOrders(t, r, a, b, c, other)
+
+	got, err := r.GetOrdersForAccounts(context.Background(), []string{first, second, ksuid.New().String()})
+	if err != nil {
+		t.Fatalf("GetOrdersForAccounts: %v", err)
+	}
+
+	want := []Order{a, b, c}
+	sort.Slice(want, func(i, j int) bool { return want[i].ID < want[j].ID })
+	if len(got) != len(want) {
+		t.Fatalf("got %d orders, want %d", len(got), len(want))
+	}
+	for i := range want {
+		assertOrder(t, got[i], want[i])
+	}
+}
+
+func testUpdateOrderStatus(t *testing.T, r Repository) {
+	ctx := context.Background()
+	o := newOrder(ksuid.New().String(), OrderedProduct{ID: ksuid.New().String(), Price: money.New(500, "USD"), Quantity: 3})
+	putOrders(t, r, o)
+
+	change := StatusChange{From: StatusPending, To: StatusPaid, ChangedAt: o.CreatedAt.Add(time.Minute)}
+	if err := r.UpdateOrderStatus(ctx, o.ID, change); err != nil {
+		t.Fatalf("UpdateOrderStatus: %v", err)
+	}
+
+	status, err := r.GetOrderStatus(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("GetOrderStatus: %v", err)
+	}
+	if status != StatusPaid {
+		t.Errorf("status = %s, want %s", status, StatusPaid)
+	}
+
+	history, err := r.GetStatusHistory(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("GetStatusHistory: %v", err)
+	}
+	assertHistory(t, history, append(o.StatusHistory, change))
+}
+
+func testUpdateOrderStatusConflict(t *testing.T, r Repository) {
+	o := newOrder(ksuid.New().String(), OrderedProduct{ID: ksuid.New().String(), Price: money.New(500, "USD"), Quantity: 1})
+	putOrders(t, r, o)
+
+	change := StatusChange{From: StatusPaid, To: StatusShipped, ChangedAt: time.Now()}
+	if err := r.UpdateOrderStatus(context.Background(), o.ID, change); !errors.Is(err, ErrStatusConflict) {
+		t.Errorf("UpdateOrderStatus error = %v, want %v", err, ErrStatusConflict)
+	}
+}
+
+func testCart(t *testing.T, r Repository) {
+	ctx := context.Background()
+	accountID := ksuid.New().String()
+	addedAt := time.Now().Truncate(time.Millisecond)
+	first := CartItem{ProductID: ksuid.New().String(), Quantity: 1, AddedAt: addedAt}
+	second := CartItem{ProductID: ksuid.New().String(), Quantity: 2, AddedAt: addedAt.Add(time.Second)}
+	for _, item := range []CartItem{first, second} {
+		if err := r.PutCartItem(ctx, accountID, item); err != nil {
+			t.Fatalf("PutCartItem: %v", err)
+		}
+	}
+	first.Quantity = 5
+	if err := r.PutCartItem(ctx, accountID, first); err != nil {
+		t.Fatalf("PutCartItem: %v", err)
+	}
+
+	cart, err := r.GetCart(ctx, accountID)
+	if err != nil {
+		t.Fatalf("GetCart: %v", err)
+	}
+	if len(cart.Items) != 2 || cart.Items[0].ProductID != first.ProductID || cart.Items[0].Quantity != 5 || cart.Items[1].ProductID != second.ProductID {
+		t.Fatalf("items = %+v, want %s x5 then %s x2", cart.Items, first.ProductID, second.ProductID)
+	}
+
+	if err := r.DeleteCartItem(ctx, accountID, second.ProductID); err != nil {
+		t.Fatalf("DeleteCartItem: %v", err)
+	}
+	if err := r.DeleteCartItem(ctx, accountID, second.ProductID); !errors.Is(err, ErrCartItemNotFound) {
+		t.Errorf("DeleteCartItem error = %v, want %v", err, ErrCartItemNotFound)
+	}
+
+	// Заказ из корзины вычитает оформленное количество: товар, добавленный во время оформления, остаётся
+	if err := r.PutCartItem(ctx, accountID, second); err != nil {
+		t.Fatalf("PutCartItem: %v", err)
+	}
+	o := newOrder(accountID, OrderedProduct{ID: first.ProductID, Price: money.New(100, "USD"), Quantity: 2}, OrderedProduct{ID: second.ProductID, Price: money.New(100, "USD"), Quantity: 2})
+	checkedOut := []CartItem{{ProductID: first.ProductID, Quantity: 2}, {ProductID: second.ProductID, Quantity: 2}}
+	if err := r.PutOrder(ctx, o, PutOrderOptions{CartItems: checkedOut}); err != nil {
+		t.Fatalf("PutOrder: %v", err)
+	}
+	if cart, err = r.GetCart(ctx, accountID); err != nil || len(cart.Items) != 1 || cart.Items[0].ProductID != first.ProductID || cart.Items[0].Quantity != 3 {
+		t.Errorf("GetCart after PutOrder = %+v, %v, want %s x3", cart, err, first.ProductID)
+	}
+}
+
+func testIdempotencyKey(t *testing.T, r Repository) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Millisecond)
+	k := IdempotencyKey{AccountID: ksuid.New().String(), Key: "key", RequestHash: "hash", ExpiresAt: now.Add(time.Minute)}
+
+	if existing, err := r.ClaimIdempotencyKey(ctx, k, now); err != nil || existing != nil {
+		t.Fatalf("ClaimIdempotencyKey = %+v, %v, want claimed", existing, err)
+	}
+	// Ключ привязывается к заказу в той же транзакции, что и сам заказ
+	o := newOrder(k.AccountID, OrderedProduct{ID: ksuid.New().String(), Name: "Keyboard", Price: money.New(4999, "USD"), Quantity: 1})
+	orderID := o.ID
+	completed := k
+	completed.ExpiresAt = now.Add(time.Hour)
+	if err := r.PutOrder(ctx, o, PutOrderOptions{IdempotencyKey: &completed}); err != nil {
+		t.Fatalf("PutOrder: %v", err)
+	}
+
+	existing, err := r.ClaimIdempotencyKey(ctx, IdempotencyKey{AccountID: k.AccountID, Key: k.Key, RequestHash: "other", ExpiresAt: now.Add(time.Minute)}, now)
+	if err != nil {
+		t.Fatalf("ClaimIdempotencyKey: %v", err)
+	}
+	if existing == nil || existing.RequestHash != "hash" || existing.OrderID != orderID {
+		t.Fatalf("existing = %+v, want hash and order %s", existing, orderID)
+	}
+
+	// Завершённый ключ не удаляется, а просроченный можно занять снова
+	if err := r.DeleteIdempotencyKey(ctx, k.AccountID, k.Key); err != nil {
+		t.Fatalf("DeleteIdempotencyKey: %v", err)
+	}
+	if existing, err := r.ClaimIdempotencyKey(ctx, k, now); err != nil || existing == nil {
+		t.Errorf("ClaimIdempotencyKey after delete = %+v, %v, want completed key", existing, err)
+	}
+	if existing, err := r.ClaimIdempotencyKey(ctx, k, now.Add(time.Hour)); err != nil || existing != nil {
+		t.Errorf("ClaimIdempotencyKey after expiry = %+v, %v, want claimed", existing, err)
+	}
+}
+
+func testOutbox(t *testing.T, r Repository) {
+	o := newOrder(ksuid.New().String(), OrderedProduct{ID: ksuid.New().String(), Name: "Keyboard", Price: money.New(4999, "USD"), Quantity: 1})
+	storetest.CheckOutbox(t, r, o.ID, &eventspb.OrderCreated{OrderId: o.ID}, func(events ...platform.Event) error {
+		return r.PutOrder(context.Background(), o, PutOrderOptions{}, events...)
+	})
+}
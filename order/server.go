@@ -6,47 +6,92 @@ import (
 
 	"go-microservice/account"
 	"go-microservice/catalog"
+	"go-microservice/money"
 	"go-microservice/order/pb"
+	"go-microservice/platform"
+	"log/slog"
 	"net"
+	"slices"
+	"strings"
+	"time"
 
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 )
 
+// maxBatchAccounts ограничивает размер запроса GetOrdersForAccounts
+const maxBatchAccounts = 500
+
+// productBatchSize - сколько товаров запрашивается у каталога за раз. Каталог отдаёт товары
+// одной страницей поиска Elasticsearch, а она не больше index.max_result_window (10000).
+const productBatchSize = 1000
+
+// accountService - методы account.Client, которые использует сервер заказов
+type accountService interface {
+	GetAccount(ctx context.Context, id string) (*account.Account, error)
+}
+
+// catalogService - методы catalog.Client, которые использует сервер заказов
+type catalogService interface {
+	GetProduct(ctx context.Context, id, currency string) (*catalog.Product, error)
+	GetProducts(ctx context.Context, ids []string, query string, skip, take uint64, currency string) ([]catalog.Product, error)
+	ReserveStock(ctx context.Context, items []catalog.StockItem) (string, error)
+	ReleaseStock(ctx context.Context, reservationID string) error
+	CommitStock(ctx context.Context, reservationID string) error
+}
+
 type grpcServer struct {
 	service       Service
-	accountClient *account.Client
-	catalogClient *catalog.Client
+	accountClient accountService
+	catalogClient catalogService
+	logger        *slog.Logger
+	// stopping закрывается при остановке сервера, чтобы завершить подписки WatchOrders
+	stopping <-chan struct{}
 	pb.UnimplementedOrderServiceServer
 }
 
-func ListenGRPC(s Service, accountURL, catalogURL string, port int) error {
-	accountClient, err := account.NewClient(accountURL)
+// ListenGRPC обслуживает s на port, пока не отменён ctx, и дожидается текущих вызовов.
+// tlsConfig используется и для входящих вызовов, и для клиентов сервисов Account и Catalog.
+// Кроме checks, по grpc.health.v1 проверяется доступность Account и Catalog. Вызовы пишутся в logger.
+func ListenGRPC(ctx context.Context, s Service, accountURL, catalogURL string, port int, tlsConfig platform.TLSConfig, logger *slog.Logger, checks ...platform.HealthCheck) error {
+	serv, err := platform.NewServer(tlsConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	accountClient, err := account.NewClient(accountURL, tlsConfig)
 	if err != nil {
 		return err
 	}
 
-	catalogClient, err := catalog.NewClient(catalogURL)
+	defer accountClient.Close()
+
+	catalogClient, err := catalog.NewClient(catalogURL, tlsConfig)
 	if err != nil {
 		return err
 	}
+	defer catalogClient.Close()
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
-		catalogClient.Close()
-		accountClient.Close()
 		return err
 	}
-	serv := grpc.NewServer()
 	pb.RegisterOrderServiceServer(serv, &grpcServer{
 		service:       s,
 		accountClient: accountClient,
 		catalogClient: catalogClient,
+		logger:        logger,
+		stopping:      ctx.Done(),
 	})
+	checks = append(checks,
+		platform.HealthCheck{Name: "account", Check: accountClient.Health},
+		platform.HealthCheck{Name: "catalog", Check: catalogClient.Health},
+	)
+	platform.RegisterHealth(serv, pb.OrderService_ServiceDesc.ServiceName, checks...)
 	reflection.Register(serv)
-	return serv.Serve(lis)
+	// Клиенты закрываются после того, как сервер дождётся текущих вызовов
+	return platform.Serve(ctx, serv, lis)
 }
 
 func (s *grpcServer) GetOrdersForAccount(ctx context.Context, r *pb.GetOrdersForAccountRequest) (*pb.GetOrdersForAccountResponse, error) {
@@ -54,25 +99,111 @@ func (s *grpcServer) GetOrdersForAccount(ctx context.Context, r *pb.GetOrdersFor
 	if r.AccountId == "" {
 		return nil, status.Error(codes.InvalidArgument, "accountId is required")
 	}
+	if err := authorizeAccount(ctx, r.AccountId, "read orders"); err != nil {
+		return nil, err
+	}
 
 	// Получение заказов
 	orders, err := s.service.GetOrdersForAccount(ctx, r.AccountId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get orders: %v", err)
+		return nil, err
+	}
+
+	pbOrders, err := s.hydrateOrders(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOrdersForAccountResponse{Orders: pbOrders}, nil
+}
+
+func (s *grpcServer) GetOrdersForAccounts(ctx context.Context, r *pb.GetOrdersForAccountsRequest) (*pb.GetOrdersForAccountsResponse, error) {
+	if len(r.AccountIds) > maxBatchAccounts {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d accountIds are allowed", maxBatchAccounts)
+	}
+	// Пользователь без роли ADMIN читает только свои заказы через GetOrdersForAccount
+	if err := authorizeAdmin(ctx, "read orders of several accounts"); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.service.GetOrdersForAccounts(ctx, r.AccountIds)
+	if err != nil {
+		return nil, err
+	}
+
+	pbOrders, err := s.hydrateOrders(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOrdersForAccountsResponse{Orders: pbOrders}, nil
+}
+
+func (s *grpcServer) GetOrdersPageForAccount(ctx context.Context, r *pb.GetOrdersPageForAccountRequest) (*pb.GetOrdersPageForAccountResponse, error) {
+	if err := authorizeAccount(ctx, r.AccountId, "read orders"); err != nil {
+		return nil, err
+	}
+
+	page, err := s.service.GetOrdersPageForAccount(ctx, r.AccountId, r.After, r.First)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]Order, len(page.Edges))
+	for i, e := range page.Edges {
+		orders[i] = e.Order
+	}
+	pbOrders, err := s.hydrateOrders(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*pb.OrderEdge, len(page.Edges))
+	for i, e := range page.Edges {
+		edges[i] = &pb.OrderEdge{Order: pbOrders[i], Cursor: e.Cursor}
+	}
+	return &pb.GetOrdersPageForAccountResponse{Edges: edges, HasNextPage: page.HasNextPage}, nil
+}
+
+func (s *grpcServer) GetOrder(ctx context.Context, r *pb.GetOrderRequest) (*pb.GetOrderResponse, error) {
+	if r.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	order, err := s.service.GetOrder(ctx, r.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, order.AccountID, "read orders"); err != nil {
+		return nil, err
+	}
+
+	pbOrders, err := s.hydrateOrders(ctx, []Order{*order})
+	if err != nil {
+		return nil, err
 	}
 
-	// Сбор всех ID продуктов для batch-запроса
+	return &pb.GetOrderResponse{Order: pbOrders[0]}, nil
+}
+
+// hydrateOrders дополняет товары заказов данными из каталога и конвертирует заказы в protobuf
+func (s *grpcServer) hydrateOrders(ctx context.Context, orders []Order) ([]*pb.Order, error) {
+	// Один товар встречается во многих заказах, поэтому ID собираются без повторов
 	productIDs := make([]string, 0)
+	seen := make(map[string]bool)
 	for _, o := range orders {
 		for _, p := range o.Products {
-			productIDs = append(productIDs, p.ID)
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				productIDs = append(productIDs, p.ID)
+			}
 		}
 	}
 
-	// Получение информации о продуктах одним запросом
+	// Получение информации о продуктах пачками по productBatchSize
 	productsMap := make(map[string]*catalog.Product)
-	if len(productIDs) > 0 {
-		products, err := s.catalogClient.GetProducts(ctx, productIDs, "", 0, 0)
+	for ids := range slices.Chunk(productIDs, productBatchSize) {
+		products, err := s.catalogClient.GetProducts(ctx, ids, "", 0, 0, "")
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get products: %v", err)
 		}
@@ -85,9 +216,11 @@ func (s *grpcServer) GetOrdersForAccount(ctx context.Context, r *pb.GetOrdersFor
 	pbOrders := make([]*pb.Order, 0, len(orders))
 	for _, o := range orders {
 		pbOrder := &pb.Order{
-			Id:         o.ID,
-			AccountId:  o.AccountID,
-			TotalPrice: o.TotalPrice,
+			Id:           o.ID,
+			AccountId:    o.AccountID,
+			TotalPrice:   money.ToProto(o.TotalPrice),
+			ExchangeRate: money.RateToProto(o.ExchangeRate),
+			Status:       toProtoStatus(o.Status),
 		}
 
 		createdAtBytes, err := o.CreatedAt.MarshalBinary()
@@ -96,27 +229,39 @@ func (s *grpcServer) GetOrdersForAccount(ctx context.Context, r *pb.GetOrdersFor
 		}
 		pbOrder.CreatedAt = createdAtBytes
 
+		pbOrder.StatusHistory, err = toProtoStatusHistory(o.StatusHistory)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal status history: %v", err)
+		}
+
 		// Обработка продуктов
 		pbProducts := make([]*pb.Order_OrderProduct, 0, len(o.Products))
 		for _, p := range o.Products {
+			pbProduct := &pb.Order_OrderProduct{
+				Id:       p.ID,
+				Quantity: p.Quantity,
+				Price:    money.ToProto(p.Price),
+			}
+			// Каталог возвращает и удалённые товары, так что отсутствие товара - рассинхронизация данных.
+			// Из-за неё не должен ломаться весь список, поэтому товар отдаётся с тем, что сохранено в заказе.
 			product, exists := productsMap[p.ID]
 			if !exists {
-				continue // или возвращаем ошибку, в зависимости от требований
+				s.logger.WarnContext(ctx, "Ordered product not found in catalog", "order_id", o.ID, "product_id", p.ID)
+			} else {
+				pbProduct.Name = product.Name
+				pbProduct.Description = product.Description
+				// Цена на момент оформления важнее текущей цены каталога
+				if p.Price.Currency == "" {
+					pbProduct.Price = money.ToProto(product.Price)
+				}
 			}
-
-			pbProducts = append(pbProducts, &pb.Order_OrderProduct{
-				Id:          p.ID,
-				Quantity:    p.Quantity,
-				Name:        product.Name,
-				Description: product.Description,
-				Price:       product.Price,
-			})
+			pbProducts = append(pbProducts, pbProduct)
 		}
 		pbOrder.Products = pbProducts
 		pbOrders = append(pbOrders, pbOrder)
 	}
 
-	return &pb.GetOrdersForAccountResponse{Orders: pbOrders}, nil
+	return pbOrders, nil
 }
 
 func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb.PostOrderResponse, error) {
@@ -127,24 +272,129 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 	if len(r.Products) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "at least one product is required")
 	}
+	currency, err := parseCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, r.AccountId, "place an order"); err != nil {
+		return nil, err
+	}
+
+	items := make([]catalog.StockItem, len(r.Products))
+	for i, p := range r.Products {
+		items[i] = catalog.StockItem{ProductID: p.ProductId, Quantity: p.Quantity}
+	}
 
-	// Проверка аккаунта
-	if _, err := s.accountClient.GetAccount(ctx, r.AccountId); err != nil {
-		return nil, status.Errorf(codes.NotFound, "account not found: %v", err)
+	order, err := s.placeOrderOnce(ctx, r.AccountId, r.IdempotencyKey, postOrderHash(r.AccountId, items, currency), func() (*Order, error) {
+		return s.placeOrder(ctx, r.AccountId, items, currency, PostOrderOptions{IdempotencyKey: r.IdempotencyKey})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PostOrderResponse{Order: order}, nil
+}
+
+// placeOrderOnce оформляет заказ через place не больше одного раза на непустой ключ key.
+// Повтор выполненного запроса, например после таймаута на стороне клиента, возвращает тот же заказ,
+// не вызывая place. place должен привязать заказ к ключу, а при ошибке ключ освобождается.
+func (s *grpcServer) placeOrderOnce(ctx context.Context, accountID, key, requestHash string, place func() (*Order, error)) (*pb.Order, error) {
+	if key == "" {
+		order, err := place()
+		if err != nil {
+			return nil, err
+		}
+		return toProtoOrder(order)
+	}
+
+	orderID, err := s.service.BeginIdempotentRequest(ctx, accountID, key, requestHash)
+	if err != nil {
+		return nil, err
+	}
+	if orderID != "" {
+		order, err := s.service.GetOrder(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		pbOrders, err := s.hydrateOrders(ctx, []Order{*order})
+		if err != nil {
+			return nil, err
+		}
+		return pbOrders[0], nil
+	}
+
+	// Ключ освобождается, даже если клиент уже отменил запрос
+	order, err := place()
+	if err != nil {
+		if abortErr := s.service.AbortIdempotentRequest(context.WithoutCancel(ctx), accountID, key); abortErr != nil {
+			s.logger.ErrorContext(ctx, "Failed to release idempotency key", "account_id", accountID, "error", abortErr)
+		}
+		return nil, err
+	}
+	return toProtoOrder(order)
+}
+
+// parseCurrency приводит валюту запроса к верхнему регистру и проверяет её; пустая остаётся пустой
+func parseCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(currency)
+	if currency != "" {
+		if err := money.ValidateCurrency(currency); err != nil {
+			return "", fmt.Errorf("%w: %q", err, currency)
+		}
+	}
+	return currency, nil
+}
+
+// authorizeAccount разрешает пользователю, от имени которого пришёл запрос, action только
+// со своим аккаунтом; администратор может действовать за любой
+func authorizeAccount(ctx context.Context, accountID, action string) error {
+	if c, ok := platform.CallerFrom(ctx); ok && c.Subject != accountID && !c.HasRole(string(account.RoleAdmin)) {
+		return status.Errorf(codes.PermissionDenied, "cannot %s for another account", action)
+	}
+	return nil
+}
+
+// authorizeAdmin разрешает action только администратору и внутренним вызовам без пользователя
+func authorizeAdmin(ctx context.Context, action string) error {
+	if c, ok := platform.CallerFrom(ctx); ok && !c.HasRole(string(account.RoleAdmin)) {
+		return status.Errorf(codes.PermissionDenied, "only admin can %s", action)
+	}
+	return nil
+}
+
+// authorizeOrder пропускает владельца заказа orderID и ADMIN, см. authorizeAccount
+func (s *grpcServer) authorizeOrder(ctx context.Context, orderID, action string) error {
+	order, err := s.service.GetOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	return authorizeAccount(ctx, order.AccountID, action)
+}
+
+// placeOrder оформляет заказ на товары items: проверяет аккаунт, берёт цены из каталога,
+// резервирует остатки и сохраняет заказ через service.PostOrder
+func (s *grpcServer) placeOrder(ctx context.Context, accountID string, items []catalog.StockItem, currency string, opts PostOrderOptions) (*Order, error) {
+	// Проверка аккаунта: заказы могут оформлять только активные аккаунты.
+	// Ошибки сервиса аккаунтов (ErrAccountNotFound и т.п.) передаются клиенту как есть.
+	a, err := s.accountClient.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.Active() {
+		return nil, fmt.Errorf("%w: account %s", account.ErrAccountDeactivated, a.ID)
 	}
 
 	// Сбор ID продуктов для запроса
-	productIDs := make([]string, 0, len(r.Products))
-	productMap := make(map[string]uint32, len(r.Products))
-	for _, p := range r.Products {
-		productIDs = append(productIDs, p.ProductId)
-		productMap[p.ProductId] = p.Quantity
+	productIDs := make([]string, 0, len(items))
+	productMap := make(map[string]uint32, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+		productMap[item.ProductID] = item.Quantity
 	}
 
-	// Получение данных о продуктах
-	catalogProducts, err := s.catalogClient.GetProducts(ctx, productIDs, "", 0, 0)
+	// Получение данных о продуктах в базовой валюте каталога, пересчёт делает сервис заказов
+	catalogProducts, err := s.catalogClient.GetProducts(ctx, productIDs, "", 0, 0, "")
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get products: %v", err)
+		return nil, err
 	}
 
 	// Проверка что все продукты найдены
@@ -155,6 +405,9 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 	// Создание списка продуктов для заказа
 	products := make([]OrderedProduct, 0, len(catalogProducts))
 	for _, p := range catalogProducts {
+		if p.Deleted {
+			return nil, status.Errorf(codes.InvalidArgument, "product %s is no longer available", p.ID)
+		}
 		quantity, exists := productMap[p.ID]
 		if !exists || quantity == 0 {
 			continue // этого не должно происходить после предыдущих проверок
@@ -168,24 +421,56 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 		})
 	}
 
+	// Резервирование остатков до сохранения заказа.
+	// Нехватка товара (catalog.ErrOutOfStock с productId) передаётся клиенту без изменений
+	stock := make([]catalog.StockItem, len(products))
+	for i, p := range products {
+		stock[i] = catalog.StockItem{ProductID: p.ID, Quantity: p.Quantity}
+	}
+	reservationID, err := s.catalogClient.ReserveStock(ctx, stock)
+	if err != nil {
+		return nil, err
+	}
+
 	// Создание заказа
-	order, err := s.service.PostOrder(ctx, r.AccountId, products)
+	order, err := s.service.PostOrder(ctx, accountID, products, currency, opts)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create order: %v", err)
+		// Заказ не сохранён - возвращаем товар на склад, даже если клиент уже отменил запрос
+		if releaseErr := s.catalogClient.ReleaseStock(context.WithoutCancel(ctx), reservationID); releaseErr != nil {
+			s.logger.ErrorContext(ctx, "Failed to release stock reservation", "reservation_id", reservationID, "error", releaseErr)
+		}
+		// Разные валюты товаров и неизвестная валюта - ошибки money с кодом InvalidArgument
+		return nil, err
 	}
 
-	// Конвертация в protobuf
+	// Заказ уже сохранён, поэтому ошибка фиксации резерва его не отменяет: резерв останется открытым
+	if err := s.catalogClient.CommitStock(context.WithoutCancel(ctx), reservationID); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to commit stock reservation", "reservation_id", reservationID, "order_id", order.ID, "error", err)
+	}
+	return order, nil
+}
+
+// toProtoOrder конвертирует только что оформленный заказ, у которого уже есть названия товаров
+func toProtoOrder(order *Order) (*pb.Order, error) {
 	createdAtBytes, err := order.CreatedAt.MarshalBinary()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to marshal createdAt: %v", err)
 	}
 
+	statusHistory, err := toProtoStatusHistory(order.StatusHistory)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal status history: %v", err)
+	}
+
 	orderPb := &pb.Order{
-		Id:         order.ID,
-		AccountId:  order.AccountID,
-		TotalPrice: order.TotalPrice,
-		CreatedAt:  createdAtBytes,
-		Products:   make([]*pb.Order_OrderProduct, 0, len(order.Products)),
+		Id:            order.ID,
+		AccountId:     order.AccountID,
+		TotalPrice:    money.ToProto(order.TotalPrice),
+		ExchangeRate:  money.RateToProto(order.ExchangeRate),
+		CreatedAt:     createdAtBytes,
+		Products:      make([]*pb.Order_OrderProduct, 0, len(order.Products)),
+		Status:        toProtoStatus(order.Status),
+		StatusHistory: statusHistory,
 	}
 
 	for _, p := range order.Products {
@@ -193,10 +478,346 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 			Id:          p.ID,
 			Name:        p.Name,
 			Description: p.Description,
-			Price:       p.Price,
+			Price:       money.ToProto(p.Price),
 			Quantity:    p.Quantity,
 		})
 	}
+	return orderPb, nil
+}
+
+func (s *grpcServer) UpdateOrderStatus(ctx context.Context, r *pb.UpdateOrderStatusRequest) (*pb.UpdateOrderStatusResponse, error) {
+	if r.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "orderId is required")
+	}
 
-	return &pb.PostOrderResponse{Order: orderPb}, nil
+	if err := authorizeAdmin(ctx, "change order status"); err != nil {
+		return nil, err
+	}
+
+	history, err := s.service.UpdateOrderStatus(ctx, r.OrderId, fromProtoStatus(r.Status))
+	if err != nil {
+		return nil, err
+	}
+
+	pbHistory, err := toProtoStatusHistory(history)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal status history: %v", err)
+	}
+	return &pb.UpdateOrderStatusResponse{
+		OrderId:       r.OrderId,
+		Status:        toProtoStatus(history[len(history)-1].To),
+		StatusHistory: pbHistory,
+	}, nil
+}
+
+func (s *grpcServer) CancelOrder(ctx context.Context, r *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error) {
+	if r.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "orderId is required")
+	}
+
+	if err := s.authorizeOrder(ctx, r.OrderId, "cancel orders"); err != nil {
+		return nil, err
+	}
+
+	history, err := s.service.CancelOrder(ctx, r.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	pbHistory, err := toProtoStatusHistory(history)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal status history: %v", err)
+	}
+	return &pb.CancelOrderResponse{
+		OrderId:       r.OrderId,
+		Status:        toProtoStatus(history[len(history)-1].To),
+		StatusHistory: pbHistory,
+	}, nil
+}
+
+// WatchOrders отправляет заказ при оформлении и при каждой смене статуса, пока клиент не отпишется.
+// Подписка на orderId сначала получает текущее состояние заказа.
+func (s *grpcServer) WatchOrders(r *pb.WatchOrdersRequest, stream pb.OrderService_WatchOrdersServer) error {
+	if (r.OrderId == "") == (r.AccountId == "") {
+		return status.Error(codes.InvalidArgument, "exactly one of orderId and accountId is required")
+	}
+	ctx := stream.Context()
+	if r.AccountId != "" {
+		if err := authorizeAccount(ctx, r.AccountId, "watch orders"); err != nil {
+			return err
+		}
+	}
+
+	// Подписка создаётся до чтения текущего состояния, чтобы не пропустить изменение между ними
+	watch := s.service.WatchOrders(ctx, r.OrderId, r.AccountId)
+	defer watch.Close()
+
+	send := func(order Order) error {
+		pbOrders, err := s.hydrateOrders(ctx, []Order{order})
+		if err != nil {
+			return err
+		}
+		return stream.Send(&pb.WatchOrdersResponse{Order: pbOrders[0]})
+	}
+	if r.OrderId != "" {
+		order, err := s.service.GetOrder(ctx, r.OrderId)
+		if err != nil {
+			return err
+		}
+		if err := authorizeAccount(ctx, order.AccountID, "watch orders"); err != nil {
+			return err
+		}
+		if err := send(*order); err != nil {
+			return err
+		}
+	} else if err := stream.SendHeader(nil); err != nil {
+		// Заголовки сообщают клиенту, что подписка создана, не дожидаясь первого изменения
+		return err
+	}
+
+	for {
+		select {
+		case order, ok := <-watch.Updates():
+			if !ok {
+				return ErrWatchLagging
+			}
+			if err := send(order); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+	}
+}
+
+func (s *grpcServer) GetCart(ctx context.Context, r *pb.GetCartRequest) (*pb.CartResponse, error) {
+	if r.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "accountId is required")
+	}
+	currency, err := parseCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, r.AccountId, "access the cart"); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.service.GetCart(ctx, r.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	return s.cartResponse(ctx, cart, currency)
+}
+
+func (s *grpcServer) AddToCart(ctx context.Context, r *pb.AddToCartRequest) (*pb.CartResponse, error) {
+	if r.AccountId == "" || r.ProductId == "" {
+		return nil, status.Error(codes.InvalidArgument, "accountId and productId are required")
+	}
+	currency, err := parseCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, r.AccountId, "access the cart"); err != nil {
+		return nil, err
+	}
+
+	// В корзину можно положить только товар, который есть в каталоге и не удалён:
+	// для остальных каталог вернёт ErrProductNotFound, он передаётся клиенту как есть
+	if _, err := s.catalogClient.GetProduct(ctx, r.ProductId, ""); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.service.AddToCart(ctx, r.AccountId, r.ProductId, r.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	return s.cartResponse(ctx, cart, currency)
+}
+
+func (s *grpcServer) UpdateCartItem(ctx context.Context, r *pb.UpdateCartItemRequest) (*pb.CartResponse, error) {
+	if r.AccountId == "" || r.ProductId == "" {
+		return nil, status.Error(codes.InvalidArgument, "accountId and productId are required")
+	}
+	currency, err := parseCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, r.AccountId, "access the cart"); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.service.UpdateCartItem(ctx, r.AccountId, r.ProductId, r.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	return s.cartResponse(ctx, cart, currency)
+}
+
+func (s *grpcServer) RemoveFromCart(ctx context.Context, r *pb.RemoveFromCartRequest) (*pb.CartResponse, error) {
+	if r.AccountId == "" || r.ProductId == "" {
+		return nil, status.Error(codes.InvalidArgument, "accountId and productId are required")
+	}
+	currency, err := parseCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, r.AccountId, "access the cart"); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.service.RemoveFromCart(ctx, r.AccountId, r.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	return s.cartResponse(ctx, cart, currency)
+}
+
+// Checkout оформляет заказ из корзины так же, как PostOrder, и очищает корзину
+func (s *grpcServer) Checkout(ctx context.Context, r *pb.CheckoutRequest) (*pb.CheckoutResponse, error) {
+	if r.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "accountId is required")
+	}
+	currency, err := parseCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, r.AccountId, "place an order"); err != nil {
+		return nil, err
+	}
+
+	order, err := s.placeOrderOnce(ctx, r.AccountId, r.IdempotencyKey, checkoutHash(r.AccountId, currency), func() (*Order, error) {
+		cart, err := s.service.GetCart(ctx, r.AccountId)
+		if err != nil {
+			return nil, err
+		}
+		if len(cart.Items) == 0 {
+			return nil, ErrCartEmpty
+		}
+		items := make([]catalog.StockItem, len(cart.Items))
+		for i, item := range cart.Items {
+			items[i] = catalog.StockItem{ProductID: item.ProductID, Quantity: item.Quantity}
+		}
+		// Из корзины убираются только оформленные товары вместе с заказом,
+		// поэтому добавленное в корзину во время оформления в ней остаётся
+		return s.placeOrder(ctx, r.AccountId, items, currency, PostOrderOptions{IdempotencyKey: r.IdempotencyKey, CartItems: cart.Items})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CheckoutResponse{Order: order}, nil
+}
+
+// cartResponse дополняет корзину названиями товаров и текущими ценами каталога в currency.
+// Удалённые из каталога товары остаются в корзине с available=false, без цены и не входят в сумму.
+func (s *grpcServer) cartResponse(ctx context.Context, cart *Cart, currency string) (*pb.CartResponse, error) {
+	pbCart := &pb.Cart{
+		AccountId: cart.AccountID,
+		Items:     make([]*pb.Cart_Item, 0, len(cart.Items)),
+	}
+	if len(cart.Items) == 0 {
+		return &pb.CartResponse{Cart: pbCart}, nil
+	}
+
+	productIDs := make([]string, len(cart.Items))
+	for i, item := range cart.Items {
+		productIDs[i] = item.ProductID
+	}
+	catalogProducts, err := s.catalogClient.GetProducts(ctx, productIDs, "", 0, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	productsMap := make(map[string]catalog.Product, len(catalogProducts))
+	for _, p := range catalogProducts {
+		productsMap[p.ID] = p
+	}
+
+	// Пересчёт цен доступных товаров тем же курсом, что и при оформлении заказа
+	available := make([]OrderedProduct, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		if p, ok := productsMap[item.ProductID]; ok && !p.Deleted {
+			available = append(available, OrderedProduct{ID: p.ID, Price: p.Price, Quantity: item.Quantity})
+		}
+	}
+	pricesMap := make(map[string]money.Money, len(available))
+	if len(available) > 0 {
+		priced, total, err := s.service.PriceProducts(ctx, available, currency)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range priced {
+			pricesMap[p.ID] = p.Price
+		}
+		pbCart.TotalPrice = money.ToProto(total)
+	}
+
+	for _, item := range cart.Items {
+		pbItem := &pb.Cart_Item{ProductId: item.ProductID, Quantity: item.Quantity}
+		if p, ok := productsMap[item.ProductID]; ok {
+			pbItem.Name = p.Name
+			pbItem.Description = p.Description
+		}
+		if price, ok := pricesMap[item.ProductID]; ok {
+			pbItem.Available = true
+			pbItem.Price = money.ToProto(price)
+			pbItem.Subtotal = money.ToProto(price.Mul(int64(item.Quantity)))
+		}
+		pbCart.Items = append(pbCart.Items, pbItem)
+	}
+	return &pb.CartResponse{Cart: pbCart}, nil
+}
+
+var protoStatuses = map[OrderStatus]pb.OrderStatus{
+	StatusPending:   pb.OrderStatus_ORDER_STATUS_PENDING,
+	StatusPaid:      pb.OrderStatus_ORDER_STATUS_PAID,
+	StatusShipped:   pb.OrderStatus_ORDER_STATUS_SHIPPED,
+	StatusDelivered: pb.OrderStatus_ORDER_STATUS_DELIVERED,
+	StatusCancelled: pb.OrderStatus_ORDER_STATUS_CANCELLED,
+	StatusRefunded:  pb.OrderStatus_ORDER_STATUS_REFUNDED,
+}
+
+func toProtoStatus(s OrderStatus) pb.OrderStatus {
+	return protoStatuses[s]
+}
+
+func fromProtoStatus(s pb.OrderStatus) OrderStatus {
+	for status, p := range protoStatuses {
+		if p == s {
+			return status
+		}
+	}
+	return ""
+}
+
+func toProtoStatusHistory(history []StatusChange) ([]*pb.OrderStatusChange, error) {
+	pbHistory := make([]*pb.OrderStatusChange, 0, len(history))
+	for _, c := range history {
+		changedAt, err := c.ChangedAt.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		pbHistory = append(pbHistory, &pb.OrderStatusChange{
+			From:      toProtoStatus(c.From),
+			To:        toProtoStatus(c.To),
+			ChangedAt: changedAt,
+		})
+	}
+	return pbHistory, nil
+}
+
+func fromProtoStatusHistory(pbHistory []*pb.OrderStatusChange) ([]StatusChange, error) {
+	history := make([]StatusChange, 0, len(pbHistory))
+	for _, c := range pbHistory {
+		var changedAt time.Time
+		if err := changedAt.UnmarshalBinary(c.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, StatusChange{
+			From:      fromProtoStatus(c.From),
+			To:        fromProtoStatus(c.To),
+			ChangedAt: changedAt,
+		})
+	}
+	return history, nil
 }
@@ -0,0 +1,254 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-microservice/account"
+	"go-microservice/catalog"
+	"go-microservice/money"
+	"go-microservice/order/pb"
+	"go-microservice/platform"
+	"sync"
+	"testing"
+
+	"github.com/segmentio/ksuid"
+)
+
+type fakeAccounts struct{}
+
+func (fakeAccounts) GetAccount(ctx context.Context, id string) (*account.Account, error) {
+	return &account.Account{ID: id, Status: account.StatusActive, Role: account.RoleCustomer}, nil
+}
+
+// fakeCatalog хранит товары в памяти и запоминает резервы
+type fakeCatalog struct {
+	mu        sync.Mutex
+	products  map[string]catalog.Product
+	reserved  []string
+	released  []string
+	committed []string
+	// onReserve вызывается при каждом резервировании
+	onReserve func()
+}
+
+func newFakeCatalog(products ...catalog.Product) *fakeCatalog {
+	c := &fakeCatalog{products: make(map[string]catalog.Product, len(products))}
+	for _, p := range products {
+		c.products[p.ID] = p
+	}
+	return c
+}
+
+func (c *fakeCatalog) GetProduct(ctx context.Context, id, currency string) (*catalog.Product, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.products[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrProductNotFound, id)
+	}
+	return &p, nil
+}
+
+func (c *fakeCatalog) GetProducts(ctx context.Context, ids []string, query string, skip, take uint64, currency string) ([]catalog.Product, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	products := make([]catalog.Product, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := c.products[id]; ok {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+func (c *fakeCatalog) ReserveStock(ctx context.Context, items []catalog.StockItem) (string, error) {
+	if c.onReserve != nil {
+		c.onReserve()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := ksuid.New().String()
+	c.reserved = append(c.reserved, id)
+	return id, nil
+}
+
+func (c *fakeCatalog) ReleaseStock(ctx context.Context, reservationID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.released = append(c.released, reservationID)
+	return nil
+}
+
+func (c *fakeCatalog) CommitStock(ctx context.Context, reservationID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.committed = append(c.committed, reservationID)
+	return nil
+}
+
+// setPrice меняет цену товара в каталоге
+func (c *fakeCatalog) setPrice(id string, price money.Money) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := c.products[id]
+	p.Price = price
+	c.products[id] = p
+}
+
+func newTestServer(t *testing.T, c *fakeCatalog) *grpcServer {
+	t.Helper()
+	return &grpcServer{
+		service:       newTestService(t),
+		accountClient: fakeAccounts{},
+		catalogClient: c,
+		logger:        platform.DiscardLogger(),
+		stopping:      make(chan struct{}),
+	}
+}
+
+func testProduct(price money.Money) catalog.Product {
+	return catalog.Product{ID: ksuid.New().String(), Name: "Keyboard", Price: price, Stock: 100}
+}
+
+func postOrderRequest(accountID, key string, products ...catalog.Product) *pb.PostOrderRequest {
+	r := &pb.PostOrderRequest{AccountId: accountID, IdempotencyKey: key}
+	for _, p := range products {
+		r.Products = append(r.Products, &pb.PostOrderRequest_OrderProduct{ProductId: p.ID, Quantity: 1})
+	}
+	return r
+}
+
+func TestPostOrderReleasesReservationOnFailure(t *testing.T) {
+	usd, eur := testProduct(money.New(100, "USD")), testProduct(money.New(100, "EUR"))
+	c := newFakeCatalog(usd, eur)
+	s := newTestServer(t, c)
+
+	// Заказ с товарами в разных валютах не сохраняется уже после резервирования
+	_, err := s.PostOrder(context.Background(), postOrderRequest(ksuid.New().String(), "", usd, eur))
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("PostOrder error = %v, want %v", err, money.ErrCurrencyMismatch)
+	}
+	if len(c.reserved) != 1 || len(c.released) != 1 || c.released[0] != c.reserved[0] || len(c.committed) != 0 {
+		t.Errorf("reserved %v, released %v, committed %v, want reservation released", c.reserved, c.released, c.committed)
+	}
+}
+
+func TestPostOrderIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	p, other := testProduct(money.New(100, "USD")), testProduct(money.New(100, "USD"))
+	c := newFakeCatalog(p, other)
+	s := newTestServer(t, c)
+	accountID := ksuid.New().String()
+
+	first, err := s.PostOrder(ctx, postOrderRequest(accountID, "key", p))
+	if err != nil {
+		t.Fatalf("PostOrder: %v", err)
+	}
+	replay, err := s.PostOrder(ctx, postOrderRequest(accountID, "key", p))
+	if err != nil {
+		t.Fatalf("PostOrder replay: %v", err)
+	}
+	if replay.Order.Id != first.Order.Id || len(c.reserved) != 1 {
+		t.Errorf("replay = order %s after %d reservations, want order %s after 1", replay.Order.Id, len(c.reserved), first.Order.Id)
+	}
+
+	if _, err := s.PostOrder(ctx, postOrderRequest(accountID, "key", other)); !errors.Is(err, ErrIdempotencyKeyConflict) {
+		t.Errorf("PostOrder with other products error = %v, want %v", err, ErrIdempotencyKeyConflict)
+	}
+}
+
+func TestPostOrderIdempotencyKeyInProgress(t *testing.T) {
+	ctx := context.Background()
+	p := testProduct(money.New(100, "USD"))
+	c := newFakeCatalog(p)
+	s := newTestServer(t, c)
+	accountID := ksuid.New().String()
+
+	// Ключ занят запросом, который ещё оформляет заказ
+	hash := postOrderHash(accountID, []catalog.StockItem{{ProductID: p.ID, Quantity: 1}}, "")
+	if _, err := s.service.BeginIdempotentRequest(ctx, accountID, "key", hash); err != nil {
+		t.Fatalf("BeginIdempotentRequest: %v", err)
+	}
+	if _, err := s.PostOrder(ctx, postOrderRequest(accountID, "key", p)); !errors.Is(err, ErrIdempotencyKeyInProgress) {
+		t.Errorf("PostOrder error = %v, want %v", err, ErrIdempotencyKeyInProgress)
+	}
+	if len(c.reserved) != 0 {
+		t.Errorf("reserved %v, want no reservations", c.reserved)
+	}
+}
+
+func TestPostOrderReleasesIdempotencyKeyOnFailure(t *testing.T) {
+	ctx := context.Background()
+	usd, eur := testProduct(money.New(100, "USD")), testProduct(money.New(100, "EUR"))
+	c := newFakeCatalog(usd, eur)
+	s := newTestServer(t, c)
+	accountID := ksuid.New().String()
+
+	if _, err := s.PostOrder(ctx, postOrderRequest(accountID, "key", usd, eur)); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("PostOrder error = %v, want %v", err, money.ErrCurrencyMismatch)
+	}
+
+	// После исправления цены повтор с тем же ключом оформляет заказ
+	c.setPrice(eur.ID, money.New(100, "USD"))
+	res, err := s.PostOrder(ctx, postOrderRequest(accountID, "key", usd, eur))
+	if err != nil {
+		t.Fatalf("PostOrder retry: %v", err)
+	}
+	if len(res.Order.Products) != 2 {
+		t.Errorf("products = %d, want 2", len(res.Order.Products))
+	}
+}
+
+func TestCheckoutRemovesCheckedOutItems(t *testing.T) {
+	ctx := context.Background()
+	keyboard, mouse := testProduct(money.New(100, "USD")), testProduct(money.New(50, "USD"))
+	c := newFakeCatalog(keyboard, mouse)
+	s := newTestServer(t, c)
+	accountID := ksuid.New().String()
+
+	if _, err := s.service.AddToCart(ctx, accountID, keyboard.ID, 2); err != nil {
+		t.Fatalf("AddToCart: %v", err)
+	}
+	// Товары, добавленные во время оформления, в заказ не попадают и остаются в корзине
+	c.onReserve = func() {
+		if _, err := s.service.AddToCart(ctx, accountID, keyboard.ID, 1); err != nil {
+			t.Errorf("AddToCart: %v", err)
+		}
+		if _, err := s.service.AddToCart(ctx, accountID, mouse.ID, 1); err != nil {
+			t.Errorf("AddToCart: %v", err)
+		}
+	}
+
+	res, err := s.Checkout(ctx, &pb.CheckoutRequest{AccountId: accountID, IdempotencyKey: "key"})
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if len(res.Order.Products) != 1 || res.Order.Products[0].Quantity != 2 {
+		t.Errorf("products = %v, want %s x2", res.Order.Products, keyboard.ID)
+	}
+
+	cart, err := s.service.GetCart(ctx, accountID)
+	if err != nil {
+		t.Fatalf("GetCart: %v", err)
+	}
+	if len(cart.Items) != 2 || cart.Items[0].ProductID != keyboard.ID || cart.Items[0].Quantity != 1 || cart.Items[1].ProductID != mouse.ID {
+		t.Errorf("cart = %+v, want %s x1 and %s x1", cart.Items, keyboard.ID, mouse.ID)
+	}
+
+	// Повтор с тем же ключом возвращает заказ, хотя корзина уже другая
+	c.onReserve = nil
+	replay, err := s.Checkout(ctx, &pb.CheckoutRequest{AccountId: accountID, IdempotencyKey: "key"})
+	if err != nil {
+		t.Fatalf("Checkout replay: %v", err)
+	}
+	if replay.Order.Id != res.Order.Id || len(c.reserved) != 1 {
+		t.Errorf("replay = order %s after %d reservations, want order %s after 1", replay.Order.Id, len(c.reserved), res.Order.Id)
+	}
+}
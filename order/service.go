@@ -2,63 +2,340 @@ package order
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	eventspb "go-microservice/events/pb"
+	"go-microservice/money"
+	"go-microservice/platform"
+	"log/slog"
 	"time"
 
 	"github.com/segmentio/ksuid"
 )
 
+var (
+	ErrOrderNotFound           = platform.NewError(platform.NotFound, "ORDER_NOT_FOUND", "order not found")
+	ErrInvalidStatus           = platform.NewError(platform.InvalidArgument, "INVALID_ORDER_STATUS", "invalid order status")
+	ErrInvalidStatusTransition = platform.NewError(platform.FailedPrecondition, "INVALID_STATUS_TRANSITION", "invalid order status transition")
+	ErrStatusConflict          = platform.NewError(platform.Conflict, "ORDER_STATUS_CONFLICT", "order status was changed concurrently")
+	ErrInvalidCursor           = platform.NewError(platform.InvalidArgument, "INVALID_CURSOR", "invalid cursor")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type Service interface {
-	PostOrder(ctx context.Context, accountID string, products []OrderedProduct) (*Order, error)
+	PostOrder(ctx context.Context, accountID string, products []OrderedProduct, currency string, opts PostOrderOptions) (*Order, error)
 	GetOrdersForAccount(ctx context.Context, accountID string) ([]Order, error)
+	GetOrdersForAccounts(ctx context.Context, accountIDs []string) ([]Order, error)
+	GetOrdersPageForAccount(ctx context.Context, accountID, after string, first uint64) (*OrderPage, error)
+	GetOrder(ctx context.Context, id string) (*Order, error)
+	UpdateOrderStatus(ctx context.Context, orderID string, status OrderStatus) ([]StatusChange, error)
+	CancelOrder(ctx context.Context, orderID string) ([]StatusChange, error)
+	PriceProducts(ctx context.Context, products []OrderedProduct, currency string) ([]OrderedProduct, money.Money, error)
+
+	// Корзина
+	GetCart(ctx context.Context, accountID string) (*Cart, error)
+	AddToCart(ctx context.Context, accountID, productID string, quantity uint32) (*Cart, error)
+	UpdateCartItem(ctx context.Context, accountID, productID string, quantity uint32) (*Cart, error)
+	RemoveFromCart(ctx context.Context, accountID, productID string) (*Cart, error)
+
+	// Идемпотентность PostOrder и Checkout
+	BeginIdempotentRequest(ctx context.Context, accountID, key, requestHash string) (string, error)
+	AbortIdempotentRequest(ctx context.Context, accountID, key string) error
+
+	// Подписка на изменения заказов
+	WatchOrders(ctx context.Context, orderID, accountID string) *OrderWatch
+}
+
+type OrderEdge struct {
+	Order Order
+	// Cursor - непрозрачная позиция заказа в выдаче, передаётся в after для следующей страницы
+	Cursor string
+}
+
+// OrderPage - страница заказов аккаунта, от старых к новым
+type OrderPage struct {
+	Edges       []OrderEdge
+	HasNextPage bool
+}
+
+type OrderStatus string
+
+const (
+	StatusPending   OrderStatus = "pending"
+	StatusPaid      OrderStatus = "paid"
+	StatusShipped   OrderStatus = "shipped"
+	StatusDelivered OrderStatus = "delivered"
+	StatusCancelled OrderStatus = "cancelled"
+	StatusRefunded  OrderStatus = "refunded"
+)
+
+// statusTransitions описывает допустимые переходы между статусами заказа.
+// Статусы без исходящих переходов являются конечными.
+var statusTransitions = map[OrderStatus][]OrderStatus{
+	StatusPending:   {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusShipped, StatusCancelled, StatusRefunded},
+	StatusShipped:   {StatusDelivered},
+	StatusDelivered: {StatusRefunded},
+	StatusCancelled: {},
+	StatusRefunded:  {},
+}
+
+func (s OrderStatus) Valid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
 }
 
 type Order struct {
-	ID         string           `json:"id"`
-	AccountID  string           `json:"account_id"`
-	CreatedAt  time.Time        `json:"created_at"`
-	TotalPrice float64          `json:"total_price"`
-	Products   []OrderedProduct `json:"products"`
+	ID            string           `json:"id"`
+	AccountID     string           `json:"account_id"`
+	CreatedAt     time.Time        `json:"created_at"`
+	TotalPrice    money.Money      `json:"total_price"`
+	Products      []OrderedProduct `json:"products"`
+	Status        OrderStatus      `json:"status"`
+	StatusHistory []StatusChange   `json:"status_history"`
+	// ExchangeRate - курс, по которому цены каталога были пересчитаны в валюту заказа при оформлении
+	ExchangeRate money.Rate `json:"exchange_rate"`
 }
 
 type OrderedProduct struct {
 	ID          string
 	Name        string
 	Description string
-	Price       float64
+	Price       money.Money
 	Quantity    uint32
 }
 
+// StatusChange - запись в истории статусов заказа.
+// У первой записи From пустой: заказ создаётся сразу в статусе pending.
+type StatusChange struct {
+	From      OrderStatus `json:"from"`
+	To        OrderStatus `json:"to"`
+	ChangedAt time.Time   `json:"changed_at"`
+}
+
 type orderService struct {
 	repository Repository
+	rates      money.ExchangeRateProvider
+	logger     *slog.Logger
+	watches    *watchHub
 }
 
-func NewService(r Repository) Service {
+func NewService(r Repository, rates money.ExchangeRateProvider, logger *slog.Logger) Service {
 	return &orderService{
 		repository: r,
+		rates:      rates,
+		logger:     logger,
+		watches:    newWatchHub(),
 	}
 }
 
-func (s orderService) PostOrder(ctx context.Context, accountID string, products []OrderedProduct) (*Order, error) {
-	totalPrice := 0.0
-	for _, v := range products {
-		totalPrice += v.Price * float64(v.Quantity)
+// PostOrderOptions - необязательные параметры PostOrder
+type PostOrderOptions struct {
+	// IdempotencyKey - ключ, занятый через BeginIdempotentRequest. Заказ привязывается к нему в той же
+	// транзакции, в которой сохраняется, поэтому повтор не создаст второй заказ и после сбоя.
+	IdempotencyKey string
+	// CartItems - товары корзины, из которых оформлен заказ. В той же транзакции они
+	// убираются из корзины, а товары, добавленные во время оформления, остаются.
+	CartItems []CartItem
+}
+
+// PostOrder оформляет заказ в валюте currency. Цены товаров приходят в валюте каталога
+// и пересчитываются по текущему курсу; пустая currency оставляет валюту каталога.
+func (s orderService) PostOrder(ctx context.Context, accountID string, products []OrderedProduct, currency string, opts PostOrderOptions) (*Order, error) {
+	converted, totalPrice, rate, err := s.convertPrices(ctx, products, currency)
+	if err != nil {
+		return nil, err
 	}
 
+	createdAt := time.Now()
 	order := Order{
-		ID:         ksuid.New().String(),
-		AccountID:  accountID,
-		CreatedAt:  time.Now(),
-		Products:   products,
-		TotalPrice: totalPrice,
+		ID:           ksuid.New().String(),
+		AccountID:    accountID,
+		CreatedAt:    createdAt,
+		Products:     converted,
+		TotalPrice:   totalPrice,
+		ExchangeRate: rate,
+		Status:       StatusPending,
+		StatusHistory: []StatusChange{
+			{To: StatusPending, ChangedAt: createdAt},
+		},
 	}
 
-	err := s.repository.PutOrder(ctx, order)
+	event, err := platform.NewEvent(order.ID, orderCreatedEvent(order))
 	if err != nil {
 		return nil, err
 	}
+	putOpts := PutOrderOptions{CartItems: opts.CartItems}
+	if opts.IdempotencyKey != "" {
+		putOpts.IdempotencyKey = &IdempotencyKey{
+			AccountID: accountID,
+			Key:       opts.IdempotencyKey,
+			OrderID:   order.ID,
+			ExpiresAt: createdAt.Add(idempotencyKeyTTL),
+		}
+	}
+	if err := s.repository.PutOrder(ctx, order, putOpts, event); err != nil {
+		return nil, err
+	}
+	observeOrderCreated(order)
+	s.logger.InfoContext(ctx, "Order created", "order_id", order.ID, "account_id", accountID, "total", order.TotalPrice.String())
+	s.watches.publish(order)
 
 	return &order, nil
 }
+
+func orderCreatedEvent(o Order) *eventspb.OrderCreated {
+	e := &eventspb.OrderCreated{
+		OrderId:    o.ID,
+		AccountId:  o.AccountID,
+		Products:   make([]*eventspb.OrderCreated_Product, len(o.Products)),
+		TotalPrice: money.ToProto(o.TotalPrice),
+	}
+	for i, p := range o.Products {
+		e.Products[i] = &eventspb.OrderCreated_Product{ProductId: p.ID, Quantity: p.Quantity, Price: money.ToProto(p.Price)}
+	}
+	return e
+}
+
+// convertPrices пересчитывает цены товаров в currency по текущему курсу и считает сумму
+func (s orderService) convertPrices(ctx context.Context, products []OrderedProduct, currency string) ([]OrderedProduct, money.Money, money.Rate, error) {
+	// Все товары должны быть в одной валюте
+	var baseCurrency string
+	if len(products) > 0 {
+		baseCurrency = products[0].Price.Currency
+	}
+	if currency == "" {
+		currency = baseCurrency
+	}
+
+	rate, err := s.rates.Rate(ctx, baseCurrency, currency)
+	if err != nil {
+		return nil, money.Money{}, money.Rate{}, err
+	}
+
+	converted := make([]OrderedProduct, len(products))
+	total := money.New(0, currency)
+	for i, v := range products {
+		if v.Price, err = money.Convert(v.Price, rate); err != nil {
+			return nil, money.Money{}, money.Rate{}, err
+		}
+		if total, err = total.Add(v.Price.Mul(int64(v.Quantity))); err != nil {
+			return nil, money.Money{}, money.Rate{}, err
+		}
+		converted[i] = v
+	}
+	return converted, total, rate, nil
+}
+
 func (s orderService) GetOrdersForAccount(ctx context.Context, accountID string) ([]Order, error) {
 	return s.repository.GetOrdersForAccount(ctx, accountID)
 }
+
+// GetOrdersForAccounts возвращает заказы сразу нескольких аккаунтов одним запросом к хранилищу
+func (s orderService) GetOrdersForAccounts(ctx context.Context, accountIDs []string) ([]Order, error) {
+	if len(accountIDs) == 0 {
+		return []Order{}, nil
+	}
+	return s.repository.GetOrdersForAccounts(ctx, accountIDs)
+}
+
+// GetOrdersPageForAccount возвращает first заказов аккаунта после курсора after (пустой after - с начала)
+func (s orderService) GetOrdersPageForAccount(ctx context.Context, accountID, after string, first uint64) (*OrderPage, error) {
+	afterID, err := decodeCursor(after)
+	if err != nil {
+		return nil, err
+	}
+	if first == 0 {
+		first = defaultPageSize
+	}
+	first = min(first, maxPageSize)
+
+	// Лишний заказ показывает, есть ли следующая страница
+	orders, err := s.repository.GetOrdersForAccountAfter(ctx, accountID, afterID, first+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &OrderPage{HasNextPage: uint64(len(orders)) > first}
+	if page.HasNextPage {
+		orders = orders[:first]
+	}
+	page.Edges = make([]OrderEdge, len(orders))
+	for i, o := range orders {
+		page.Edges[i] = OrderEdge{Order: o, Cursor: encodeCursor(o.ID)}
+	}
+	return page, nil
+}
+
+func (s orderService) GetOrder(ctx context.Context, id string) (*Order, error) {
+	return s.repository.GetOrderByID(ctx, id)
+}
+
+func (s orderService) UpdateOrderStatus(ctx context.Context, orderID string, status OrderStatus) ([]StatusChange, error) {
+	if !status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+
+	current, err := s.repository.GetOrderStatus(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !current.CanTransitionTo(status) {
+		return nil, ErrInvalidStatusTransition
+	}
+
+	change := StatusChange{
+		From:      current,
+		To:        status,
+		ChangedAt: time.Now(),
+	}
+	event, err := platform.NewEvent(orderID, &eventspb.OrderStatusChanged{
+		OrderId: orderID,
+		From:    string(change.From),
+		To:      string(change.To),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.UpdateOrderStatus(ctx, orderID, change, event); err != nil {
+		return nil, err
+	}
+	orderStatusChanges.WithLabelValues(string(status)).Inc()
+	s.logger.InfoContext(ctx, "Order status changed", "order_id", orderID, "from", current, "to", status)
+	s.notifyOrderChanged(ctx, orderID)
+
+	return s.repository.GetStatusHistory(ctx, orderID)
+}
+
+// CancelOrder отменяет заказ. Списанные при оформлении остатки в каталог не возвращаются.
+func (s orderService) CancelOrder(ctx context.Context, orderID string) ([]StatusChange, error) {
+	return s.UpdateOrderStatus(ctx, orderID, StatusCancelled)
+}
+
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(id) == 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	return string(id), nil
+}
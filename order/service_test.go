@@ -0,0 +1,399 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"go-microservice/catalog"
+	eventspb "go-microservice/events/pb"
+	"go-microservice/money"
+	"go-microservice/platform"
+	"math"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestService(t *testing.T) Service {
+	t.Helper()
+	rates, err := money.NewStaticRateProvider("USD", map[string]string{"EUR": "0.9", "JPY": "150"})
+	if err != nil {
+		t.Fatalf("NewStaticRateProvider: %v", err)
+	}
+	return NewService(NewInMemoryRepository(), rates, platform.DiscardLogger())
+}
+
+func TestUpdateOrderStatusTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []OrderStatus
+		next    OrderStatus
+		wantErr error
+	}{
+		{name: "pay", next: StatusPaid},
+		{name: "cancel pending", next: StatusCancelled},
+		{name: "ship unpaid", next: StatusShipped, wantErr: ErrInvalidStatusTransition},
+		{name: "ship paid", path: []OrderStatus{StatusPaid}, next: StatusShipped},
+		{name: "deliver shipped", path: []OrderStatus{StatusPaid, StatusShipped}, next: StatusDelivered},
+		{name: "cancel shipped", path: []OrderStatus{StatusPaid, StatusShipped}, next: StatusCancelled, wantErr: ErrInvalidStatusTransition},
+		{name: "refund delivered", path: []OrderStatus{StatusPaid, StatusShipped, StatusDelivered}, next: StatusRefunded},
+		{name: "reopen cancelled", path: []OrderStatus{StatusCancelled}, next: StatusPending, wantErr: ErrInvalidStatusTransition},
+		{name: "same status", next: StatusPending, wantErr: ErrInvalidStatusTransition},
+		{name: "unknown status", next: OrderStatus("lost"), wantErr: ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestService(t)
+
+			o, err := s.PostOrder(ctx, ksuid.New().String(), []OrderedProduct{{ID: ksuid.New().String(), Price: money.New(100, "USD"), Quantity: 1}}, "", PostOrderOptions{})
+			if err != nil {
+				t.Fatalf("PostOrder: %v", err)
+			}
+			for _, status := range tt.path {
+				if _, err := s.UpdateOrderStatus(ctx, o.ID, status); err != nil {
+					t.Fatalf("UpdateOrderStatus(%s): %v", status, err)
+				}
+			}
+
+			history, err := s.UpdateOrderStatus(ctx, o.ID, tt.next)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateOrderStatus(%s) error = %v, want %v", tt.next, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(history) != len(tt.path)+2 {
+				t.Fatalf("history has %d entries, want %d", len(history), len(tt.path)+2)
+			}
+			if last := history[len(history)-1]; last.To != tt.next {
+				t.Errorf("last status = %s, want %s", last.To, tt.next)
+			}
+		})
+	}
+}
+
+func TestPostOrderTotal(t *testing.T) {
+	s := newTestService(t)
+
+	o, err := s.PostOrder(context.Background(), ksuid.New().String(), []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(10, "USD"), Quantity: 1},
+		{ID: ksuid.New().String(), Price: money.New(20, "USD"), Quantity: 1},
+		{ID: ksuid.New().String(), Price: money.New(1999, "USD"), Quantity: 3},
+	}, "", PostOrderOptions{})
+	if err != nil {
+		t.Fatalf("PostOrder: %v", err)
+	}
+	if want := money.New(6027, "USD"); o.TotalPrice != want {
+		t.Errorf("TotalPrice = %s, want %s", o.TotalPrice, want)
+	}
+
+	_, err = s.PostOrder(context.Background(), ksuid.New().String(), []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(100, "USD"), Quantity: 1},
+		{ID: ksuid.New().String(), Price: money.New(100, "EUR"), Quantity: 1},
+	}, "", PostOrderOptions{})
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("PostOrder with mixed currencies error = %v, want %v", err, money.ErrCurrencyMismatch)
+	}
+}
+
+func TestPostOrderRecordsEvent(t *testing.T) {
+	rates, err := money.NewStaticRateProvider("USD", nil)
+	if err != nil {
+		t.Fatalf("NewStaticRateProvider: %v", err)
+	}
+	r := NewInMemoryRepository()
+	s := NewService(r, rates, platform.DiscardLogger())
+
+	o, err := s.PostOrder(context.Background(), ksuid.New().String(), []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(1999, "USD"), Quantity: 2},
+	}, "", PostOrderOptions{})
+	if err != nil {
+		t.Fatalf("PostOrder: %v", err)
+	}
+
+	events, err := r.PendingEvents(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("PendingEvents: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	var got eventspb.OrderCreated
+	if err := proto.Unmarshal(events[0].Payload, &got); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if events[0].Type != "events.v1.OrderCreated" || events[0].AggregateID != o.ID || got.OrderId != o.ID || got.TotalPrice.GetAmount() != 3998 {
+		t.Errorf("event = %+v with payload %v, want OrderCreated for %s", events[0], &got, o.ID)
+	}
+}
+
+func TestUpdateOrderStatusRecordsEvent(t *testing.T) {
+	rates, err := money.NewStaticRateProvider("USD", nil)
+	if err != nil {
+		t.Fatalf("NewStaticRateProvider: %v", err)
+	}
+	r := NewInMemoryRepository()
+	s := NewService(r, rates, platform.DiscardLogger())
+	ctx := context.Background()
+
+	o, err := s.PostOrder(ctx, ksuid.New().String(), []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(1999, "USD"), Quantity: 1},
+	}, "", PostOrderOptions{})
+	if err != nil {
+		t.Fatalf("PostOrder: %v", err)
+	}
+	if _, err := s.UpdateOrderStatus(ctx, o.ID, StatusPaid); err != nil {
+		t.Fatalf("UpdateOrderStatus: %v", err)
+	}
+
+	events, err := r.PendingEvents(ctx, 10)
+	if err != nil {
+		t.Fatalf("PendingEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	var got eventspb.OrderStatusChanged
+	if err := proto.Unmarshal(events[1].Payload, &got); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if events[1].Type != "events.v1.OrderStatusChanged" || events[1].AggregateID != o.ID || got.OrderId != o.ID || got.From != "pending" || got.To != "paid" {
+		t.Errorf("event = %+v with payload %v, want OrderStatusChanged pending -> paid for %s", events[1], &got, o.ID)
+	}
+}
+
+func TestPostOrderConvertsCurrency(t *testing.T) {
+	s := newTestService(t)
+
+	o, err := s.PostOrder(context.Background(), ksuid.New().String(), []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(1999, "USD"), Quantity: 2},
+		{ID: ksuid.New().String(), Price: money.New(5, "USD"), Quantity: 1},
+	}, "EUR", PostOrderOptions{})
+	if err != nil {
+		t.Fatalf("PostOrder: %v", err)
+	}
+	// 19.99 * 0.9 = 17.991 -> 17.99, 0.05 * 0.9 = 0.045 -> 0.05
+	if o.Products[0].Price != money.New(1799, "EUR") || o.Products[1].Price != money.New(5, "EUR") {
+		t.Errorf("prices = %s, %s, want 17.99 EUR, 0.05 EUR", o.Products[0].Price, o.Products[1].Price)
+	}
+	if want := money.New(3603, "EUR"); o.TotalPrice != want {
+		t.Errorf("TotalPrice = %s, want %s", o.TotalPrice, want)
+	}
+	if want := (money.Rate{From: "USD", To: "EUR", Value: "0.9"}); o.ExchangeRate != want {
+		t.Errorf("ExchangeRate = %+v, want %+v", o.ExchangeRate, want)
+	}
+
+	_, err = s.PostOrder(context.Background(), ksuid.New().String(), []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(100, "USD"), Quantity: 1},
+	}, "GBP", PostOrderOptions{})
+	if !errors.Is(err, money.ErrRateNotFound) {
+		t.Errorf("PostOrder in unknown currency error = %v, want %v", err, money.ErrRateNotFound)
+	}
+}
+
+func TestCancelMissingOrder(t *testing.T) {
+	s := newTestService(t)
+	if _, err := s.CancelOrder(context.Background(), ksuid.New().String()); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("CancelOrder error = %v, want %v", err, ErrOrderNotFound)
+	}
+}
+
+func TestPostOrderMetrics(t *testing.T) {
+	s := newTestService(t)
+	created := testutil.ToFloat64(ordersCreated.WithLabelValues("JPY"))
+	value := testutil.ToFloat64(ordersValue.WithLabelValues("JPY"))
+	paid := testutil.ToFloat64(orderStatusChanges.WithLabelValues(string(StatusPaid)))
+
+	// 10.00 USD = 1500 JPY, у иены нет дробных единиц
+	o, err := s.PostOrder(context.Background(), ksuid.New().String(), []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(1000, "USD"), Quantity: 1},
+	}, "JPY", PostOrderOptions{})
+	if err != nil {
+		t.Fatalf("PostOrder: %v", err)
+	}
+	if _, err := s.UpdateOrderStatus(context.Background(), o.ID, StatusPaid); err != nil {
+		t.Fatalf("UpdateOrderStatus: %v", err)
+	}
+
+	if got := testutil.ToFloat64(ordersCreated.WithLabelValues("JPY")) - created; got != 1 {
+		t.Errorf("orders_created_total grew by %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(ordersValue.WithLabelValues("JPY")) - value; got != 1500 {
+		t.Errorf("orders_value_total grew by %v, want 1500", got)
+	}
+	if got := testutil.ToFloat64(orderStatusChanges.WithLabelValues(string(StatusPaid))) - paid; got != 1 {
+		t.Errorf("order_status_changes_total{status=paid} grew by %v, want 1", got)
+	}
+}
+
+func TestCart(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	accountID, productID := ksuid.New().String(), ksuid.New().String()
+
+	if _, err := s.AddToCart(ctx, accountID, productID, 2); err != nil {
+		t.Fatalf("AddToCart: %v", err)
+	}
+	cart, err := s.AddToCart(ctx, accountID, productID, 3)
+	if err != nil {
+		t.Fatalf("AddToCart: %v", err)
+	}
+	if len(cart.Items) != 1 || cart.Items[0].Quantity != 5 {
+		t.Fatalf("items = %+v, want one product x5", cart.Items)
+	}
+
+	if _, err := s.AddToCart(ctx, accountID, productID, 0); !errors.Is(err, ErrInvalidQuantity) {
+		t.Errorf("AddToCart(0) error = %v, want %v", err, ErrInvalidQuantity)
+	}
+	// 5 + (MaxUint32 - 3) переполняет uint32 и дало бы 1
+	if _, err := s.AddToCart(ctx, accountID, productID, math.MaxUint32-3); !errors.Is(err, ErrInvalidQuantity) {
+		t.Errorf("AddToCart overflowing quantity error = %v, want %v", err, ErrInvalidQuantity)
+	}
+	if _, err := s.UpdateCartItem(ctx, accountID, productID, maxCartQuantity+1); !errors.Is(err, ErrInvalidQuantity) {
+		t.Errorf("UpdateCartItem over limit error = %v, want %v", err, ErrInvalidQuantity)
+	}
+	if _, err := s.UpdateCartItem(ctx, accountID, ksuid.New().String(), 1); !errors.Is(err, ErrCartItemNotFound) {
+		t.Errorf("UpdateCartItem of missing product error = %v, want %v", err, ErrCartItemNotFound)
+	}
+
+	if cart, err = s.UpdateCartItem(ctx, accountID, productID, 1); err != nil || cart.Items[0].Quantity != 1 {
+		t.Fatalf("UpdateCartItem = %+v, %v, want quantity 1", cart, err)
+	}
+	if cart, err = s.UpdateCartItem(ctx, accountID, productID, 0); err != nil || len(cart.Items) != 0 {
+		t.Errorf("UpdateCartItem(0) = %+v, %v, want empty cart", cart, err)
+	}
+}
+
+func TestPriceProducts(t *testing.T) {
+	s := newTestService(t)
+
+	products, total, err := s.PriceProducts(context.Background(), []OrderedProduct{
+		{ID: ksuid.New().String(), Price: money.New(1000, "USD"), Quantity: 2},
+		{ID: ksuid.New().String(), Price: money.New(500, "USD"), Quantity: 1},
+	}, "EUR")
+	if err != nil {
+		t.Fatalf("PriceProducts: %v", err)
+	}
+	if products[0].Price != money.New(900, "EUR") {
+		t.Errorf("price = %s, want 9.00 EUR", products[0].Price)
+	}
+	if total != money.New(2250, "EUR") {
+		t.Errorf("total = %s, want 22.50 EUR", total)
+	}
+}
+
+func TestIdempotentRequest(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	accountID, key := ksuid.New().String(), "checkout-1"
+
+	if orderID, err := s.BeginIdempotentRequest(ctx, accountID, key, "hash"); err != nil || orderID != "" {
+		t.Fatalf("BeginIdempotentRequest = %q, %v, want new request", orderID, err)
+	}
+	if _, err := s.BeginIdempotentRequest(ctx, accountID, key, "hash"); !errors.Is(err, ErrIdempotencyKeyInProgress) {
+		t.Errorf("replay in progress error = %v, want %v", err, ErrIdempotencyKeyInProgress)
+	}
+
+	o, err := s.PostOrder(ctx, accountID, []OrderedProduct{{ID: ksuid.New().String(), Price: money.New(100, "USD"), Quantity: 1}}, "", PostOrderOptions{IdempotencyKey: key})
+	if err != nil {
+		t.Fatalf("PostOrder: %v", err)
+	}
+	if orderID, err := s.BeginIdempotentRequest(ctx, accountID, key, "hash"); err != nil || orderID != o.ID {
+		t.Errorf("replay = %q, %v, want %s", orderID, err, o.ID)
+	}
+	if _, err := s.BeginIdempotentRequest(ctx, accountID, key, "other"); !errors.Is(err, ErrIdempotencyKeyConflict) {
+		t.Errorf("replay with other payload error = %v, want %v", err, ErrIdempotencyKeyConflict)
+	}
+
+	// Ключ неудачного запроса освобождается, и запрос можно повторить
+	if _, err := s.BeginIdempotentRequest(ctx, accountID, "checkout-2", "hash"); err != nil {
+		t.Fatalf("BeginIdempotentRequest: %v", err)
+	}
+	if err := s.AbortIdempotentRequest(ctx, accountID, "checkout-2"); err != nil {
+		t.Fatalf("AbortIdempotentRequest: %v", err)
+	}
+	if orderID, err := s.BeginIdempotentRequest(ctx, accountID, "checkout-2", "hash"); err != nil || orderID != "" {
+		t.Errorf("BeginIdempotentRequest after abort = %q, %v, want new request", orderID, err)
+	}
+}
+
+func TestPostOrderHashIgnoresProductOrder(t *testing.T) {
+	a := catalog.StockItem{ProductID: "a", Quantity: 1}
+	b := catalog.StockItem{ProductID: "b", Quantity: 2}
+
+	if postOrderHash("acc", []catalog.StockItem{a, b}, "USD") != postOrderHash("acc", []catalog.StockItem{b, a}, "USD") {
+		t.Error("hash depends on product order")
+	}
+	if postOrderHash("acc", []catalog.StockItem{a, b}, "USD") == postOrderHash("acc", []catalog.StockItem{a, b}, "EUR") {
+		t.Error("hash ignores currency")
+	}
+}
+
+func TestWatchOrders(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	accountID := ksuid.New().String()
+	products := []OrderedProduct{{ID: ksuid.New().String(), Price: money.New(100, "USD"), Quantity: 1}}
+
+	accountWatch := s.WatchOrders(ctx, "", accountID)
+	defer accountWatch.Close()
+
+	o, err := s.PostOrder(ctx, accountID, products, "", PostOrderOptions{})
+	if err != nil {
+		t.Fatalf("PostOrder: %v", err)
+	}
+	if _, err := s.PostOrder(ctx, ksuid.New().String(), products, "", PostOrderOptions{}); err != nil {
+		t.Fatalf("PostOrder for another account: %v", err)
+	}
+	orderWatch := s.WatchOrders(ctx, o.ID, "")
+	defer orderWatch.Close()
+	if _, err := s.UpdateOrderStatus(ctx, o.ID, StatusPaid); err != nil {
+		t.Fatalf("UpdateOrderStatus: %v", err)
+	}
+
+	for _, update := range []struct {
+		watch *OrderWatch
+		want  OrderStatus
+	}{
+		{accountWatch, StatusPending},
+		{accountWatch, StatusPaid},
+		{orderWatch, StatusPaid},
+	} {
+		select {
+		case got := <-update.watch.Updates():
+			if got.ID != o.ID || got.Status != update.want {
+				t.Errorf("update = order %s in %s, want %s in %s", got.ID, got.Status, o.ID, update.want)
+			}
+		default:
+			t.Fatalf("no update, want order %s in %s", o.ID, update.want)
+		}
+	}
+	if len(accountWatch.Updates()) != 0 {
+		t.Errorf("account watch got an order of another account")
+	}
+}
+
+func TestWatchOrdersClosesLaggingWatch(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	accountID := ksuid.New().String()
+
+	watch := s.WatchOrders(ctx, "", accountID)
+	defer watch.Close()
+	for range watchBufferSize + 1 {
+		if _, err := s.PostOrder(ctx, accountID, []OrderedProduct{{ID: ksuid.New().String(), Price: money.New(100, "USD"), Quantity: 1}}, "", PostOrderOptions{}); err != nil {
+			t.Fatalf("PostOrder: %v", err)
+		}
+	}
+
+	received := 0
+	for range watch.Updates() {
+		received++
+	}
+	if received != watchBufferSize {
+		t.Errorf("received %d updates before the watch was closed, want %d", received, watchBufferSize)
+	}
+}
@@ -10,4 +10,74 @@ CREATE TABLE IF NOT EXISTS order_products(
     product_id CHAR(27),
     quantity INT NOT NULL,
     PRIMARY KEY(order_id,product_id)
-);
\ No newline at end of file
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'paid', 'shipped', 'delivered', 'cancelled', 'refunded'));
+
+CREATE TABLE IF NOT EXISTS order_status_history(
+    id BIGSERIAL PRIMARY KEY,
+    order_id CHAR(27) NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_id_idx ON order_status_history (order_id);
+
+INSERT INTO order_status_history(order_id, from_status, to_status, changed_at)
+SELECT o.id, NULL, 'pending', o.created_at
+FROM orders o
+WHERE NOT EXISTS (SELECT 1 FROM order_status_history h WHERE h.order_id = o.id);
+
+-- Переход с MONEY на целые минимальные единицы валюты.
+-- Старые заказы считаются оформленными в USD.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'orders' AND column_name = 'total_price') THEN
+        ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_amount BIGINT;
+        ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3);
+        UPDATE orders SET total_amount = ROUND(total_price::numeric * 100)::BIGINT, currency = 'USD';
+        ALTER TABLE orders ALTER COLUMN total_amount SET NOT NULL;
+        ALTER TABLE orders ALTER COLUMN currency SET NOT NULL;
+        ALTER TABLE orders DROP COLUMN total_price;
+    END IF;
+END $$;
+
+-- Снимок цен на момент оформления: цена товара в валюте заказа и курс пересчёта из валюты каталога.
+-- У старых заказов цены товаров не сохранялись, они берутся из каталога.
+ALTER TABLE order_products ADD COLUMN IF NOT EXISTS price_amount BIGINT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC NOT NULL DEFAULT 1;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS base_currency CHAR(3);
+UPDATE orders SET base_currency = currency WHERE base_currency IS NULL;
+ALTER TABLE orders ALTER COLUMN base_currency SET NOT NULL;
+
+-- Корзины: только товары и количество, цены берутся из каталога при чтении
+CREATE TABLE IF NOT EXISTS cart_items(
+    account_id CHAR(27) NOT NULL,
+    product_id CHAR(27) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY(account_id, product_id)
+);
+
+-- Ключи идемпотентности PostOrder. Пока заказ оформляется, order_id пустой, а expires_at
+-- ограничивает время, на которое запрос занимает ключ; после оформления - время хранения ключа.
+CREATE TABLE IF NOT EXISTS idempotency_keys(
+    account_id CHAR(27) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    order_id CHAR(27),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY(account_id, idempotency_key)
+);
+
+-- Outbox: события, записанные в одной транзакции с заказом. Relay публикует их и удаляет.
+CREATE TABLE IF NOT EXISTS outbox(
+    id CHAR(27) PRIMARY KEY,
+    type VARCHAR(128) NOT NULL,
+    aggregate_id CHAR(27) NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    payload BYTEA NOT NULL
+);
+CREATE INDEX IF NOT EXISTS outbox_occurred_at_idx ON outbox (occurred_at, id);
@@ -0,0 +1,115 @@
+package order
+
+import (
+	"context"
+	"go-microservice/platform"
+	"sync"
+)
+
+var ErrWatchLagging = platform.NewError(platform.Conflict, "WATCH_LAGGING", "subscriber fell behind order updates, subscribe again")
+
+// watchBufferSize - сколько изменений копится для подписчика, пока он отправляет предыдущие
+const watchBufferSize = 16
+
+// OrderWatch - подписка на оформление заказов и смену их статуса, см. Service.WatchOrders.
+// Изменения приходят только от того экземпляра сервиса, в котором сделаны.
+type OrderWatch struct {
+	orderID   string
+	accountID string
+	updates   chan Order
+	hub       *watchHub
+}
+
+// Updates возвращает канал изменений. Канал закрывается, если подписчик не успевал их читать:
+// изменения потеряны, и подписку нужно создать заново.
+func (w *OrderWatch) Updates() <-chan Order {
+	return w.updates
+}
+
+// Close отменяет подписку
+func (w *OrderWatch) Close() {
+	w.hub.remove(w)
+}
+
+func (w *OrderWatch) matches(o Order) bool {
+	if w.orderID != "" {
+		return o.ID == w.orderID
+	}
+	return o.AccountID == w.accountID
+}
+
+// watchHub рассылает изменения заказов подпискам
+type watchHub struct {
+	mu      sync.Mutex
+	watches map[*OrderWatch]struct{}
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{watches: make(map[*OrderWatch]struct{})}
+}
+
+func (h *watchHub) add(orderID, accountID string) *OrderWatch {
+	w := &OrderWatch{
+		orderID:   orderID,
+		accountID: accountID,
+		updates:   make(chan Order, watchBufferSize),
+		hub:       h,
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.watches[w] = struct{}{}
+	return w
+}
+
+func (h *watchHub) remove(w *OrderWatch) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.watches[w]; ok {
+		delete(h.watches, w)
+		close(w.updates)
+	}
+}
+
+// empty сообщает, что подписок нет и изменения можно не рассылать
+func (h *watchHub) empty() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.watches) == 0
+}
+
+// publish не ждёт подписчиков: отставшая подписка закрывается
+func (h *watchHub) publish(o Order) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for w := range h.watches {
+		if !w.matches(o) {
+			continue
+		}
+		select {
+		case w.updates <- o:
+		default:
+			delete(h.watches, w)
+			close(w.updates)
+		}
+	}
+}
+
+// WatchOrders подписывается на изменения заказа orderID или, если он пуст, всех заказов аккаунта accountID
+func (s orderService) WatchOrders(ctx context.Context, orderID, accountID string) *OrderWatch {
+	s.logger.DebugContext(ctx, "Orders watch started", "order_id", orderID, "account_id", accountID)
+	return s.watches.add(orderID, accountID)
+}
+
+// notifyOrderChanged рассылает подписчикам заказ orderID после смены статуса.
+// Ошибка чтения заказа только пишется в лог: статус уже изменён.
+func (s orderService) notifyOrderChanged(ctx context.Context, orderID string) {
+	if s.watches.empty() {
+		return
+	}
+	o, err := s.repository.GetOrderByID(ctx, orderID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Order watchers not notified", "order_id", orderID, "error", err)
+		return
+	}
+	s.watches.publish(*o)
+}
@@ -0,0 +1,40 @@
+// gencerts выпускает одноразовый CA и сертификаты сервисов для локальной проверки mTLS:
+//
+//	go run ./platform/cmd/gencerts -dir certs
+package main
+
+import (
+	"flag"
+	"go-microservice/platform/tlstest"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", "certs", "каталог для сертификатов")
+	services := flag.String("services", "account,catalog,order,graphql", "сервисы через запятую; имя сервиса становится DNS SAN сертификата")
+	flag.Parse()
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		log.Fatal(err)
+	}
+
+	ca, err := tlstest.NewCA()
+	if err != nil {
+		log.Fatal(err)
+	}
+	caFile, err := ca.WriteCert(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("CA:", caFile)
+
+	for _, name := range strings.Split(*services, ",") {
+		certFile, keyFile, err := ca.Issue(*dir, name, name, "localhost")
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Println(name+":", certFile, keyFile)
+	}
+}
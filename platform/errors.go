@@ -0,0 +1,176 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"maps"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorKind - вид ошибки домена, по нему выбирается gRPC код
+type ErrorKind uint8
+
+const (
+	NotFound ErrorKind = iota + 1
+	AlreadyExists
+	InvalidArgument
+	FailedPrecondition
+	Unauthenticated
+	PermissionDenied
+	// Conflict - данные изменились параллельно, запрос можно повторить
+	Conflict
+)
+
+var kindCodes = map[ErrorKind]codes.Code{
+	NotFound:           codes.NotFound,
+	AlreadyExists:      codes.AlreadyExists,
+	InvalidArgument:    codes.InvalidArgument,
+	FailedPrecondition: codes.FailedPrecondition,
+	Unauthenticated:    codes.Unauthenticated,
+	PermissionDenied:   codes.PermissionDenied,
+	Conflict:           codes.Aborted,
+}
+
+// errorDomain - ErrorInfo.Domain ошибок домена, по нему клиент отличает их от чужих ErrorInfo
+const errorDomain = "go-microservice"
+
+// Error - типизированная ошибка домена. Сервер отдаёт её gRPC статусом с кодом по Kind
+// и ErrorInfo{Reason, Metadata}, клиент восстанавливает из статуса снова *Error,
+// так что errors.Is(err, account.ErrAccountNotFound) работает и по ту сторону gRPC.
+// Пакеты объявляют ошибки переменными и дополняют текст через fmt.Errorf("%w: ...").
+type Error struct {
+	Kind ErrorKind
+	// Reason - машиночитаемый код ошибки, например ACCOUNT_NOT_FOUND; шлюз отдаёт его в extensions.code
+	Reason  string
+	Message string
+	// Metadata - подробности для клиента, например {"productId": "..."}
+	Metadata map[string]string
+}
+
+// NewError создаёт ошибку домена
+func NewError(kind ErrorKind, reason, message string) *Error {
+	return &Error{Kind: kind, Reason: reason, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is сравнивает ошибки по Reason: ошибка, пришедшая от другого сервиса, равна переменной его пакета
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Reason == e.Reason
+}
+
+// WithMetadata возвращает копию ошибки с дополнительной парой key=value в Metadata
+func (e *Error) WithMetadata(key, value string) *Error {
+	c := *e
+	c.Metadata = maps.Clone(e.Metadata)
+	if c.Metadata == nil {
+		c.Metadata = map[string]string{}
+	}
+	c.Metadata[key] = value
+	return &c
+}
+
+// GRPCStatus реализует интерфейс, по которому gRPC сервер и status.FromError получают статус ошибки.
+// Для обёрнутой ошибки gRPC подставляет в статус полный текст обёртки.
+func (e *Error) GRPCStatus() *status.Status {
+	code, ok := kindCodes[e.Kind]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := status.New(code, e.Message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: e.Reason, Domain: errorDomain, Metadata: e.Metadata})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// UnaryServerErrors отдаёт клиенту ошибки домена и gRPC статусы как есть, отмену и дедлайн -
+// соответствующими кодами, а остальные ошибки - как Internal без текста: подробности
+// остаются в логе сервиса, клиенту они ни к чему. Должен стоять перед UnaryServerLogging.
+func UnaryServerErrors() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		res, err := handler(ctx, req)
+		return res, serverError(err)
+	}
+}
+
+// StreamServerErrors - UnaryServerErrors для потоковых вызовов
+func StreamServerErrors() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return serverError(handler(srv, ss))
+	}
+}
+
+func serverError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
+// UnaryClientErrors восстанавливает ошибки домена из gRPC статусов с ErrorInfo.
+// Остальные ошибки возвращаются как есть, их код доступен через status.Code.
+func UnaryClientErrors() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return clientError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientErrors - UnaryClientErrors для потоковых вызовов: ошибки домена восстанавливаются
+// и при открытии потока, и при чтении из него. io.EOF в конце потока возвращается как есть.
+func StreamClientErrors() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, clientError(err)
+		}
+		return &clientStream{ClientStream: cs}, nil
+	}
+}
+
+// clientStream восстанавливает ошибки домена из ошибок RecvMsg
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return clientError(s.ClientStream.RecvMsg(m))
+}
+
+func clientError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != errorDomain {
+			continue
+		}
+		return &Error{Kind: errorKind(st.Code()), Reason: info.Reason, Message: st.Message(), Metadata: info.Metadata}
+	}
+	return err
+}
+
+func errorKind(code codes.Code) ErrorKind {
+	for kind, c := range kindCodes {
+		if c == code {
+			return kind
+		}
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// failServiceDesc - сервис, метод которого возвращает ошибку, переданную при регистрации
+var failServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.FailService",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Fail",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			if err := dec(new(healthpb.HealthCheckRequest)); err != nil {
+				return nil, err
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: "/pb.FailService/Fail"}
+			return interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				return nil, srv.(error)
+			})
+		},
+	}},
+}
+
+// failStreamDesc - потоковый метод, который завершается ошибкой, переданной при регистрации,
+// если в контексте есть пользователь, иначе - Unauthenticated
+var failStreamDesc = grpc.ServiceDesc{
+	ServiceName: "pb.FailStreamService",
+	HandlerType: (*any)(nil),
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Fail",
+		ServerStreams: true,
+		Handler: func(srv any, stream grpc.ServerStream) error {
+			if _, ok := CallerFrom(stream.Context()); !ok {
+				return status.Error(codes.Unauthenticated, "no caller")
+			}
+			return srv.(error)
+		},
+	}},
+}
+
+// callFailing возвращает ошибку, которую клиент получит от сервера, вернувшего err.
+// С stream вызов потоковый и идёт от имени пользователя.
+func callFailing(t *testing.T, err error, stream bool) error {
+	t.Helper()
+	serv, serverErr := NewServer(TLSConfig{}, DiscardLogger())
+	if serverErr != nil {
+		t.Fatalf("NewServer: %v", serverErr)
+	}
+	serv.RegisterService(&failServiceDesc, err)
+	serv.RegisterService(&failStreamDesc, err)
+	lis, listenErr := net.Listen("tcp", "127.0.0.1:0")
+	if listenErr != nil {
+		t.Fatalf("listen: %v", listenErr)
+	}
+	go serv.Serve(lis)
+	t.Cleanup(serv.Stop)
+
+	conn, dialErr := Dial(lis.Addr().String(), TLSConfig{})
+	if dialErr != nil {
+		t.Fatalf("Dial: %v", dialErr)
+	}
+	defer conn.Close()
+	if stream {
+		ctx := WithCaller(context.Background(), Caller{Subject: "account-1"})
+		cs, err := conn.NewStream(ctx, &failStreamDesc.Streams[0], "/pb.FailStreamService/Fail")
+		if err != nil {
+			return err
+		}
+		if err := cs.SendMsg(&healthpb.HealthCheckRequest{}); err != nil {
+			return err
+		}
+		if err := cs.CloseSend(); err != nil {
+			return err
+		}
+		return cs.RecvMsg(&healthpb.HealthCheckResponse{})
+	}
+	return conn.Invoke(context.Background(), "/pb.FailService/Fail", &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+}
+
+func TestDomainErrorOverGRPC(t *testing.T) {
+	errOutOfStock := NewError(FailedPrecondition, "OUT_OF_STOCK", "out of stock")
+
+	for name, stream := range map[string]bool{"unary": false, "stream": true} {
+		t.Run(name, func(t *testing.T) {
+			err := callFailing(t, fmt.Errorf("%w: product p1", errOutOfStock.WithMetadata("productId", "p1")), stream)
+
+			if !errors.Is(err, errOutOfStock) {
+				t.Fatalf("got %v, want errors.Is OUT_OF_STOCK", err)
+			}
+			if status.Code(err) != codes.FailedPrecondition {
+				t.Errorf("code = %s, want FailedPrecondition", status.Code(err))
+			}
+			var domainErr *Error
+			errors.As(err, &domainErr)
+			if domainErr.Message != "out of stock: product p1" || domainErr.Metadata["productId"] != "p1" {
+				t.Errorf("got %q with metadata %v, want wrapped message and productId p1", domainErr.Message, domainErr.Metadata)
+			}
+		})
+	}
+}
+
+func TestInternalErrorIsHidden(t *testing.T) {
+	err := callFailing(t, errors.New("pq: connection refused"), false)
+
+	if status.Code(err) != codes.Internal {
+		t.Errorf("code = %s, want Internal", status.Code(err))
+	}
+	if msg := status.Convert(err).Message(); msg != "internal error" {
+		t.Errorf("message = %q, want internal error", msg)
+	}
+}
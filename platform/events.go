@@ -0,0 +1,172 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Event - доменное событие. Payload - сообщение из events/pb в двоичном protobuf,
+// Type - полное имя его схемы вместе с версией, например events.v1.OrderCreated.
+type Event struct {
+	// ID - KSUID, подписчики отбрасывают по нему повторно доставленные события
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	AggregateID string    `json:"aggregate_id"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	Payload     []byte    `json:"payload"`
+}
+
+// NewEvent кодирует msg в событие об изменении агрегата aggregateID (аккаунта, товара, заказа)
+func NewEvent(aggregateID string, msg proto.Message) (Event, error) {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:          ksuid.New().String(),
+		Type:        string(msg.ProtoReflect().Descriptor().FullName()),
+		AggregateID: aggregateID,
+		OccurredAt:  time.Now(),
+		Payload:     payload,
+	}, nil
+}
+
+// EventPublisher доставляет события подписчикам. Relay гарантирует доставку хотя бы раз:
+// после сбоя событие может прийти повторно.
+type EventPublisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// EventsConfig выбирает, куда relay публикует события из outbox
+type EventsConfig struct {
+	// Publisher - file или inprocess. inprocess годится только процессу, который подписывается сам
+	Publisher string `envconfig:"EVENTS_PUBLISHER" default:"file"`
+	// File - файл для Publisher=file, события дописываются в конец
+	File          string        `envconfig:"EVENTS_FILE" default:"events.jsonl"`
+	RelayInterval time.Duration `envconfig:"EVENTS_RELAY_INTERVAL" default:"1s"`
+}
+
+// NewEventPublisher создаёт publisher по c. Возвращённая функция закрывает его после остановки relay.
+func NewEventPublisher(c EventsConfig) (EventPublisher, func() error, error) {
+	switch c.Publisher {
+	case "", "file":
+		p, err := NewFilePublisher(c.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		return p, p.Close, nil
+	case "inprocess":
+		return NewInProcessPublisher(), func() error { return nil }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown EVENTS_PUBLISHER %q, want file or inprocess", c.Publisher)
+	}
+}
+
+// ErrNoSubscribers - у InProcessPublisher нет обработчиков, событие некому доставить
+var ErrNoSubscribers = errors.New("no event subscribers")
+
+// InProcessPublisher синхронно передаёт события обработчикам в том же процессе.
+// Пока обработчиков нет, Publish возвращает ErrNoSubscribers, и события остаются в outbox.
+type InProcessPublisher struct {
+	mu       sync.RWMutex
+	handlers []func(context.Context, Event) error
+}
+
+func NewInProcessPublisher() *InProcessPublisher {
+	return &InProcessPublisher{}
+}
+
+// Subscribe добавляет обработчик всех событий. Ошибка обработчика возвращается из Publish,
+// и relay повторит событие для всех обработчиков.
+func (p *InProcessPublisher) Subscribe(handler func(context.Context, Event) error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers = append(p.handlers, handler)
+}
+
+func (p *InProcessPublisher) Publish(ctx context.Context, e Event) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.handlers) == 0 {
+		return ErrNoSubscribers
+	}
+	for _, h := range p.handlers {
+		if err := h(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FilePublisher дописывает события в файл по одному JSON на строку. Payload известных схем
+// (из импортированных пакетов events/pb) записывается JSON объектом, остальные - base64.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FilePublisher{file: f}, nil
+}
+
+// fileEvent - строка файла FilePublisher
+type fileEvent struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregateId"`
+	OccurredAt  time.Time       `json:"occurredAt"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+func (p *FilePublisher) Publish(ctx context.Context, e Event) error {
+	payload, err := payloadJSON(e)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(fileEvent{
+		ID:          e.ID,
+		Type:        e.Type,
+		AggregateID: e.AggregateID,
+		OccurredAt:  e.OccurredAt,
+		Payload:     payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.file.Write(append(line, '\n'))
+	return err
+}
+
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
+
+func payloadJSON(e Event) (json.RawMessage, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(e.Type))
+	if err != nil {
+		return json.Marshal(e.Payload)
+	}
+	msg := mt.New().Interface()
+	if err := proto.Unmarshal(e.Payload, msg); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", e.Type, err)
+	}
+	return protojson.Marshal(msg)
+}
@@ -0,0 +1,153 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// memoryOutbox - OutboxStore в памяти
+type memoryOutbox struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (o *memoryOutbox) PendingEvents(ctx context.Context, limit int) ([]Event, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]Event{}, o.events[:min(limit, len(o.events))]...), nil
+}
+
+func (o *memoryOutbox) DeleteEvents(ctx context.Context, ids []string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = slices.DeleteFunc(o.events, func(e Event) bool { return slices.Contains(ids, e.ID) })
+	return nil
+}
+
+func newTestEvents(t *testing.T, n int) []Event {
+	t.Helper()
+	events := make([]Event, n)
+	for i := range events {
+		e, err := NewEvent("aggregate", &healthpb.HealthCheckRequest{Service: "svc"})
+		if err != nil {
+			t.Fatalf("NewEvent: %v", err)
+		}
+		events[i] = e
+	}
+	return events
+}
+
+func TestRelayBatchStopsAtFirstFailure(t *testing.T) {
+	events := newTestEvents(t, 3)
+	store := &memoryOutbox{events: slices.Clone(events)}
+
+	var published []string
+	publisher := NewInProcessPublisher()
+	publisher.Subscribe(func(ctx context.Context, e Event) error {
+		if e.ID == events[1].ID {
+			return errors.New("subscriber is down")
+		}
+		published = append(published, e.ID)
+		return nil
+	})
+
+	if _, err := relayBatch(context.Background(), store, publisher); err == nil {
+		t.Fatal("relayBatch error = nil, want publish error")
+	}
+	if !slices.Equal(published, []string{events[0].ID}) {
+		t.Errorf("published = %v, want only the first event", published)
+	}
+	// Событие после сбойного тоже остаётся, чтобы не нарушить порядок
+	pending, _ := store.PendingEvents(context.Background(), outboxBatchSize)
+	if len(pending) != 2 || pending[0].ID != events[1].ID || pending[1].ID != events[2].ID {
+		t.Errorf("pending = %v, want the failed event and the one after it", pending)
+	}
+}
+
+func TestRelayBatchKeepsEventsWithoutSubscribers(t *testing.T) {
+	events := newTestEvents(t, 2)
+	store := &memoryOutbox{events: slices.Clone(events)}
+
+	if _, err := relayBatch(context.Background(), store, NewInProcessPublisher()); !errors.Is(err, ErrNoSubscribers) {
+		t.Fatalf("relayBatch error = %v, want %v", err, ErrNoSubscribers)
+	}
+	if pending, _ := store.PendingEvents(context.Background(), outboxBatchSize); len(pending) != len(events) {
+		t.Errorf("pending = %v, want all %d events", pending, len(events))
+	}
+}
+
+func TestRelayEvents(t *testing.T) {
+	events := newTestEvents(t, outboxBatchSize+1)
+	store := &memoryOutbox{events: slices.Clone(events)}
+
+	delivered := make(chan Event, len(events))
+	publisher := NewInProcessPublisher()
+	publisher.Subscribe(func(ctx context.Context, e Event) error {
+		delivered <- e
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RelayEvents(ctx, store, publisher, time.Millisecond)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	for i, want := range events {
+		select {
+		case got := <-delivered:
+			if got.ID != want.ID {
+				t.Fatalf("event %d = %s, want %s", i, got.ID, want.ID)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %d was not delivered", i)
+		}
+	}
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	p, err := NewFilePublisher(path)
+	if err != nil {
+		t.Fatalf("NewFilePublisher: %v", err)
+	}
+	e := newTestEvents(t, 1)[0]
+	if err := p.Publish(context.Background(), e); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		ID      string `json:"id"`
+		Type    string `json:"type"`
+		Payload struct {
+			Service string `json:"service"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("line %q is not JSON: %v", data, err)
+	}
+	if got.ID != e.ID || got.Type != "grpc.health.v1.HealthCheckRequest" || got.Payload.Service != "svc" {
+		t.Errorf("line = %s, want event %s with decoded payload", data, e.ID)
+	}
+}
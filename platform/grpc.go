@@ -0,0 +1,54 @@
+package platform
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"google.golang.org/grpc"
+)
+
+// Проверки состояния приходят каждые несколько секунд и не нужны в трассировке
+var tracingFilter = otelgrpc.WithFilter(filters.Not(filters.HealthCheck()))
+
+// NewServer создаёт gRPC сервер с транспортом из tlsConfig и общей обвязкой:
+// трассировка, метрики, metadata запроса, ошибки домена и access лог в logger.
+// Потоковые вызовы живут, пока клиент подписан, поэтому в метрики времени обработки не попадают.
+func NewServer(tlsConfig TLSConfig, logger *slog.Logger) (*grpc.Server, error) {
+	creds, err := ServerCredentials(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.StatsHandler(otelgrpc.NewServerHandler(tracingFilter)),
+		grpc.ChainUnaryInterceptor(UnaryServerMetrics(), UnaryServerInterceptor(), UnaryServerErrors(), UnaryServerLogging(logger)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(), StreamServerErrors(), StreamServerLogging(logger)),
+	), nil
+}
+
+// Dial создаёт подключение к сервису по url с транспортом из tlsConfig и общей обвязкой.
+// Соединение устанавливается при первом вызове.
+func Dial(url string, tlsConfig TLSConfig) (*grpc.ClientConn, error) {
+	creds, err := ClientCredentials(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.NewClient(url,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(tracingFilter)),
+		grpc.WithChainUnaryInterceptor(UnaryClientMetrics(), UnaryClientInterceptor(), UnaryClientErrors()),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(), StreamClientErrors()),
+	)
+}
+
+// serverStream подменяет контекст потока, в который перехватчик добавил значения
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
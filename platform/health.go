@@ -0,0 +1,95 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// checkTimeout ограничивает одну проверку зависимости
+const checkTimeout = 2 * time.Second
+
+// HealthCheck - проверка зависимости сервиса: базы данных или другого сервиса
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// RunChecks выполняет проверки параллельно и возвращает ошибку по имени каждой проверки (nil - в порядке)
+func RunChecks(ctx context.Context, checks []HealthCheck) map[string]error {
+	results := make(map[string]error, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			err := c.Check(ctx)
+			mu.Lock()
+			results[c.Name] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// HealthServer реализует grpc.health.v1. Пустое имя сервиса или имя gRPC сервиса
+// проверяет все зависимости, имя проверки - только её. Проверки выполняются на каждый запрос.
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+	service string
+	checks  []HealthCheck
+}
+
+// RegisterHealth регистрирует на serv HealthServer для gRPC сервиса service (например, pb.AccountService_ServiceDesc.ServiceName)
+func RegisterHealth(serv *grpc.Server, service string, checks ...HealthCheck) *HealthServer {
+	h := &HealthServer{service: service, checks: checks}
+	healthpb.RegisterHealthServer(serv, h)
+	return h
+}
+
+func (h *HealthServer) Check(ctx context.Context, r *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	checks := h.checks
+	if r.Service != "" && r.Service != h.service {
+		checks = nil
+		for _, c := range h.checks {
+			if c.Name == r.Service {
+				checks = append(checks, c)
+			}
+		}
+		if len(checks) == 0 {
+			return nil, status.Errorf(codes.NotFound, "unknown service %q", r.Service)
+		}
+	}
+
+	res := &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}
+	for name, err := range RunChecks(ctx, checks) {
+		if err != nil {
+			slog.WarnContext(ctx, "health check failed", "check", name, "error", err)
+			res.Status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return res, nil
+}
+
+// CheckConn спрашивает у сервиса за conn его состояние по grpc.health.v1
+func CheckConn(ctx context.Context, conn *grpc.ClientConn) error {
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("status %s", res.Status)
+	}
+	return nil
+}
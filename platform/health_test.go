@@ -0,0 +1,69 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealthServer(t *testing.T) {
+	h := &HealthServer{
+		service: "pb.AccountService",
+		checks: []HealthCheck{
+			{Name: "postgres", Check: func(ctx context.Context) error { return nil }},
+			{Name: "cache", Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+		},
+	}
+
+	tests := []struct {
+		service string
+		want    healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{"", healthpb.HealthCheckResponse_NOT_SERVING},
+		{"pb.AccountService", healthpb.HealthCheckResponse_NOT_SERVING},
+		{"postgres", healthpb.HealthCheckResponse_SERVING},
+		{"cache", healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+	for _, tt := range tests {
+		res, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{Service: tt.service})
+		if err != nil {
+			t.Fatalf("Check(%q): %v", tt.service, err)
+		}
+		if res.Status != tt.want {
+			t.Errorf("Check(%q) = %s, want %s", tt.service, res.Status, tt.want)
+		}
+	}
+
+	_, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Check(unknown) error = %v, want NotFound", err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 2*time.Millisecond
+	t.Cleanup(func() { retryBaseDelay, retryMaxDelay = 500*time.Millisecond, 10*time.Second })
+
+	attempts := 0
+	err := Retry(context.Background(), "connect", func() error {
+		if attempts++; attempts < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Retry = %v after %d attempts, want success after 3", err, attempts)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = Retry(ctx, "connect", func() error { return errors.New("down") })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Retry error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
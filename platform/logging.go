@@ -0,0 +1,127 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LogConfig задаёт уровень и формат логов сервиса
+type LogConfig struct {
+	// Level - debug, info, warn или error
+	Level string `envconfig:"LOG_LEVEL" default:"info"`
+	// Format - json или text
+	Format string `envconfig:"LOG_FORMAT" default:"json"`
+}
+
+// NewLogger создаёт логгер сервиса service, который пишет в stdout.
+// К каждой записи с контекстом добавляются request_id, trace_id и span_id запроса,
+// поэтому логи одного запроса во всех сервисах можно найти по любому из них.
+func NewLogger(service string, c LogConfig) (*slog.Logger, error) {
+	return newLogger(os.Stdout, service, c)
+}
+
+func newLogger(w io.Writer, service string, c LogConfig) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		return nil, fmt.Errorf("invalid LOG_LEVEL %q, want debug, info, warn or error", c.Level)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch c.Format {
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown LOG_FORMAT %q, want json or text", c.Format)
+	}
+	return slog.New(contextHandler{h}).With("service", service), nil
+}
+
+// DiscardLogger - логгер для тестов, ничего не пишет
+func DiscardLogger() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// contextHandler добавляет к записи идентификаторы запроса и трассировки из контекста
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// UnaryServerLogging пишет access лог каждого вызова: метод, код ответа, время и пользователя.
+// Должен стоять после UnaryServerInterceptor, чтобы ID запроса уже был в контексте, и после
+// UnaryServerErrors, чтобы в лог попал исходный текст ошибки, а не тот, что ушёл клиенту.
+// Ошибки сервера (Internal, Unknown и т.п.) пишутся с уровнем error, ошибки клиента - warn.
+func UnaryServerLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		logRPC(ctx, logger, info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+func logRPC(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(serverError(err))
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if c, ok := CallerFrom(ctx); ok {
+		attrs = append(attrs, slog.String("subject", c.Subject))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	logger.LogAttrs(ctx, codeLevel(code), "rpc", attrs...)
+}
+
+// StreamServerLogging - UnaryServerLogging для потоковых вызовов, строка пишется по завершении потока
+func StreamServerLogging(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logRPC(ss.Context(), logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func codeLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unimplemented, codes.DeadlineExceeded, codes.Unavailable:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
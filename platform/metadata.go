@@ -0,0 +1,146 @@
+// Package platform содержит общую обвязку gRPC серверов и клиентов сервисов.
+package platform
+
+import (
+	"context"
+	"strings"
+
+	"github.com/segmentio/ksuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Ключи gRPC metadata. Дедлайн передаётся самим gRPC (grpc-timeout), отдельный ключ не нужен.
+const (
+	RequestIDKey     = "x-request-id"
+	CallerSubjectKey = "x-caller-subject"
+	CallerRolesKey   = "x-caller-roles"
+)
+
+type requestIDKey struct{}
+
+type callerKey struct{}
+
+// Caller - аутентифицированный пользователь, от имени которого выполняется запрос.
+// Его выставляет шлюз после проверки access токена; сервисы доверяют metadata,
+// поэтому gRPC порты не должны быть доступны снаружи, а с mTLS стоит ограничить
+// клиентов через TLSConfig.AllowedPeers.
+type Caller struct {
+	Subject string
+	Roles   []string
+}
+
+// HasRole сообщает, есть ли у пользователя роль role
+func (c Caller) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID возвращает ID запроса или пустую строку
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID создаёт ID для запроса, пришедшего без него
+func NewRequestID() string {
+	return ksuid.New().String()
+}
+
+func WithCaller(ctx context.Context, c Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+// CallerFrom возвращает пользователя запроса. false - анонимный или внутренний вызов.
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	c, ok := ctx.Value(callerKey{}).(Caller)
+	return c, ok
+}
+
+// UnaryClientInterceptor передаёт ID запроса и пользователя из контекста в исходящую metadata
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor - UnaryClientInterceptor для потоковых вызовов
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	pairs := []string{}
+	if id := RequestID(ctx); id != "" {
+		pairs = append(pairs, RequestIDKey, id)
+	}
+	if c, ok := CallerFrom(ctx); ok {
+		pairs = append(pairs, CallerSubjectKey, c.Subject, CallerRolesKey, strings.Join(c.Roles, ","))
+	}
+	if len(pairs) == 0 {
+		return ctx
+	}
+	// Set, а не Append: значения, пришедшие от предыдущего хопа, заменяются текущими
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	for i := 0; i < len(pairs); i += 2 {
+		md.Set(pairs[i], pairs[i+1])
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// UnaryServerInterceptor достаёт ID запроса и пользователя из входящей metadata в контекст,
+// так что клиенты, созданные с UnaryClientInterceptor, передадут их дальше.
+// Запросу без ID назначается новый; ID возвращается клиенту в заголовке ответа.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = incomingContext(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, RequestID(ctx)))
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor - UnaryServerInterceptor для потоковых вызовов
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := incomingContext(ss.Context())
+		ss.SetHeader(metadata.Pairs(RequestIDKey, RequestID(ctx)))
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func incomingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := first(md, RequestIDKey)
+	if requestID == "" {
+		requestID = NewRequestID()
+	}
+	ctx = WithRequestID(ctx, requestID)
+
+	if subject := first(md, CallerSubjectKey); subject != "" {
+		var roles []string
+		if r := first(md, CallerRolesKey); r != "" {
+			roles = strings.Split(r, ",")
+		}
+		ctx = WithCaller(ctx, Caller{Subject: subject, Roles: roles})
+	}
+	return ctx
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package platform
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// hop прогоняет вызов через клиентский и серверный перехватчики, как при реальном gRPC вызове,
+// и возвращает контекст, который получил обработчик
+func hop(t *testing.T, ctx context.Context) context.Context {
+	t.Helper()
+	var got context.Context
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		// Исходящая metadata клиента становится входящей на сервере, значения контекста - нет
+		serverCtx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := UnaryServerInterceptor()(serverCtx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			got = ctx
+			return nil, nil
+		})
+		return err
+	}
+	if err := UnaryClientInterceptor()(ctx, "/test/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	return got
+}
+
+func TestMetadataPropagatesAcrossHops(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithCaller(ctx, Caller{Subject: "account-1", Roles: []string{"customer", "merchandiser"}})
+
+	// gateway -> order -> account
+	ctx = hop(t, hop(t, ctx))
+
+	if id := RequestID(ctx); id != "req-1" {
+		t.Errorf("request id = %q, want req-1", id)
+	}
+	c, ok := CallerFrom(ctx)
+	if !ok || c.Subject != "account-1" || !c.HasRole("merchandiser") || c.HasRole("admin") {
+		t.Errorf("caller = %+v, %v, want account-1 with customer and merchandiser roles", c, ok)
+	}
+}
+
+func TestServerAssignsRequestID(t *testing.T) {
+	ctx := hop(t, context.Background())
+
+	if RequestID(ctx) == "" {
+		t.Error("request id is empty, want generated id")
+	}
+	if c, ok := CallerFrom(ctx); ok {
+		t.Errorf("caller = %+v, want anonymous call", c)
+	}
+}
+
+func TestClientReplacesInheritedMetadata(t *testing.T) {
+	// Сервер, сам вызывающий другой сервис, не должен дублировать значения из исходящей metadata
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(RequestIDKey, "stale"))
+	ctx = WithRequestID(ctx, "req-2")
+
+	got := hop(t, ctx)
+	if id := RequestID(got); id != "req-2" {
+		t.Errorf("request id = %q, want req-2", id)
+	}
+}
@@ -0,0 +1,89 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	serverHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Время обработки gRPC вызова сервером.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+	clientHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_handling_seconds",
+		Help:    "Время gRPC вызова другого сервиса, включая сеть.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+	queryDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Время запроса репозитория к хранилищу.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"store", "operation", "result"})
+)
+
+// UnaryServerMetrics записывает время и код ответа каждого вызова в grpc_server_handling_seconds
+func UnaryServerMetrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		observeRPC(serverHandlingSeconds, info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+// UnaryClientMetrics записывает время и код ответа каждого вызова в grpc_client_handling_seconds
+func UnaryClientMetrics() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observeRPC(clientHandlingSeconds, method, start, err)
+		return err
+	}
+}
+
+func observeRPC(h *prometheus.HistogramVec, fullMethod string, start time.Time, err error) {
+	// fullMethod имеет вид /pb.AccountService/GetAccount
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	h.WithLabelValues(service, method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
+
+// observeQuery записывает время запроса к хранилищу store, см. StartQuery
+func observeQuery(store, operation string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	queryDurationSeconds.WithLabelValues(store, operation, result).Observe(time.Since(start).Seconds())
+}
+
+// ServeMetrics отдаёт метрики Prometheus на addr по /metrics, пока не отменён ctx
+func ServeMetrics(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
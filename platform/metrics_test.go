@@ -0,0 +1,43 @@
+package platform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func sampleCount(t *testing.T, o prometheus.Observer) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := o.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("write metric: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestUnaryServerMetrics(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.AccountService/GetAccount"}
+	ok := serverHandlingSeconds.WithLabelValues("pb.AccountService", "GetAccount", "OK")
+	notFound := serverHandlingSeconds.WithLabelValues("pb.AccountService", "GetAccount", "NotFound")
+	okBefore, notFoundBefore := sampleCount(t, ok), sampleCount(t, notFound)
+
+	interceptor := UnaryServerMetrics()
+	interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "account not found")
+	})
+
+	if got := sampleCount(t, ok) - okBefore; got != 1 {
+		t.Errorf("OK calls observed %d times, want 1", got)
+	}
+	if got := sampleCount(t, notFound) - notFoundBefore; got != 1 {
+		t.Errorf("NotFound calls observed %d times, want 1", got)
+	}
+}
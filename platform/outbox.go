@@ -0,0 +1,141 @@
+package platform
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// outboxBatchSize - сколько событий relay забирает из outbox за раз
+const outboxBatchSize = 100
+
+// OutboxStore - outbox сервиса: события, сохранённые в одной транзакции с изменением, которое их вызвало
+type OutboxStore interface {
+	// PendingEvents возвращает до limit неопубликованных событий в порядке записи
+	PendingEvents(ctx context.Context, limit int) ([]Event, error)
+	// DeleteEvents удаляет опубликованные события
+	DeleteEvents(ctx context.Context, ids []string) error
+}
+
+// RelayEvents каждые interval публикует события из store в порядке записи и удаляет опубликованные,
+// пока не отменён ctx. Если publisher вернул ошибку, остальные события ждут следующей попытки,
+// чтобы подписчики получали события одного агрегата по порядку.
+func RelayEvents(ctx context.Context, store OutboxStore, publisher EventPublisher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			n, err := relayBatch(ctx, store, publisher)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.WarnContext(ctx, "Event relay failed", "error", err)
+				}
+				break
+			}
+			if n < outboxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// relayBatch публикует одну пачку событий и возвращает их число
+func relayBatch(ctx context.Context, store OutboxStore, publisher EventPublisher) (int, error) {
+	events, err := store.PendingEvents(ctx, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := make([]string, 0, len(events))
+	var publishErr error
+	for _, e := range events {
+		if publishErr = publisher.Publish(ctx, e); publishErr != nil {
+			publishErr = fmt.Errorf("publish %s %s: %w", e.Type, e.ID, publishErr)
+			break
+		}
+		published = append(published, e.ID)
+	}
+	if len(published) > 0 {
+		if err := store.DeleteEvents(ctx, published); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), publishErr
+}
+
+// InsertEvents сохраняет события в таблицу outbox в транзакции tx, вместе с изменением, которое их вызвало.
+// Таблица одинакова во всех сервисах на Postgres, её схема - в up.sql сервиса.
+func InsertEvents(ctx context.Context, tx *sql.Tx, events []Event) error {
+	for _, e := range events {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO outbox(id,type,aggregate_id,occurred_at,payload) VALUES($1,$2,$3,$4,$5)",
+			e.ID, e.Type, e.AggregateID, e.OccurredAt, e.Payload)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PendingEvents реализует OutboxStore.PendingEvents для таблицы outbox
+func PendingEvents(ctx context.Context, db *sql.DB, limit int) ([]Event, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT id, type, aggregate_id, occurred_at, payload FROM outbox ORDER BY occurred_at, id LIMIT $1",
+		limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.Type, &e.AggregateID, &e.OccurredAt, &e.Payload); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// DeleteEvents реализует OutboxStore.DeleteEvents для таблицы outbox
+func DeleteEvents(ctx context.Context, db *sql.DB, ids []string) error {
+	_, err := db.ExecContext(ctx, "DELETE FROM outbox WHERE id = ANY($1)", pq.Array(ids))
+	return err
+}
+
+// StartEventRelay создаёт publisher по c и запускает RelayEvents для store в фоне.
+// Возвращённая функция останавливает relay, дожидается его и закрывает publisher;
+// её нужно вызвать до закрытия хранилища.
+func StartEventRelay(ctx context.Context, store OutboxStore, c EventsConfig) (stop func(), err error) {
+	publisher, closePublisher, err := NewEventPublisher(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RelayEvents(ctx, store, publisher, c.RelayInterval)
+	}()
+	return func() {
+		cancel()
+		<-done
+		if err := closePublisher(); err != nil {
+			slog.Warn("Closing event publisher failed", "error", err)
+		}
+	}, nil
+}
@@ -0,0 +1,34 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Задержки между попытками Retry: удваиваются от retryBaseDelay до retryMaxDelay
+var (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// Retry повторяет fn с экспоненциальной задержкой, пока она не выполнится без ошибки
+// или не закончится ctx. Используется при старте, пока поднимаются базы данных.
+func Retry(ctx context.Context, what string, fn func() error) error {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		slog.WarnContext(ctx, "Attempt failed, retrying", "step", what, "attempt", attempt, "delay", delay, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w (last error: %v)", what, ctx.Err(), err)
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
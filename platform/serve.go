@@ -0,0 +1,54 @@
+package platform
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// ShutdownTimeout - сколько ждать завершения текущих запросов при остановке.
+// Меньше 10 секунд, которые docker ждёт после SIGTERM до SIGKILL.
+const ShutdownTimeout = 5 * time.Second
+
+// SignalContext возвращает контекст, который отменяется по SIGINT или SIGTERM
+func SignalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// Serve обслуживает lis, пока не отменён ctx, затем перестаёт принимать вызовы
+// и дожидается текущих (GracefulStop). Вызовы, не завершившиеся за ShutdownTimeout,
+// обрываются. Возвращает nil после штатной остановки.
+func Serve(ctx context.Context, serv *grpc.Server, lis net.Listener) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- serv.Serve(lis)
+	}()
+	slog.Info("Listening", "addr", lis.Addr().String())
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down, waiting for in-flight calls")
+	stopped := make(chan struct{})
+	go func() {
+		serv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(ShutdownTimeout):
+		slog.Warn("Shutdown timeout, closing remaining connections")
+		serv.Stop()
+		<-stopped
+	}
+	return <-errc
+}
@@ -0,0 +1,65 @@
+package platform
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestServeDrainsInFlightCalls(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	serv := grpc.NewServer()
+	RegisterHealth(serv, "test", HealthCheck{Name: "slow", Check: func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, serv, lis)
+	}()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer conn.Close()
+	called := make(chan error, 1)
+	go func() {
+		called <- CheckConn(context.Background(), conn)
+	}()
+
+	<-started
+	cancel()
+	select {
+	case err := <-served:
+		t.Fatalf("Serve returned %v before in-flight call finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-called; err != nil {
+		t.Errorf("in-flight call failed: %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Serve = %v, want nil after graceful stop", err)
+	}
+
+	// После остановки новые вызовы не принимаются
+	ctx, cancelCall := context.WithTimeout(context.Background(), time.Second)
+	defer cancelCall()
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err == nil {
+		t.Error("Check succeeded after shutdown")
+	}
+}
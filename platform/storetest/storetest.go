@@ -0,0 +1,94 @@
+// Package storetest - общая часть контрактных тестов репозиториев сервисов:
+// запуск тестов на всех хранилищах и проверка outbox.
+package storetest
+
+import (
+	"bytes"
+	"context"
+	"go-microservice/platform"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Test - проверка контракта репозитория R
+type Test[R any] struct {
+	Name string
+	Run  func(t *testing.T, r R)
+}
+
+// Store - хранилище, на котором гоняются контрактные тесты, кроме хранилища в памяти
+type Store[R any] struct {
+	// Name - имя хранилища в именах подтестов (postgres, elastic)
+	Name string
+	// Env - переменная окружения с адресом хранилища; если она не задана, хранилище пропускается
+	Env string
+	// Open подключается по адресу из Env и возвращает пустое хранилище, закрывая его через t.Cleanup
+	Open func(t *testing.T, url string) R
+}
+
+// RunContract запускает tests на репозитории в памяти, который создаёт memory, и на store,
+// если задана store.Env. Каждый тест получает новый репозиторий.
+func RunContract[R any](t *testing.T, tests []Test[R], memory func() R, store Store[R]) {
+	factories := map[string]func(t *testing.T) R{
+		"memory": func(t *testing.T) R { return memory() },
+	}
+	if url := os.Getenv(store.Env); url != "" {
+		factories[store.Name] = func(t *testing.T) R { return store.Open(t, url) }
+	}
+
+	for name, newRepository := range factories {
+		t.Run(name, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.Name, func(t *testing.T) {
+					tt.Run(t, newRepository(t))
+				})
+			}
+		})
+	}
+}
+
+// CheckOutbox записывает через put два события msg агрегата aggregateID и проверяет,
+// что PendingEvents возвращает их по порядку, а DeleteEvents удаляет.
+func CheckOutbox(t *testing.T, store platform.OutboxStore, aggregateID string, msg proto.Message, put func(events ...platform.Event) error) {
+	t.Helper()
+	ctx := context.Background()
+
+	// У событий разное время, чтобы порядок не зависел от точности хранилища
+	events := make([]platform.Event, 2)
+	for i := range events {
+		e, err := platform.NewEvent(aggregateID, msg)
+		if err != nil {
+			t.Fatalf("NewEvent: %v", err)
+		}
+		e.OccurredAt = time.Now().UTC().Truncate(time.Microsecond).Add(time.Duration(i) * time.Millisecond)
+		events[i] = e
+	}
+	if err := put(events...); err != nil {
+		t.Fatalf("put events: %v", err)
+	}
+
+	pending, err := store.PendingEvents(ctx, 10)
+	if err != nil {
+		t.Fatalf("PendingEvents: %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != events[0].ID || pending[1].ID != events[1].ID {
+		t.Fatalf("PendingEvents = %v, want %v", pending, events)
+	}
+	if pending[0].Type != events[0].Type || pending[0].AggregateID != events[0].AggregateID || !bytes.Equal(pending[0].Payload, events[0].Payload) {
+		t.Errorf("PendingEvents[0] = %+v, want %+v", pending[0], events[0])
+	}
+
+	if err := store.DeleteEvents(ctx, []string{events[0].ID}); err != nil {
+		t.Fatalf("DeleteEvents: %v", err)
+	}
+	pending, err = store.PendingEvents(ctx, 10)
+	if err != nil {
+		t.Fatalf("PendingEvents: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != events[1].ID {
+		t.Errorf("PendingEvents after delete = %v, want only %s", pending, events[1].ID)
+	}
+}
@@ -0,0 +1,123 @@
+package platform
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TLSConfig - сертификаты сервиса для gRPC. Один и тот же сертификат служит
+// серверным для входящих вызовов и клиентским для исходящих.
+// Без CertFile соединения идут без шифрования, как при локальном запуске.
+type TLSConfig struct {
+	CertFile string `envconfig:"TLS_CERT_FILE"`
+	KeyFile  string `envconfig:"TLS_KEY_FILE"`
+	// CAFile - CA, которым подписаны сертификаты сервисов. На сервере с CAFile
+	// клиент обязан предъявить сертификат (mTLS); клиент без CAFile проверяет
+	// сервер по системным корневым сертификатам.
+	CAFile string `envconfig:"TLS_CA_FILE"`
+	// AllowedPeers - SAN (DNS имя или URI) клиентских сертификатов, которым сервер
+	// разрешает подключаться. Пусто - любой сертификат, подписанный CAFile.
+	AllowedPeers []string `envconfig:"TLS_ALLOWED_PEERS"`
+}
+
+// Enabled сообщает, настроен ли TLS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// ServerCredentials возвращает транспорт для grpc.NewServer: mTLS с проверкой
+// SAN клиента, TLS без проверки клиента или plaintext, если TLS не настроен.
+func ServerCredentials(c TLSConfig) (credentials.TransportCredentials, error) {
+	if !c.Enabled() {
+		return insecure.NewCredentials(), nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.CAFile != "" {
+		if config.ClientCAs, err = loadCertPool(c.CAFile); err != nil {
+			return nil, err
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		if len(c.AllowedPeers) > 0 {
+			config.VerifyConnection = verifyPeer(c.AllowedPeers)
+		}
+	} else if len(c.AllowedPeers) > 0 {
+		return nil, errors.New("TLS_ALLOWED_PEERS requires TLS_CA_FILE")
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// ClientCredentials возвращает транспорт для grpc.NewClient. Имя сервера
+// проверяется по адресу из URL, поэтому в сертификате сервиса должно быть
+// имя, по которому к нему обращаются (например, account).
+func ClientCredentials(c TLSConfig) (credentials.TransportCredentials, error) {
+	if !c.Enabled() {
+		return insecure.NewCredentials(), nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load client certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.CAFile != "" {
+		if config.RootCAs, err = loadCertPool(c.CAFile); err != nil {
+			return nil, err
+		}
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// verifyPeer пропускает только клиентов, в сертификате которых есть один из allowed.
+// Цепочка к этому моменту уже проверена по ClientCAs.
+func verifyPeer(allowed []string) func(tls.ConnectionState) error {
+	names := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		names[name] = true
+	}
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("client certificate is required")
+		}
+		for _, name := range certificateNames(cs.PeerCertificates[0]) {
+			if names[name] {
+				return nil
+			}
+		}
+		return fmt.Errorf("client certificate %q is not allowed", cs.PeerCertificates[0].Subject.CommonName)
+	}
+}
+
+func certificateNames(cert *x509.Certificate) []string {
+	names := append([]string(nil), cert.DNSNames...)
+	for _, u := range cert.URIs {
+		names = append(names, u.String())
+	}
+	return names
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates in %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,140 @@
+package platform
+
+import (
+	"context"
+	"go-microservice/platform/tlstest"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testCerts struct {
+	dir    string
+	ca     *tlstest.CA
+	caFile string
+}
+
+func newTestCerts(t *testing.T) *testCerts {
+	t.Helper()
+	ca, err := tlstest.NewCA()
+	if err != nil {
+		t.Fatalf("NewCA: %v", err)
+	}
+	dir := t.TempDir()
+	caFile, err := ca.WriteCert(dir)
+	if err != nil {
+		t.Fatalf("WriteCert: %v", err)
+	}
+	return &testCerts{dir: dir, ca: ca, caFile: caFile}
+}
+
+// config выпускает сертификат name с SAN sans и возвращает TLSConfig с ним
+func (c *testCerts) config(t *testing.T, name string, sans ...string) TLSConfig {
+	t.Helper()
+	certFile, keyFile, err := c.ca.Issue(c.dir, name, sans...)
+	if err != nil {
+		t.Fatalf("Issue(%s): %v", name, err)
+	}
+	return TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: c.caFile}
+}
+
+// serve поднимает gRPC сервер с health сервисом на свободном порту localhost и возвращает его адрес
+func serve(t *testing.T, c TLSConfig) string {
+	t.Helper()
+	creds, err := ServerCredentials(c)
+	if err != nil {
+		t.Fatalf("ServerCredentials: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := grpc.NewServer(grpc.Creds(creds))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+func check(t *testing.T, addr string, c TLSConfig) error {
+	t.Helper()
+	creds, err := ClientCredentials(c)
+	if err != nil {
+		t.Fatalf("ClientCredentials: %v", err)
+	}
+	// Сертификат сервера выпущен на localhost, а не на 127.0.0.1
+	conn, err := grpc.NewClient("localhost:"+port(t, addr), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func port(t *testing.T, addr string) string {
+	t.Helper()
+	_, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("split %s: %v", addr, err)
+	}
+	return p
+}
+
+func TestMutualTLS(t *testing.T) {
+	certs := newTestCerts(t)
+	server := certs.config(t, "account", "localhost")
+	server.AllowedPeers = []string{"order", "spiffe://go-microservice/graphql"}
+	addr := serve(t, server)
+
+	tests := []struct {
+		name    string
+		client  TLSConfig
+		wantErr bool
+	}{
+		{"allowed DNS SAN", certs.config(t, "order", "order"), false},
+		{"allowed URI SAN", certs.config(t, "graphql", "spiffe://go-microservice/graphql"), false},
+		{"SAN not allowed", certs.config(t, "catalog", "catalog"), true},
+		{"no client certificate", TLSConfig{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := check(t, addr, tt.client)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientRejectsUnknownCA(t *testing.T) {
+	addr := serve(t, newTestCerts(t).config(t, "account", "localhost"))
+
+	// Клиент доверяет другому CA и не должен принять сертификат сервера
+	if err := check(t, addr, newTestCerts(t).config(t, "order", "order")); err == nil {
+		t.Error("Check succeeded with server certificate from unknown CA")
+	}
+}
+
+func TestPlaintextWithoutCertificates(t *testing.T) {
+	addr := serve(t, TLSConfig{})
+	if err := check(t, addr, TLSConfig{}); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+}
+
+func TestAllowedPeersRequireCA(t *testing.T) {
+	c := newTestCerts(t).config(t, "account", "localhost")
+	c.CAFile = ""
+	c.AllowedPeers = []string{"order"}
+	if _, err := ServerCredentials(c); err == nil {
+		t.Error("ServerCredentials succeeded with AllowedPeers but without CA")
+	}
+}
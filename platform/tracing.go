@@ -0,0 +1,139 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName - имя, под которым сервисы создают свои span
+const instrumentationName = "go-microservice"
+
+// TracingConfig выбирает, куда отправлять трассировку. Адрес коллектора и остальные
+// параметры OTLP задаются стандартными переменными OTEL_EXPORTER_OTLP_*.
+type TracingConfig struct {
+	// Exporter - none, stdout или otlp
+	Exporter string `envconfig:"OTEL_TRACES_EXPORTER" default:"none"`
+}
+
+// SetupTracing настраивает глобальный TracerProvider и передачу контекста трассировки
+// (W3C traceparent) для сервиса service. Возвращённая функция отправляет накопленные
+// span и вызывается при остановке, после сервера. С exporter none span не записываются,
+// но контекст трассировки всё равно передаётся дальше.
+func SetupTracing(ctx context.Context, service string, c TracingConfig) (shutdown func(), err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch c.Exporter {
+	case "", "none":
+		return func() {}, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "otlp":
+		exporter, err = otlptracegrpc.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q, want none, stdout or otlp", c.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(service)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			slog.Error("tracing shutdown failed", "error", err)
+		}
+	}, nil
+}
+
+// Tracer возвращает tracer для span сервисов
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// EndSpan завершает span, отмечая его ошибкой, если err не nil
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StartQuery начинает span запроса к хранилищу store и замер db_query_duration_seconds.
+// Возвращённая функция завершает оба с результатом запроса и пишет запрос в logger
+// с уровнем debug:
+//
+//	ctx, end := platform.StartQuery(ctx, r.logger, "postgres", "GetAccountById")
+//	defer end(&err)
+func StartQuery(ctx context.Context, logger *slog.Logger, store, operation string) (context.Context, func(err *error)) {
+	ctx, span := Tracer().Start(ctx, store+" "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", store),
+			attribute.String("db.operation.name", operation),
+		),
+	)
+	start := time.Now()
+	return ctx, func(err *error) {
+		observeQuery(store, operation, start, *err)
+		if logger.Enabled(ctx, slog.LevelDebug) {
+			attrs := []slog.Attr{
+				slog.String("store", store),
+				slog.String("operation", operation),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if *err != nil {
+				attrs = append(attrs, slog.String("error", (*err).Error()))
+			}
+			logger.LogAttrs(ctx, slog.LevelDebug, "query", attrs...)
+		}
+		EndSpan(span, *err)
+	}
+}
+
+// QueryObserver замеряет запросы к хранилищу Store через StartQuery. Его встраивают обёртки
+// репозиториев сервисов, чтобы каждый метод сводился к вызову ObserveQuery или ObserveExec.
+type QueryObserver struct {
+	// Store - имя хранилища в метриках и span (postgres, elasticsearch)
+	Store  string
+	Logger *slog.Logger
+}
+
+// ObserveQuery выполняет запрос operation к хранилищу o.Store внутри StartQuery и возвращает его результат
+func ObserveQuery[T any](ctx context.Context, o QueryObserver, operation string, query func(ctx context.Context) (T, error)) (_ T, err error) {
+	ctx, end := StartQuery(ctx, o.Logger, o.Store, operation)
+	defer end(&err)
+	return query(ctx)
+}
+
+// ObserveExec - ObserveQuery для запросов без результата
+func ObserveExec(ctx context.Context, o QueryObserver, operation string, exec func(ctx context.Context) error) (err error) {
+	ctx, end := StartQuery(ctx, o.Logger, o.Store, operation)
+	defer end(&err)
+	return exec(ctx)
+}
@@ -0,0 +1,147 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// recordSpans подменяет глобальный TracerProvider на записывающий span в память
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestStartQuery(t *testing.T) {
+	recorder := recordSpans(t)
+	failed := queryDurationSeconds.WithLabelValues("postgres", "GetAccountById", "error")
+	before := sampleCount(t, failed)
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	func() (err error) {
+		_, end := StartQuery(ctx, DiscardLogger(), "postgres", "GetAccountById")
+		defer end(&err)
+		return errors.New("connection reset")
+	}()
+	parent.End()
+
+	if got := sampleCount(t, failed) - before; got != 1 {
+		t.Errorf("failed query observed %d times, want 1", got)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	query := spans[0]
+	if query.Name() != "postgres GetAccountById" || query.Status().Code != codes.Error {
+		t.Errorf("span = %s with status %v, want postgres GetAccountById with error", query.Name(), query.Status())
+	}
+	if query.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("query span is not a child of the caller span")
+	}
+}
+
+func TestObserveQuery(t *testing.T) {
+	recorder := recordSpans(t)
+	o := QueryObserver{Store: "postgres", Logger: DiscardLogger()}
+
+	got, err := ObserveQuery(context.Background(), o, "GetOrderByID", func(ctx context.Context) (string, error) {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			t.Error("query context has no span")
+		}
+		return "order", nil
+	})
+	if got != "order" || err != nil {
+		t.Errorf("ObserveQuery = %q, %v, want order", got, err)
+	}
+	wantErr := errors.New("connection reset")
+	if err := ObserveExec(context.Background(), o, "DeleteEvents", func(ctx context.Context) error { return wantErr }); err != wantErr {
+		t.Errorf("ObserveExec error = %v, want %v", err, wantErr)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "postgres GetOrderByID" || spans[1].Status().Code != codes.Error {
+		t.Errorf("spans = %v, want postgres GetOrderByID and failed DeleteEvents", spans)
+	}
+}
+
+// echoServiceDesc - сервис с одним unary методом: вызовы Health/Check не попадают в трассировку
+var echoServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.EchoService",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Echo",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			if err := dec(new(healthpb.HealthCheckRequest)); err != nil {
+				return nil, err
+			}
+			return &healthpb.HealthCheckResponse{}, nil
+		},
+	}},
+}
+
+func TestTracePropagatesOverGRPC(t *testing.T) {
+	recorder := recordSpans(t)
+	if _, err := SetupTracing(context.Background(), "test", TracingConfig{Exporter: "none"}); err != nil {
+		t.Fatalf("SetupTracing: %v", err)
+	}
+
+	serv, err := NewServer(TLSConfig{}, DiscardLogger())
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	serv.RegisterService(&echoServiceDesc, struct{}{})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go serv.Serve(lis)
+	t.Cleanup(serv.Stop)
+
+	conn, err := Dial(lis.Addr().String(), TLSConfig{})
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, root := Tracer().Start(context.Background(), "root")
+	err = conn.Invoke(ctx, "/pb.EchoService/Echo", &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	root.End()
+	if err != nil {
+		t.Fatalf("Echo: %v", err)
+	}
+	// Серверный span завершается после отправки ответа, GracefulStop дожидается обработчика
+	serv.GracefulStop()
+
+	var client, server sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		switch s.SpanKind() {
+		case trace.SpanKindClient:
+			client = s
+		case trace.SpanKindServer:
+			server = s
+		}
+	}
+	if client == nil || server == nil {
+		t.Fatalf("got spans %v, want client and server spans", recorder.Ended())
+	}
+	if client.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Error("client span is not a child of the caller span")
+	}
+	if server.SpanContext().TraceID() != root.SpanContext().TraceID() || server.Parent().SpanID() != client.SpanContext().SpanID() {
+		t.Error("server span does not continue the client trace")
+	}
+}